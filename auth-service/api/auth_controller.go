@@ -1,9 +1,11 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/eyupaydin41/auth-service/command"
+	grpcclient "github.com/eyupaydin41/auth-service/grpc"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -23,15 +25,26 @@ func RegisterHandler(cmdHandler *command.CommandHandler) gin.HandlerFunc {
 
 		// Command oluştur
 		userID := uuid.New().String()
+		// Kayıt token'sız yapılır; actor kaydolan kullanıcının kendisidir
+		metadata := metadataFrom(c)
+		if metadata.ActorID == "" {
+			metadata.ActorID = userID
+		}
+
 		cmd := command.RegisterUserCommand{
 			UserID:   userID,
 			Email:    req.Email,
 			Password: req.Password,
+			Metadata: metadata,
 		}
 
 		// Command'ı işle
 		err := cmdHandler.HandleRegisterUser(cmd)
 		if err != nil {
+			if errors.Is(err, grpcclient.ErrConcurrencyConflict) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -67,11 +80,16 @@ func ChangePasswordHandler(cmdHandler *command.CommandHandler) gin.HandlerFunc {
 			UserID:      userID,
 			OldPassword: req.OldPassword,
 			NewPassword: req.NewPassword,
+			Metadata:    metadataFrom(c),
 		}
 
 		// Command'ı işle
 		err := cmdHandler.HandleChangePassword(cmd)
 		if err != nil {
+			if errors.Is(err, grpcclient.ErrConcurrencyConflict) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -102,11 +120,16 @@ func ChangeEmailHandler(cmdHandler *command.CommandHandler) gin.HandlerFunc {
 		cmd := command.ChangeEmailCommand{
 			UserID:   userID,
 			NewEmail: req.NewEmail,
+			Metadata: metadataFrom(c),
 		}
 
 		// Command'ı işle
 		err := cmdHandler.HandleChangeEmail(cmd)
 		if err != nil {
+			if errors.Is(err, grpcclient.ErrConcurrencyConflict) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
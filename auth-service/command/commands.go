@@ -1,6 +1,10 @@
 package command
 
-import "time"
+import (
+	"time"
+
+	"github.com/eyupaydin41/auth-service/domain"
+)
 
 // Command interface - Tüm command'lar bunu implement eder
 type Command interface {
@@ -13,6 +17,8 @@ type RegisterUserCommand struct {
 	UserID   string
 	Email    string
 	Password string
+	// Metadata - Command'ın ürettiği event'lere eklenir (correlation, actor ...)
+	Metadata domain.EventMetadata
 }
 
 func (c RegisterUserCommand) GetAggregateID() string {
@@ -28,6 +34,8 @@ type ChangePasswordCommand struct {
 	UserID      string
 	OldPassword string
 	NewPassword string
+	// Metadata - Command'ın ürettiği event'lere eklenir (correlation, actor ...)
+	Metadata domain.EventMetadata
 }
 
 func (c ChangePasswordCommand) GetAggregateID() string {
@@ -42,6 +50,8 @@ func (c ChangePasswordCommand) GetCommandType() string {
 type ChangeEmailCommand struct {
 	UserID   string
 	NewEmail string
+	// Metadata - Command'ın ürettiği event'lere eklenir (correlation, actor ...)
+	Metadata domain.EventMetadata
 }
 
 func (c ChangeEmailCommand) GetAggregateID() string {
@@ -57,6 +67,8 @@ type DeactivateUserCommand struct {
 	UserID    string
 	Reason    string
 	Timestamp time.Time
+	// Metadata - Command'ın ürettiği event'lere eklenir (correlation, actor ...)
+	Metadata domain.EventMetadata
 }
 
 func (c DeactivateUserCommand) GetAggregateID() string {
@@ -73,6 +85,8 @@ type RecordLoginCommand struct {
 	IPAddress string
 	UserAgent string
 	Timestamp time.Time
+	// Metadata - Command'ın ürettiği event'lere eklenir (correlation, actor ...)
+	Metadata domain.EventMetadata
 }
 
 func (c RecordLoginCommand) GetAggregateID() string {
@@ -1,24 +1,29 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/eyupaydin41/auth-service/domain"
 	"github.com/eyupaydin41/auth-service/event"
 	grpcclient "github.com/eyupaydin41/auth-service/grpc"
+	"github.com/eyupaydin41/auth-service/model"
+	pb "github.com/eyupaydin41/auth-service/proto"
+	"github.com/eyupaydin41/auth-service/repository"
 )
 
 // CommandHandler - Command'ları işler ve aggregate üzerinde çalışır
 type CommandHandler struct {
-	publisher        *event.KafkaProducer
+	outboxRepo       *repository.OutboxRepository // Kafka'ya gidecek event'ler (relay gönderir)
 	eventStoreClient *grpcclient.EventStoreClient // gRPC client (yeni!)
 }
 
 // NewCommandHandler - Yeni command handler oluşturur
-func NewCommandHandler(publisher *event.KafkaProducer, eventStoreClient *grpcclient.EventStoreClient) *CommandHandler {
+func NewCommandHandler(outboxRepo *repository.OutboxRepository, eventStoreClient *grpcclient.EventStoreClient) *CommandHandler {
 	return &CommandHandler{
-		publisher:        publisher,
+		outboxRepo:       outboxRepo,
 		eventStoreClient: eventStoreClient,
 	}
 }
@@ -36,13 +41,12 @@ func (h *CommandHandler) HandleRegisterUser(cmd RegisterUserCommand) error {
 		return fmt.Errorf("failed to register user: %w", err)
 	}
 
-	// 3. Event'leri Kafka'ya publish et
-	h.publishEvents(aggregate.GetUncommittedChanges())
-
-	// 4. Aggregate'i temizle
-	aggregate.MarkChangesAsCommitted()
+	// 3. Event'leri event-store'a yaz (stream henüz olmamalı) ve outbox'a kaydet
+	if err := h.commitEvents(aggregate, grpcclient.ExpectNoStream(), cmd.Metadata); err != nil {
+		return fmt.Errorf("failed to register user: %w", err)
+	}
 
-	log.Printf("User registered successfully, events published to Kafka: %s", cmd.UserID)
+	log.Printf("User registered successfully, events queued in outbox: %s", cmd.UserID)
 	return nil
 }
 
@@ -55,12 +59,13 @@ func (h *CommandHandler) HandleChangePassword(cmd ChangePasswordCommand) error {
 	// Snapshot varsa: snapshot + sonraki eventler (HIZLI!)
 	// Snapshot yoksa: tüm eventler (yavaş ama çalışır)
 	log.Printf("🔄 Loading aggregate %s with snapshot from event-store via gRPC...", cmd.UserID)
-	aggregate, err := h.eventStoreClient.GetAggregateWithSnapshot(cmd.UserID)
+	aggregate, err := h.eventStoreClient.GetAggregateWithSnapshot(cmd.Metadata.TenantID, cmd.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to load aggregate with snapshot: %w", err)
 	}
 
 	log.Printf("✅ Aggregate loaded: Status=%s, Email=%s, Version=%d", aggregate.Status, aggregate.Email, aggregate.Version)
+	loadedVersion := aggregate.Version
 
 	// 2. Command'ı uygula
 	err = aggregate.ChangePassword(cmd.OldPassword, cmd.NewPassword)
@@ -68,9 +73,10 @@ func (h *CommandHandler) HandleChangePassword(cmd ChangePasswordCommand) error {
 		return fmt.Errorf("failed to change password: %w", err)
 	}
 
-	// 3. Yeni event'leri Kafka'ya publish et
-	h.publishEvents(aggregate.GetUncommittedChanges())
-	aggregate.MarkChangesAsCommitted()
+	// 3. Yeni event'leri yaz - aggregate yüklendikten sonra stream ilerlediyse conflict döner
+	if err := h.commitEvents(aggregate, grpcclient.ExpectVersion(loadedVersion), cmd.Metadata); err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
 
 	log.Printf("Password changed successfully for user: %s", cmd.UserID)
 	return nil
@@ -83,12 +89,13 @@ func (h *CommandHandler) HandleChangeEmail(cmd ChangeEmailCommand) error {
 
 	// 1. Snapshot kullanarak aggregate'i yükle
 	log.Printf("🔄 Loading aggregate %s with snapshot from event-store via gRPC...", cmd.UserID)
-	aggregate, err := h.eventStoreClient.GetAggregateWithSnapshot(cmd.UserID)
+	aggregate, err := h.eventStoreClient.GetAggregateWithSnapshot(cmd.Metadata.TenantID, cmd.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to load aggregate with snapshot: %w", err)
 	}
 
 	log.Printf("✅ Aggregate loaded: Status=%s, Email=%s, Version=%d", aggregate.Status, aggregate.Email, aggregate.Version)
+	loadedVersion := aggregate.Version
 
 	// 2. Command'ı uygula
 	err = aggregate.ChangeEmail(cmd.NewEmail)
@@ -96,18 +103,73 @@ func (h *CommandHandler) HandleChangeEmail(cmd ChangeEmailCommand) error {
 		return fmt.Errorf("failed to change email: %w", err)
 	}
 
-	// 3. Event'leri Kafka'ya publish et
-	h.publishEvents(aggregate.GetUncommittedChanges())
-	aggregate.MarkChangesAsCommitted()
+	// 3. Yeni event'leri yaz - aggregate yüklendikten sonra stream ilerlediyse conflict döner
+	if err := h.commitEvents(aggregate, grpcclient.ExpectVersion(loadedVersion), cmd.Metadata); err != nil {
+		return fmt.Errorf("failed to change email: %w", err)
+	}
 
 	log.Printf("Email changed successfully for user: %s", cmd.UserID)
 	return nil
 }
 
-// publishEvents - Event'leri Kafka'ya publish eder
-func (h *CommandHandler) publishEvents(events []domain.DomainEvent) {
-	for _, event := range events {
-		h.publisher.Publish(event.GetEventType(), event)
-		log.Printf("Published event: %s for aggregate: %s", event.GetEventType(), event.GetAggregateID())
+// commitEvents - Uncommitted event'leri outbox'a appending olarak yazar, sonra expected version
+// kontrolü ile event-store'a append eder ve başarılıysa satırları relay'e bırakır (Release)
+// Outbox satırı append'ten önce commit edildiği için event-store'daki her event'in bir outbox
+// satırı vardır. Conflict'te satırlar silinir; sonucu bilinmeyen append'leri (timeout, Release
+// hatası, çökme) relay event-store'a bakarak sonuçlandırır.
+// nil dönüldüğünde event'ler kalıcıdır ve relay onları Kafka'ya taşıyacaktır
+// metadata hem outbox envelope'una hem event-store'a aynı şekilde yazılır
+func (h *CommandHandler) commitEvents(aggregate *domain.UserAggregate, expected *pb.ExpectedVersion, metadata domain.EventMetadata) error {
+	changes := aggregate.GetUncommittedChanges()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	messages := make([]*model.OutboxMessage, 0, len(changes))
+	ids := make([]string, 0, len(changes))
+	now := time.Now()
+	for _, change := range changes {
+		eventID, envelope, err := event.NewEnvelope(change.GetEventType(), change, metadata)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, &model.OutboxMessage{
+			ID:            eventID,
+			AggregateID:   change.GetAggregateID(),
+			Version:       change.GetVersion(),
+			TenantID:      metadata.TenantID,
+			EventType:     change.GetEventType(),
+			Payload:       string(envelope),
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		ids = append(ids, eventID)
 	}
+
+	if err := h.outboxRepo.Reserve(messages); err != nil {
+		return err
+	}
+
+	if _, err := h.eventStoreClient.AppendEvents(aggregate.ID, expected, changes, metadata); err != nil {
+		// Conflict'te event-store hiçbir şey yazmamıştır; diğer hatalarda yazılmış olabilir, relay karar verir
+		if errors.Is(err, grpcclient.ErrConcurrencyConflict) {
+			if discardErr := h.outboxRepo.Discard(ids); discardErr != nil {
+				log.Printf("failed to discard outbox messages of aggregate %s, relay will resolve them: %v", aggregate.ID, discardErr)
+			}
+		}
+		return err
+	}
+
+	if err := h.outboxRepo.Release(ids); err != nil {
+		// Event'ler event-store'da; satırları relay outboxAppendTimeout sonra pending yapar
+		log.Printf("failed to release outbox messages of aggregate %s, relay will resolve them: %v", aggregate.ID, err)
+	}
+
+	for _, change := range changes {
+		log.Printf("Queued event in outbox: %s for aggregate: %s", change.GetEventType(), change.GetAggregateID())
+	}
+
+	aggregate.MarkChangesAsCommitted()
+	return nil
 }
@@ -4,18 +4,20 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // UserAggregate - User'ın domain logic'ini içeren aggregate root
+// JSON alanları event-store'un user reducer'ının state'iyle aynıdır (GetAggregateWithSnapshot)
 type UserAggregate struct {
-	ID           string
-	Email        string
-	PasswordHash string
-	Status       string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	Version      uint32
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"password_hash"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Version      uint32    `json:"version"`
 
 	// Henüz persist edilmemiş event'lar
 	uncommittedChanges []DomainEvent
@@ -73,6 +75,7 @@ func (u *UserAggregate) Register(email, password string) error {
 	// Event oluştur ve uygula
 	event := UserCreatedEvent{
 		BaseEvent: BaseEvent{
+			EventID:     uuid.New().String(),
 			AggregateID: u.ID,
 			Timestamp:   time.Now(),
 			Version:     u.Version + 1,
@@ -117,6 +120,7 @@ func (u *UserAggregate) ChangePassword(oldPassword, newPassword string) error {
 	// Event oluştur ve uygula
 	event := PasswordChangedEvent{
 		BaseEvent: BaseEvent{
+			EventID:     uuid.New().String(),
 			AggregateID: u.ID,
 			Timestamp:   time.Now(),
 			Version:     u.Version + 1,
@@ -148,6 +152,7 @@ func (u *UserAggregate) ChangeEmail(newEmail string) error {
 	// Event oluştur ve uygula
 	event := EmailChangedEvent{
 		BaseEvent: BaseEvent{
+			EventID:     uuid.New().String(),
 			AggregateID: u.ID,
 			Timestamp:   time.Now(),
 			Version:     u.Version + 1,
@@ -170,6 +175,7 @@ func (u *UserAggregate) Deactivate(reason string) error {
 	// Event oluştur ve uygula
 	event := UserDeactivatedEvent{
 		BaseEvent: BaseEvent{
+			EventID:     uuid.New().String(),
 			AggregateID: u.ID,
 			Timestamp:   time.Now(),
 			Version:     u.Version + 1,
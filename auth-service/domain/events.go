@@ -2,8 +2,16 @@ package domain
 
 import "time"
 
+// SchemaVersion - Event payload'larının event-store schema registry'deki versiyonu
+// Payload şekli geriye uyumsuz değiştiğinde event-store'a yeni şema eklenip bu değer artırılır
+const SchemaVersion = 1
+
+// AggregateType - Event-store'da bu servisin yazdığı stream'lerin aggregate tipi
+const AggregateType = "user"
+
 // DomainEvent interface - Tüm domain event'ları bunu implement eder
 type DomainEvent interface {
+	GetEventID() string
 	GetEventType() string
 	GetAggregateID() string
 	GetTimestamp() time.Time
@@ -11,12 +19,19 @@ type DomainEvent interface {
 }
 
 // BaseEvent - Tüm event'ların ortak alanları
+// EventID event oluşturulurken bir kez atanır; retry/redelivery'de aynı kalır,
+// event-store tekrar gelen event'leri bu ID ile ayıklar
 type BaseEvent struct {
+	EventID     string    `json:"event_id"`
 	AggregateID string    `json:"aggregate_id"`
 	Timestamp   time.Time `json:"timestamp"`
 	Version     uint32    `json:"version"`
 }
 
+func (e BaseEvent) GetEventID() string {
+	return e.EventID
+}
+
 func (e BaseEvent) GetAggregateID() string {
 	return e.AggregateID
 }
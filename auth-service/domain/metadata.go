@@ -0,0 +1,16 @@
+package domain
+
+// EventMetadata - Event'in hangi istek, kullanıcı ve servisten doğduğu
+// Domain event'in payload'una girmez; envelope'ta ve event-store'da ayrı kolonlarda taşınır
+type EventMetadata struct {
+	// CorrelationID - Aynı kullanıcı isteğinden doğan tüm event'lerde ortak (X-Correlation-ID)
+	CorrelationID string `json:"correlation_id"`
+	// CausationID - Event'e doğrudan sebep olan isteğin ID'si (X-Request-ID)
+	CausationID string `json:"causation_id"`
+	// ActorID - İşlemi yapan kullanıcı (JWT'deki user_id)
+	ActorID string `json:"actor_id"`
+	// SourceService - Event'i üreten servis
+	SourceService string `json:"source_service"`
+	// TenantID - Aggregate'in ait olduğu tenant; envelope'a girmez, Kafka header'ında ve gRPC metadata'sında taşınır
+	TenantID string `json:"-"`
+}
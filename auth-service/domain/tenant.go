@@ -0,0 +1,13 @@
+package domain
+
+// DefaultTenantID - Tenant göndermeyen isteklerin tenant'ı
+// event-store ve query-service'teki model.DefaultTenantID ile aynı değer olmalıdır
+const DefaultTenantID = "default"
+
+// NormalizeTenantID - Boş tenant default tenant'tır
+func NormalizeTenantID(tenantID string) string {
+	if tenantID == "" {
+		return DefaultTenantID
+	}
+	return tenantID
+}
@@ -0,0 +1,155 @@
+package event
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/eyupaydin41/auth-service/model"
+	"github.com/eyupaydin41/auth-service/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	outboxBatchSize       = 100
+	outboxDeliveryTimeout = 10 * time.Second
+	outboxMaxBackoff      = 5 * time.Minute
+	// outboxAppendTimeout - Appending bir satırın append sonucunun beklendiği süre; event-store
+	// client'ının gRPC timeout'undan uzun olmalı ki hâlâ süren bir append sonuçlandırılmasın
+	outboxAppendTimeout = time.Minute
+)
+
+// EventLookup - Sonucu bilinmeyen append'lerin event-store'a yazılıp yazılmadığını kontrol eder
+type EventLookup interface {
+	HasEvent(tenantID, aggregateID, eventID string) (bool, error)
+}
+
+// OutboxRelay - Outbox'taki pending mesajları Kafka'ya taşır
+// Mesaj ancak broker teslim ettiğini onayladıktan sonra sent olarak işaretlenir.
+// Command'ın sonuçlandıramadığı appending satırlar (append sonrası çökme, timeout) event-store'da
+// varsa pending yapılır, yoksa silinir
+type OutboxRelay struct {
+	repo         *repository.OutboxRepository
+	producer     *KafkaProducer
+	events       EventLookup
+	pollInterval time.Duration
+	stop         chan struct{}
+}
+
+func NewOutboxRelay(repo *repository.OutboxRepository, producer *KafkaProducer, events EventLookup, pollInterval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		repo:         repo,
+		producer:     producer,
+		events:       events,
+		pollInterval: pollInterval,
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start - Relay döngüsünü başlatır (goroutine içinde çağrılmalı)
+func (r *OutboxRelay) Start() {
+	log.Printf("outbox relay started (poll interval: %s)", r.pollInterval)
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			log.Println("outbox relay stopped")
+			return
+		case <-ticker.C:
+			if err := r.resolveAppending(); err != nil {
+				log.Printf("outbox relay error: %v", err)
+			}
+			if err := r.relayPending(); err != nil {
+				log.Printf("outbox relay error: %v", err)
+			}
+		}
+	}
+}
+
+// Stop - Relay döngüsünü durdurur
+func (r *OutboxRelay) Stop() {
+	close(r.stop)
+}
+
+// resolveAppending - outboxAppendTimeout'tan eski appending satırları event-store'a sorarak sonuçlandırır
+// Event-store'a ulaşılamazsa satırlar kalır ve sonraki poll'da tekrar denenir
+func (r *OutboxRelay) resolveAppending() error {
+	messages, err := r.repo.FindStaleAppending(time.Now().Add(-outboxAppendTimeout), outboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		stored, err := r.events.HasEvent(msg.TenantID, msg.AggregateID, msg.ID)
+		if err != nil {
+			return fmt.Errorf("failed to look up event %s: %w", msg.ID, err)
+		}
+
+		if stored {
+			err = r.repo.Release([]string{msg.ID})
+		} else {
+			err = r.repo.Discard([]string{msg.ID})
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("outbox: resolved appending event %s (%s) for aggregate %s, stored in event-store: %t",
+			msg.ID, msg.EventType, msg.AggregateID, stored)
+	}
+	return nil
+}
+
+// relayPending - Gönderilebilir mesaj kalmayana ya da bir gönderim başarısız olana kadar batch'leri gönderir
+// Her batch'te aggregate başına sadece en eski gönderilmemiş mesaj seçilir (ProcessPending);
+// aynı aggregate'in sonraki event'leri bir sonraki batch'e kalır
+func (r *OutboxRelay) relayPending() error {
+	for {
+		sent, err := r.relayBatch()
+		if err != nil || sent == 0 {
+			return err
+		}
+	}
+}
+
+// relayBatch - Bir batch pending mesajı sırayla gönderir; gönderilen mesaj sayısını döner
+// Bir mesaj başarısız olursa batch durdurulur ve 0 dönülür (backoff süresi poll'a bırakılır)
+func (r *OutboxRelay) relayBatch() (int, error) {
+	sent := 0
+	err := r.repo.ProcessPending(outboxBatchSize, func(tx *gorm.DB, messages []model.OutboxMessage) error {
+		for _, msg := range messages {
+			if err := r.producer.PublishAndWait([]byte(msg.Payload), msg.TenantID, outboxDeliveryTimeout); err != nil {
+				attempts := msg.Attempts + 1
+				nextAttempt := time.Now().Add(backoff(attempts))
+				log.Printf("outbox: failed to publish %s (%s), attempt %d, retrying at %s: %v",
+					msg.ID, msg.EventType, attempts, nextAttempt.Format(time.RFC3339), err)
+				sent = 0
+				return r.repo.MarkFailed(tx, msg.ID, attempts, err, nextAttempt)
+			}
+
+			if err := r.repo.MarkSent(tx, msg.ID); err != nil {
+				return err
+			}
+			sent++
+			log.Printf("outbox: published event %s (%s) for aggregate %s", msg.ID, msg.EventType, msg.AggregateID)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sent, nil
+}
+
+// backoff - Exponential backoff (1s, 2s, 4s, ... en fazla outboxMaxBackoff)
+func backoff(attempts int) time.Duration {
+	d := time.Second
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return d
+}
@@ -2,11 +2,18 @@ package event
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/eyupaydin41/auth-service/domain"
+	"github.com/google/uuid"
 )
 
+// TenantHeader - Mesajın tenant'ını taşıyan Kafka header'ı (event-store ve query-service okur)
+const TenantHeader = "tenant-id"
+
 type KafkaProducer struct {
 	producer *kafka.Producer
 	topic    string
@@ -24,16 +31,52 @@ func NewKafkaProducer(broker, topic string) *KafkaProducer {
 	}
 }
 
-func (kp *KafkaProducer) Publish(eventType string, payload interface{}) {
+// identifiedEvent - Kendi event ID'sini taşıyan payload'lar
+type identifiedEvent interface {
+	GetEventID() string
+}
+
+// NewEnvelope - Event'i {"event_id", "type", "schema_version", "aggregate_type", "metadata", "data"} envelope'una çevirir
+// Payload kendi ID'sini taşıyorsa o kullanılır, yoksa burada bir kez üretilir
+func NewEnvelope(eventType string, payload interface{}, metadata domain.EventMetadata) (string, []byte, error) {
+	eventID := ""
+	if e, ok := payload.(identifiedEvent); ok {
+		eventID = e.GetEventID()
+	}
+	if eventID == "" {
+		eventID = uuid.New().String()
+	}
+
 	data := map[string]interface{}{
-		"type": eventType,
-		"data": payload,
+		"event_id":       eventID,
+		"type":           eventType,
+		"schema_version": domain.SchemaVersion,
+		"aggregate_type": domain.AggregateType,
+		"metadata":       metadata,
+		"data":           payload,
 	}
 
-	value, _ := json.Marshal(data)
-	err := kp.producer.Produce(&kafka.Message{
+	value, err := json.Marshal(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	return eventID, value, nil
+}
+
+// Publish - Event'i envelope ile publish eder (fire-and-forget)
+// Producer retry'larında mesaj aynı ID ile gider, event-store tekrarları ayıklar
+func (kp *KafkaProducer) Publish(eventType string, payload interface{}, metadata domain.EventMetadata) {
+	_, value, err := NewEnvelope(eventType, payload, metadata)
+	if err != nil {
+		log.Printf("failed to build message: %v", err)
+		return
+	}
+
+	err = kp.producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &kp.topic, Partition: kafka.PartitionAny},
 		Value:          value,
+		Headers:        tenantHeaders(metadata.TenantID),
 	}, nil)
 
 	if err != nil {
@@ -41,6 +84,43 @@ func (kp *KafkaProducer) Publish(eventType string, payload interface{}) {
 	}
 }
 
+// PublishAndWait - Hazır envelope'u gönderir ve broker'dan delivery report gelene kadar bekler
+// Outbox relay, mesajı ancak bu metod nil dönerse gönderildi olarak işaretler
+func (kp *KafkaProducer) PublishAndWait(value []byte, tenantID string, timeout time.Duration) error {
+	deliveryChan := make(chan kafka.Event, 1)
+
+	err := kp.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &kp.topic, Partition: kafka.PartitionAny},
+		Value:          value,
+		Headers:        tenantHeaders(tenantID),
+	}, deliveryChan)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	select {
+	case e := <-deliveryChan:
+		msg, ok := e.(*kafka.Message)
+		if !ok {
+			return fmt.Errorf("unexpected delivery event: %v", e)
+		}
+		if msg.TopicPartition.Error != nil {
+			return fmt.Errorf("delivery failed: %w", msg.TopicPartition.Error)
+		}
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("delivery report not received within %s", timeout)
+	}
+}
+
+// tenantHeaders - Tenant boşsa header eklenmez (consumer default tenant'ı kullanır)
+func tenantHeaders(tenantID string) []kafka.Header {
+	if tenantID == "" {
+		return nil
+	}
+	return []kafka.Header{{Key: TenantHeader, Value: []byte(tenantID)}}
+}
+
 func (kp *KafkaProducer) Close() {
 	kp.producer.Flush(5000)
 	kp.producer.Close()
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// conflictReason - Event-store'un conflict durumunda gönderdiği ErrorInfo.Reason
+const conflictReason = "WRONG_EXPECTED_VERSION"
+
+// ErrConcurrencyConflict - errors.Is ile conflict kontrolü için
+var ErrConcurrencyConflict = errors.New("concurrency conflict")
+
+// ConcurrencyConflictError - Aggregate yüklendikten sonra stream başka bir command ile ilerlemiş
+type ConcurrencyConflictError struct {
+	AggregateID     string
+	ExpectedVersion uint32
+	ActualVersion   uint32
+}
+
+func (e *ConcurrencyConflictError) Error() string {
+	return fmt.Sprintf("aggregate %s was modified concurrently: expected version %d, actual version %d",
+		e.AggregateID, e.ExpectedVersion, e.ActualVersion)
+}
+
+func (e *ConcurrencyConflictError) Unwrap() error {
+	return ErrConcurrencyConflict
+}
+
+// conflictFromStatus - ABORTED status'u ConcurrencyConflictError'a çevirir
+// Conflict değilse nil döner
+func conflictFromStatus(err error) *ConcurrencyConflictError {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Aborted {
+		return nil
+	}
+
+	conflict := &ConcurrencyConflictError{}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Reason != conflictReason {
+			continue
+		}
+
+		conflict.AggregateID = info.Metadata["aggregate_id"]
+		if v, err := strconv.ParseUint(info.Metadata["expected_version"], 10, 32); err == nil {
+			conflict.ExpectedVersion = uint32(v)
+		}
+		if v, err := strconv.ParseUint(info.Metadata["actual_version"], 10, 32); err == nil {
+			conflict.ActualVersion = uint32(v)
+		}
+	}
+
+	return conflict
+}
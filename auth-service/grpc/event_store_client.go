@@ -11,8 +11,20 @@ import (
 	pb "github.com/eyupaydin41/auth-service/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
+// tenantMetadataKey - Event-store'un isteğin tenant'ını okuduğu metadata anahtarı
+const tenantMetadataKey = "x-tenant-id"
+
+// tenantContext - Tenant boşsa event-store default tenant'ı kullanır
+func tenantContext(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, tenantMetadataKey, tenantID)
+}
+
 // EventStoreClient - gRPC client wrapper
 // HTTP client'a benzer ama type-safe ve daha performanslı
 type EventStoreClient struct {
@@ -46,12 +58,13 @@ func NewEventStoreClient(address string) (*EventStoreClient, error) {
 
 // GetAggregateHistory - Aggregate'in tüm event history'sini getir
 // HTTP karşılığı: GET /events/aggregate/:id
-func (c *EventStoreClient) GetAggregateHistory(aggregateID string) ([]domain.DomainEvent, error) {
+func (c *EventStoreClient) GetAggregateHistory(tenantID, aggregateID string) ([]domain.DomainEvent, error) {
 	log.Printf("gRPC Call: GetAggregateEvents for aggregate_id=%s", aggregateID)
 
 	// Context oluştur (timeout için)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	ctx = tenantContext(ctx, tenantID)
 
 	// gRPC call yap
 	// HTTP'de: resp, _ := http.Get("http://event-store:8090/events/aggregate/" + id)
@@ -80,6 +93,28 @@ func (c *EventStoreClient) GetAggregateHistory(aggregateID string) ([]domain.Dom
 	return domainEvents, nil
 }
 
+// HasEvent - Event ID'si aggregate'in stream'inde var mı? (outbox relay'i sonucu bilinmeyen
+// append'leri bununla sonuçlandırır)
+func (c *EventStoreClient) HasEvent(tenantID, aggregateID, eventID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = tenantContext(ctx, tenantID)
+
+	resp, err := c.client.GetAggregateEvents(ctx, &pb.GetAggregateEventsRequest{
+		AggregateId: aggregateID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("gRPC call failed: %w", err)
+	}
+
+	for _, pbEvent := range resp.Events {
+		if pbEvent.Id == eventID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // pbEventToDomainEvent - Protobuf event'i domain event'e çevir
 func (c *EventStoreClient) pbEventToDomainEvent(pbEvent *pb.Event) (domain.DomainEvent, error) {
 	// Timestamp parse et
@@ -101,6 +136,7 @@ func (c *EventStoreClient) pbEventToDomainEvent(pbEvent *pb.Event) (domain.Domai
 
 		return domain.UserCreatedEvent{
 			BaseEvent: domain.BaseEvent{
+				EventID:     pbEvent.Id,
 				AggregateID: pbEvent.AggregateId,
 				Timestamp:   timestamp,
 				Version:     uint32(pbEvent.Version),
@@ -119,6 +155,7 @@ func (c *EventStoreClient) pbEventToDomainEvent(pbEvent *pb.Event) (domain.Domai
 
 		return domain.PasswordChangedEvent{
 			BaseEvent: domain.BaseEvent{
+				EventID:     pbEvent.Id,
 				AggregateID: pbEvent.AggregateId,
 				Timestamp:   timestamp,
 				Version:     uint32(pbEvent.Version),
@@ -137,6 +174,7 @@ func (c *EventStoreClient) pbEventToDomainEvent(pbEvent *pb.Event) (domain.Domai
 
 		return domain.EmailChangedEvent{
 			BaseEvent: domain.BaseEvent{
+				EventID:     pbEvent.Id,
 				AggregateID: pbEvent.AggregateId,
 				Timestamp:   timestamp,
 				Version:     uint32(pbEvent.Version),
@@ -155,6 +193,7 @@ func (c *EventStoreClient) pbEventToDomainEvent(pbEvent *pb.Event) (domain.Domai
 
 		return domain.UserDeactivatedEvent{
 			BaseEvent: domain.BaseEvent{
+				EventID:     pbEvent.Id,
 				AggregateID: pbEvent.AggregateId,
 				Timestamp:   timestamp,
 				Version:     uint32(pbEvent.Version),
@@ -168,12 +207,13 @@ func (c *EventStoreClient) pbEventToDomainEvent(pbEvent *pb.Event) (domain.Domai
 }
 
 // GetAggregateWithSnapshot - Snapshot kullanarak aggregate state'ini getir
-func (c *EventStoreClient) GetAggregateWithSnapshot(aggregateID string) (*domain.UserAggregate, error) {
+func (c *EventStoreClient) GetAggregateWithSnapshot(tenantID, aggregateID string) (*domain.UserAggregate, error) {
 	log.Printf("gRPC Call: GetAggregateWithSnapshot for aggregate_id=%s", aggregateID)
 
 	// Context oluştur
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	ctx = tenantContext(ctx, tenantID)
 
 	// gRPC call yap
 	resp, err := c.client.GetAggregateWithSnapshot(ctx, &pb.GetAggregateWithSnapshotRequest{
@@ -198,6 +238,72 @@ func (c *EventStoreClient) GetAggregateWithSnapshot(aggregateID string) (*domain
 	return aggregate, nil
 }
 
+// ExpectAny - Versiyon kontrolü yapmadan ekle
+func ExpectAny() *pb.ExpectedVersion {
+	return &pb.ExpectedVersion{Kind: pb.ExpectedVersionKind_EXPECTED_VERSION_ANY}
+}
+
+// ExpectNoStream - Aggregate'in henüz hiç event'i olmamalı (yeni kayıt)
+func ExpectNoStream() *pb.ExpectedVersion {
+	return &pb.ExpectedVersion{Kind: pb.ExpectedVersionKind_EXPECTED_VERSION_NO_STREAM}
+}
+
+// ExpectVersion - Aggregate tam olarak bu versiyonda olmalı
+func ExpectVersion(version uint32) *pb.ExpectedVersion {
+	return &pb.ExpectedVersion{Kind: pb.ExpectedVersionKind_EXPECTED_VERSION_EXACT, Version: version}
+}
+
+// AppendEvents - Event'leri optimistic concurrency ile event-store'a yazar
+// Stream beklenen versiyonda değilse *ConcurrencyConflictError döner
+func (c *EventStoreClient) AppendEvents(aggregateID string, expected *pb.ExpectedVersion, events []domain.DomainEvent, eventMetadata domain.EventMetadata) (uint32, error) {
+	log.Printf("gRPC Call: AppendEvents for aggregate_id=%s (%d events)", aggregateID, len(events))
+
+	pbEvents := make([]*pb.NewEvent, 0, len(events))
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal event %s: %w", event.GetEventType(), err)
+		}
+
+		pbEvents = append(pbEvents, &pb.NewEvent{
+			Id:            event.GetEventID(),
+			EventType:     event.GetEventType(),
+			Timestamp:     event.GetTimestamp().Format(time.RFC3339Nano),
+			DataJson:      string(data),
+			SchemaVersion: domain.SchemaVersion,
+			Metadata: &pb.EventMetadata{
+				CorrelationId: eventMetadata.CorrelationID,
+				CausationId:   eventMetadata.CausationID,
+				ActorId:       eventMetadata.ActorID,
+				SourceService: eventMetadata.SourceService,
+			},
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = tenantContext(ctx, eventMetadata.TenantID)
+
+	resp, err := c.client.AppendEvents(ctx, &pb.AppendEventsRequest{
+		AggregateId:     aggregateID,
+		ExpectedVersion: expected,
+		Events:          pbEvents,
+		AggregateType:   domain.AggregateType,
+	})
+	if err != nil {
+		if conflict := conflictFromStatus(err); conflict != nil {
+			if conflict.AggregateID == "" {
+				conflict.AggregateID = aggregateID
+			}
+			return 0, conflict
+		}
+		return 0, fmt.Errorf("gRPC call failed: %w", err)
+	}
+
+	log.Printf("gRPC Response: Appended versions %d..%d", resp.FirstVersion, resp.LastVersion)
+	return resp.LastVersion, nil
+}
+
 // Close - Connection'ı kapat
 func (c *EventStoreClient) Close() error {
 	return c.conn.Close()
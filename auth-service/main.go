@@ -3,12 +3,14 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/eyupaydin41/auth-service/api"
 	"github.com/eyupaydin41/auth-service/command"
 	"github.com/eyupaydin41/auth-service/config"
 	"github.com/eyupaydin41/auth-service/event"
 	grpcclient "github.com/eyupaydin41/auth-service/grpc"
+	"github.com/eyupaydin41/auth-service/repository"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,10 +18,18 @@ import (
 func main() {
 	config.LoadEnv()
 
+	// Auth DB (transactional outbox için)
+	db := config.NewPostgresDB()
+	outboxRepo := repository.NewOutboxRepository(db)
+	if err := outboxRepo.CreateTable(); err != nil {
+		log.Fatalf("Failed to create outbox table: %v", err)
+	}
+
 	// Kafka Producer (event publishing için)
 	kafkaBroker := os.Getenv("KAFKA_BROKER")
 	kafkaTopic := os.Getenv("KAFKA_TOPIC")
 	producer := event.NewKafkaProducer(kafkaBroker, kafkaTopic)
+	defer producer.Close()
 
 	// gRPC Client (event-store'dan aggregate load etmek için)
 	// HTTP'de: http.DefaultClient kullanırdık
@@ -36,10 +46,22 @@ func main() {
 	}
 	defer eventStoreClient.Close()
 
+	// Outbox relay - pending event'leri Kafka'ya taşır, sonucu bilinmeyen append'leri event-store'a sorar
+	pollInterval := time.Second
+	if v := os.Getenv("OUTBOX_POLL_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			pollInterval = d
+		}
+	}
+	relay := event.NewOutboxRelay(outboxRepo, producer, eventStoreClient, pollInterval)
+	go relay.Start()
+	defer relay.Stop()
+
 	// Command Handler
-	cmdHandler := command.NewCommandHandler(producer, eventStoreClient)
+	cmdHandler := command.NewCommandHandler(outboxRepo, eventStoreClient)
 
 	r := gin.Default()
+	r.Use(api.RequestMetadata("auth-service"))
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "OK"})
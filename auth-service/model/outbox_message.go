@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	// OutboxStatusAppending - Satır yazıldı, event-store append'inin sonucu henüz bilinmiyor
+	OutboxStatusAppending = "appending"
+	OutboxStatusPending   = "pending"
+	OutboxStatusSent      = "sent"
+)
+
+// OutboxMessage - Kafka'ya gönderilmeyi bekleyen event (transactional outbox)
+// Command event-store'a append etmeden önce appending olarak yazılır; append başarılıysa
+// pending olur ve relay goroutine'i Kafka'ya taşır
+type OutboxMessage struct {
+	ID            string    `gorm:"primaryKey"` // Event ID (producer'ın atadığı)
+	AggregateID   string    `gorm:"index;not null"`
+	Version       uint32    `gorm:"not null;default:0"`  // Aggregate içindeki sıra (aynı created_at'te)
+	TenantID      string    `gorm:"not null;default:''"` // Kafka'da tenant-id header'ı olarak gider
+	EventType     string    `gorm:"not null"`
+	Payload       string    `gorm:"type:text;not null"` // Kafka'ya gidecek envelope JSON
+	Status        string    `gorm:"type:varchar(20);default:'pending';not null;index"`
+	Attempts      int       `gorm:"not null;default:0"`
+	LastError     string    `gorm:"type:text"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	CreatedAt     time.Time `gorm:"not null"`
+	SentAt        *time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
@@ -26,6 +26,11 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	EventStoreService_GetAggregateEvents_FullMethodName       = "/eventstore.EventStoreService/GetAggregateEvents"
 	EventStoreService_GetAggregateWithSnapshot_FullMethodName = "/eventstore.EventStoreService/GetAggregateWithSnapshot"
+	EventStoreService_AppendEvents_FullMethodName             = "/eventstore.EventStoreService/AppendEvents"
+	EventStoreService_ReadAll_FullMethodName                  = "/eventstore.EventStoreService/ReadAll"
+	EventStoreService_SubscribeAll_FullMethodName             = "/eventstore.EventStoreService/SubscribeAll"
+	EventStoreService_SubscribeToStream_FullMethodName        = "/eventstore.EventStoreService/SubscribeToStream"
+	EventStoreService_ReadStream_FullMethodName               = "/eventstore.EventStoreService/ReadStream"
 )
 
 // EventStoreServiceClient is the client API for EventStoreService service.
@@ -42,6 +47,20 @@ type EventStoreServiceClient interface {
 	// Snapshot varsa: snapshot + sonraki eventler
 	// Snapshot yoksa: tüm eventler
 	GetAggregateWithSnapshot(ctx context.Context, in *GetAggregateWithSnapshotRequest, opts ...grpc.CallOption) (*GetAggregateWithSnapshotResponse, error)
+	// Optimistic concurrency ile event ekler
+	// Stream'in versiyonu expected_version ile uyuşmazsa ABORTED döner
+	AppendEvents(ctx context.Context, in *AppendEventsRequest, opts ...grpc.CallOption) (*AppendEventsResponse, error)
+	// HTTP karşılığı: GET /events/replay?from_position=
+	// Tüm stream'lerdeki event'leri global position sırasıyla okur
+	ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error)
+	// Tüm event'lere from_position'dan itibaren abone olur (server-streaming)
+	// Önce kayıtlı event'ler gönderilir, ardından caught_up mesajı ve canlı event'ler gelir
+	SubscribeAll(ctx context.Context, in *SubscribeAllRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscriptionMessage], error)
+	// Tek bir aggregate'in stream'ine from_version'dan itibaren abone olur (server-streaming)
+	SubscribeToStream(ctx context.Context, in *SubscribeToStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscriptionMessage], error)
+	// Bir aggregate stream'ini limitsiz olarak stream eder (server-streaming)
+	// Version aralığı, event tipi ve yön filtreleri sorguya iletilir
+	ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
 }
 
 type eventStoreServiceClient struct {
@@ -72,6 +91,83 @@ func (c *eventStoreServiceClient) GetAggregateWithSnapshot(ctx context.Context,
 	return out, nil
 }
 
+func (c *eventStoreServiceClient) AppendEvents(ctx context.Context, in *AppendEventsRequest, opts ...grpc.CallOption) (*AppendEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AppendEventsResponse)
+	err := c.cc.Invoke(ctx, EventStoreService_AppendEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventStoreServiceClient) ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadAllResponse)
+	err := c.cc.Invoke(ctx, EventStoreService_ReadAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventStoreServiceClient) SubscribeAll(ctx context.Context, in *SubscribeAllRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscriptionMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventStoreService_ServiceDesc.Streams[0], EventStoreService_SubscribeAll_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeAllRequest, SubscriptionMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventStoreService_SubscribeAllClient = grpc.ServerStreamingClient[SubscriptionMessage]
+
+func (c *eventStoreServiceClient) SubscribeToStream(ctx context.Context, in *SubscribeToStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscriptionMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventStoreService_ServiceDesc.Streams[1], EventStoreService_SubscribeToStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeToStreamRequest, SubscriptionMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventStoreService_SubscribeToStreamClient = grpc.ServerStreamingClient[SubscriptionMessage]
+
+func (c *eventStoreServiceClient) ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventStoreService_ServiceDesc.Streams[2], EventStoreService_ReadStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadStreamRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventStoreService_ReadStreamClient = grpc.ServerStreamingClient[Event]
+
 // EventStoreServiceServer is the server API for EventStoreService service.
 // All implementations must embed UnimplementedEventStoreServiceServer
 // for forward compatibility.
@@ -86,6 +182,20 @@ type EventStoreServiceServer interface {
 	// Snapshot varsa: snapshot + sonraki eventler
 	// Snapshot yoksa: tüm eventler
 	GetAggregateWithSnapshot(context.Context, *GetAggregateWithSnapshotRequest) (*GetAggregateWithSnapshotResponse, error)
+	// Optimistic concurrency ile event ekler
+	// Stream'in versiyonu expected_version ile uyuşmazsa ABORTED döner
+	AppendEvents(context.Context, *AppendEventsRequest) (*AppendEventsResponse, error)
+	// HTTP karşılığı: GET /events/replay?from_position=
+	// Tüm stream'lerdeki event'leri global position sırasıyla okur
+	ReadAll(context.Context, *ReadAllRequest) (*ReadAllResponse, error)
+	// Tüm event'lere from_position'dan itibaren abone olur (server-streaming)
+	// Önce kayıtlı event'ler gönderilir, ardından caught_up mesajı ve canlı event'ler gelir
+	SubscribeAll(*SubscribeAllRequest, grpc.ServerStreamingServer[SubscriptionMessage]) error
+	// Tek bir aggregate'in stream'ine from_version'dan itibaren abone olur (server-streaming)
+	SubscribeToStream(*SubscribeToStreamRequest, grpc.ServerStreamingServer[SubscriptionMessage]) error
+	// Bir aggregate stream'ini limitsiz olarak stream eder (server-streaming)
+	// Version aralığı, event tipi ve yön filtreleri sorguya iletilir
+	ReadStream(*ReadStreamRequest, grpc.ServerStreamingServer[Event]) error
 	mustEmbedUnimplementedEventStoreServiceServer()
 }
 
@@ -102,6 +212,21 @@ func (UnimplementedEventStoreServiceServer) GetAggregateEvents(context.Context,
 func (UnimplementedEventStoreServiceServer) GetAggregateWithSnapshot(context.Context, *GetAggregateWithSnapshotRequest) (*GetAggregateWithSnapshotResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAggregateWithSnapshot not implemented")
 }
+func (UnimplementedEventStoreServiceServer) AppendEvents(context.Context, *AppendEventsRequest) (*AppendEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendEvents not implemented")
+}
+func (UnimplementedEventStoreServiceServer) ReadAll(context.Context, *ReadAllRequest) (*ReadAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadAll not implemented")
+}
+func (UnimplementedEventStoreServiceServer) SubscribeAll(*SubscribeAllRequest, grpc.ServerStreamingServer[SubscriptionMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAll not implemented")
+}
+func (UnimplementedEventStoreServiceServer) SubscribeToStream(*SubscribeToStreamRequest, grpc.ServerStreamingServer[SubscriptionMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToStream not implemented")
+}
+func (UnimplementedEventStoreServiceServer) ReadStream(*ReadStreamRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadStream not implemented")
+}
 func (UnimplementedEventStoreServiceServer) mustEmbedUnimplementedEventStoreServiceServer() {}
 func (UnimplementedEventStoreServiceServer) testEmbeddedByValue()                           {}
 
@@ -159,6 +284,75 @@ func _EventStoreService_GetAggregateWithSnapshot_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventStoreService_AppendEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventStoreServiceServer).AppendEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventStoreService_AppendEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventStoreServiceServer).AppendEvents(ctx, req.(*AppendEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventStoreService_ReadAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventStoreServiceServer).ReadAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventStoreService_ReadAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventStoreServiceServer).ReadAll(ctx, req.(*ReadAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventStoreService_SubscribeAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAllRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventStoreServiceServer).SubscribeAll(m, &grpc.GenericServerStream[SubscribeAllRequest, SubscriptionMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventStoreService_SubscribeAllServer = grpc.ServerStreamingServer[SubscriptionMessage]
+
+func _EventStoreService_SubscribeToStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventStoreServiceServer).SubscribeToStream(m, &grpc.GenericServerStream[SubscribeToStreamRequest, SubscriptionMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventStoreService_SubscribeToStreamServer = grpc.ServerStreamingServer[SubscriptionMessage]
+
+func _EventStoreService_ReadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventStoreServiceServer).ReadStream(m, &grpc.GenericServerStream[ReadStreamRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventStoreService_ReadStreamServer = grpc.ServerStreamingServer[Event]
+
 // EventStoreService_ServiceDesc is the grpc.ServiceDesc for EventStoreService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -174,7 +368,31 @@ var EventStoreService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAggregateWithSnapshot",
 			Handler:    _EventStoreService_GetAggregateWithSnapshot_Handler,
 		},
+		{
+			MethodName: "AppendEvents",
+			Handler:    _EventStoreService_AppendEvents_Handler,
+		},
+		{
+			MethodName: "ReadAll",
+			Handler:    _EventStoreService_ReadAll_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeAll",
+			Handler:       _EventStoreService_SubscribeAll_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeToStream",
+			Handler:       _EventStoreService_SubscribeToStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadStream",
+			Handler:       _EventStoreService_ReadStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/event_store.proto",
 }
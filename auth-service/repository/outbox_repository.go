@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eyupaydin41/auth-service/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// CreateTable - Outbox tablosunu oluşturur
+func (r *OutboxRepository) CreateTable() error {
+	return r.db.AutoMigrate(&model.OutboxMessage{})
+}
+
+// Reserve - Mesajları appending olarak tek transaction içinde yazar (relay henüz göndermez)
+// Event-store append'inden önce commit edilir; append başarılı olup process çökse bile
+// event'lerin outbox satırı vardır ve relay onları Release ya da Discard ile sonuçlandırır
+func (r *OutboxRepository) Reserve(messages []*model.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	for _, msg := range messages {
+		msg.Status = model.OutboxStatusAppending
+	}
+	if err := r.db.Create(&messages).Error; err != nil {
+		return fmt.Errorf("failed to insert outbox messages: %w", err)
+	}
+	return nil
+}
+
+// Release - Append'i doğrulanmış appending mesajları gönderilmek üzere pending yapar
+func (r *OutboxRepository) Release(ids []string) error {
+	err := r.db.Model(&model.OutboxMessage{}).
+		Where("id IN ? AND status = ?", ids, model.OutboxStatusAppending).
+		Updates(map[string]interface{}{
+			"status":          model.OutboxStatusPending,
+			"next_attempt_at": time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release outbox messages: %w", err)
+	}
+	return nil
+}
+
+// Discard - Event-store'a yazılmadığı kesin olan appending mesajları siler
+func (r *OutboxRepository) Discard(ids []string) error {
+	err := r.db.Where("id IN ? AND status = ?", ids, model.OutboxStatusAppending).
+		Delete(&model.OutboxMessage{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to discard outbox messages: %w", err)
+	}
+	return nil
+}
+
+// FindStaleAppending - before'dan önce yazılmış ve hâlâ sonucu bilinmeyen mesajlar
+func (r *OutboxRepository) FindStaleAppending(before time.Time, limit int) ([]model.OutboxMessage, error) {
+	var messages []model.OutboxMessage
+	err := r.db.Where("status = ? AND created_at < ?", model.OutboxStatusAppending, before).
+		Order("created_at ASC, version ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch appending outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+// ProcessPending - Gönderim zamanı gelmiş pending mesajları sırayla kilitleyip fn'e verir
+// Birden fazla relay instance'ı aynı satırı işlemesin diye SKIP LOCKED kullanılır.
+// Aggregate'inde daha eski gönderilmemiş (appending, pending ya da backoff'ta bekleyen) bir
+// satır olan mesajlar seçilmez: başarısız bir event'in sonraki event'leri onu geçemez ve
+// başka bir instance'ın kilitlediği satırın arkasındakiler de beklemede kalır
+func (r *OutboxRepository) ProcessPending(limit int, fn func(tx *gorm.DB, messages []model.OutboxMessage) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var messages []model.OutboxMessage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxStatusPending, time.Now()).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox older
+				WHERE older.aggregate_id = outbox.aggregate_id
+					AND older.status <> ?
+					AND (older.created_at < outbox.created_at
+						OR (older.created_at = outbox.created_at AND older.version < outbox.version))
+			)`, model.OutboxStatusSent).
+			Order("created_at ASC, version ASC").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil {
+			return fmt.Errorf("failed to fetch pending outbox messages: %w", err)
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		return fn(tx, messages)
+	})
+}
+
+// MarkSent - Mesajı gönderildi olarak işaretler
+func (r *OutboxRepository) MarkSent(tx *gorm.DB, id string) error {
+	now := time.Now()
+	return tx.Model(&model.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     model.OutboxStatusSent,
+		"sent_at":    now,
+		"last_error": "",
+	}).Error
+}
+
+// MarkFailed - Başarısız denemeyi kaydeder ve bir sonraki deneme zamanını ayarlar
+func (r *OutboxRepository) MarkFailed(tx *gorm.DB, id string, attempts int, sendErr error, nextAttemptAt time.Time) error {
+	return tx.Model(&model.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      sendErr.Error(),
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+}
+
+// CountPending - Gönderilmeyi bekleyen mesaj sayısı
+func (r *OutboxRepository) CountPending() (int64, error) {
+	var count int64
+	err := r.db.Model(&model.OutboxMessage{}).Where("status = ?", model.OutboxStatusPending).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/eyupaydin41/event-store/archive"
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveHandler struct {
+	archive  *archive.Archive
+	archiver *archive.Archiver
+}
+
+// NewArchiveHandler - archiver nil ise (backend partition desteklemiyor) sadece okuma endpoint'leri çalışır
+func NewArchiveHandler(archive *archive.Archive, archiver *archive.Archiver) *ArchiveHandler {
+	return &ArchiveHandler{archive: archive, archiver: archiver}
+}
+
+// GetManifest - Arşivlenmiş partition'lar, satır sayıları ve checksum'lar
+// GET /archive
+func (h *ArchiveHandler) GetManifest(c *gin.Context) {
+	response := gin.H{"partitions": h.archive.Entries()}
+	if h.archiver != nil {
+		response["cutoff"] = h.archiver.Cutoff()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RunArchive - Cutoff'tan eski partition'ları hemen arşivler
+// POST /archive/run
+func (h *ArchiveHandler) RunArchive(c *gin.Context) {
+	if h.archiver == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "event store backend does not support partition archival"})
+		return
+	}
+
+	result, err := h.archiver.Run(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "archived": result.Archived})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// VerifyArchive - Tüm arşiv dosyalarının checksum'larını kontrol eder
+// GET /archive/verify
+func (h *ArchiveHandler) VerifyArchive(c *gin.Context) {
+	failures := h.archive.Verify(c.Request.Context())
+
+	errors := make(map[string]string, len(failures))
+	for partition, err := range failures {
+		errors[partition] = err.Error()
+	}
+
+	status := http.StatusOK
+	if len(errors) > 0 {
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"partitions": len(h.archive.Entries()),
+		"valid":      len(errors) == 0,
+		"errors":     errors,
+	})
+}
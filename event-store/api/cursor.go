@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 10000
+)
+
+// errInvalidCursor - Token çözülemedi ya da başka bir sorgu için üretilmiş
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor - Opaque continuation token'ın içeriği
+// Sıralama anahtarları değişmez olduğu için (global position, aggregate version, aggregate ID)
+// yeni event'ler gelse de sayfalar kaymaz
+type pageCursor struct {
+	// Scope - Token'ın üretildiği endpoint + filtrelerin hash'i; farklı sorguda kullanılamaz
+	Scope       uint32 `json:"s"`
+	Position    uint64 `json:"p,omitempty"`
+	Version     uint32 `json:"v,omitempty"`
+	AggregateID string `json:"a,omitempty"`
+}
+
+func cursorScope(scope string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(scope))
+	return h.Sum32()
+}
+
+func encodeCursor(scope string, cursor pageCursor) string {
+	cursor.Scope = cursorScope(scope)
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor - Boş token sıfır cursor döner (ilk sayfa)
+func decodeCursor(token, scope string) (pageCursor, error) {
+	var cursor pageCursor
+	if token == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, errInvalidCursor
+	}
+	if cursor.Scope != cursorScope(scope) {
+		return cursor, errInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// pageSize - limit query parametresini okur (varsayılan ve üst sınır uygulanır)
+func pageSize(c *gin.Context, fallback int) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// nextToken - Sayfa doluysa bir sonraki sayfanın token'ı, değilse nil (JSON'da null)
+func nextToken(full bool, scope string, cursor pageCursor) *string {
+	if !full {
+		return nil
+	}
+	token := encodeCursor(scope, cursor)
+	return &token
+}
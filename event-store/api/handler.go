@@ -1,11 +1,14 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
 	"github.com/eyupaydin41/event-store/service"
 	"github.com/gin-gonic/gin"
 )
@@ -18,8 +21,13 @@ func NewEventHandler(service *service.EventService) *EventHandler {
 	return &EventHandler{service: service}
 }
 
+// GetEvents - Filtrelenmiş event'leri global position sırasıyla sayfa sayfa döner
+// GET /events?event_type=&aggregate_id=&start_time=&end_time=&limit=&cursor=
+// Metadata filtreleri: correlation_id, causation_id, actor_id, source_service, schema_version
+// Stream filtreleri: aggregate_type, category
+// Yanıttaki next token'ı bir sonraki sayfa için cursor olarak verilir (son sayfada null)
 func (h *EventHandler) GetEvents(c *gin.Context) {
-	var filter model.EventFilter
+	filter := model.EventFilter{TenantID: tenantFrom(c)}
 
 	if eventType := c.Query("event_type"); eventType != "" {
 		filter.EventType = eventType
@@ -41,57 +49,130 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 		}
 	}
 
-	if limit := c.Query("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil {
-			filter.Limit = l
+	filter.Limit = pageSize(c, defaultPageSize)
+
+	// Sayfalama position cursor'ı ile yapılır; offset satır atlayıp cursor'ın kapsamını bozar
+	if c.Query("offset") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset is not supported, page with cursor or from_position"})
+		return
+	}
+
+	filter.FromPosition = 1
+	if fromPosition := c.Query("from_position"); fromPosition != "" {
+		p, err := strconv.ParseUint(fromPosition, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from_position must be a non-negative integer"})
+			return
 		}
+		filter.FromPosition = p
 	}
 
-	if offset := c.Query("offset"); offset != "" {
-		if o, err := strconv.Atoi(offset); err == nil {
-			filter.Offset = o
+	// Metadata filtreleri
+	filter.CorrelationID = c.Query("correlation_id")
+	filter.CausationID = c.Query("causation_id")
+	filter.ActorID = c.Query("actor_id")
+	filter.SourceService = c.Query("source_service")
+	if schemaVersion := c.Query("schema_version"); schemaVersion != "" {
+		v, err := strconv.ParseUint(schemaVersion, 10, 16)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "schema_version must be a non-negative integer"})
+			return
 		}
+		version := uint16(v)
+		filter.SchemaVersion = &version
+	}
+
+	// Stream filtreleri
+	filter.AggregateType = c.Query("aggregate_type")
+	filter.Category = c.Query("category")
+
+	scope := fmt.Sprintf("events|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
+		filter.TenantID, filter.EventType, filter.AggregateID, c.Query("start_time"), c.Query("end_time"),
+		filter.CorrelationID, filter.CausationID, filter.ActorID, filter.SourceService, c.Query("schema_version"),
+		filter.AggregateType, filter.Category)
+	if !applyPositionCursor(c, scope, &filter.FromPosition) {
+		return
 	}
 
 	events, err := h.service.GetEvents(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(readStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
+	next := nextPosition(events, filter.FromPosition)
 	c.JSON(http.StatusOK, gin.H{
-		"events": events,
-		"count":  len(events),
+		"events":        events,
+		"count":         len(events),
+		"next_position": next,
+		"next":          nextToken(len(events) == filter.Limit, scope, pageCursor{Position: next}),
 	})
 }
 
+// GetEventsByAggregate - Aggregate'in event'lerini version sırasıyla sayfa sayfa döner
+// GET /events/aggregate/:id?from_version=&limit=&cursor=
 func (h *EventHandler) GetEventsByAggregate(c *gin.Context) {
 	aggregateID := c.Param("id")
+	tenantID := tenantFrom(c)
 
-	fromVersion := 0
+	var fromVersion uint32
 	if v := c.Query("from_version"); v != "" {
-		if ver, err := strconv.Atoi(v); err == nil {
-			fromVersion = ver
+		if ver, err := strconv.ParseUint(v, 10, 32); err == nil {
+			fromVersion = uint32(ver)
 		}
 	}
 
-	events, err := h.service.GetEventsByAggregateID(aggregateID, fromVersion)
+	scope := "aggregate|" + tenantID + "|" + aggregateID
+	cursor, err := decodeCursor(c.Query("cursor"), scope)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if cursor.Version > 0 {
+		fromVersion = cursor.Version
+	}
+
+	limit := pageSize(c, 1000)
+	var events []*model.Event
+	err = h.service.ReadStream(c.Request.Context(), model.StreamQuery{
+		TenantID:    tenantID,
+		AggregateID: aggregateID,
+		FromVersion: fromVersion,
+		MaxCount:    limit,
+	}, func(event *model.Event) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		c.JSON(readStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	var nextVersion uint32
+	if len(events) > 0 {
+		nextVersion = events[len(events)-1].Version + 1
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"aggregate_id": aggregateID,
 		"events":       events,
 		"count":        len(events),
+		"next":         nextToken(len(events) == limit, scope, pageCursor{Version: nextVersion}),
 	})
 }
 
+// ReplayEvents - since (RFC3339) ya da from_position'dan itibaren event'leri position sırasıyla döner
+// GET /events/replay?since=&limit=&cursor= veya /events/replay?from_position=&event_type=&category=&limit=&cursor=
+// from_position tam olarak kaldığı yerden devam etmek içindir; next token ile sayfalanır
 func (h *EventHandler) ReplayEvents(c *gin.Context) {
+	if fromPositionStr := c.Query("from_position"); fromPositionStr != "" {
+		h.replayFromPosition(c, fromPositionStr)
+		return
+	}
+
 	sinceStr := c.Query("since")
 	if sinceStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "since parameter is required (RFC3339 format)"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since (RFC3339 format) or from_position parameter is required"})
 		return
 	}
 
@@ -101,9 +182,17 @@ func (h *EventHandler) ReplayEvents(c *gin.Context) {
 		return
 	}
 
-	events, err := h.service.GetEventsSince(since)
+	tenantID := tenantFrom(c)
+	var fromPosition uint64 = 1
+	scope := "replay-since|" + tenantID + "|" + sinceStr
+	if !applyPositionCursor(c, scope, &fromPosition) {
+		return
+	}
+
+	limit := pageSize(c, 1000)
+	events, err := h.service.GetEventsSince(tenantID, since, fromPosition, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(readStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -111,11 +200,78 @@ func (h *EventHandler) ReplayEvents(c *gin.Context) {
 		"since":  since,
 		"events": events,
 		"count":  len(events),
+		"next":   nextToken(len(events) == limit, scope, pageCursor{Position: nextPosition(events, fromPosition)}),
 	})
 }
 
+func (h *EventHandler) replayFromPosition(c *gin.Context, fromPositionStr string) {
+	fromPosition, err := strconv.ParseUint(fromPositionStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from_position must be a non-negative integer"})
+		return
+	}
+
+	tenantID := tenantFrom(c)
+	eventType := c.Query("event_type")
+	category := c.Query("category")
+	scope := "replay-position|" + tenantID + "|" + eventType + "|" + category
+	if !applyPositionCursor(c, scope, &fromPosition) {
+		return
+	}
+
+	limit := pageSize(c, 1000)
+	events, err := h.service.GetEventsFromPosition(tenantID, fromPosition, eventType, category, limit)
+	if err != nil {
+		c.JSON(readStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	next := nextPosition(events, fromPosition)
+	c.JSON(http.StatusOK, gin.H{
+		"from_position": fromPosition,
+		"events":        events,
+		"count":         len(events),
+		"next_position": next,
+		"next":          nextToken(len(events) == limit, scope, pageCursor{Position: next}),
+	})
+}
+
+// applyPositionCursor - cursor parametresi varsa position'ı ondan alır
+// Geçersiz token'da 400 yazar ve false döner
+func applyPositionCursor(c *gin.Context, scope string, position *uint64) bool {
+	cursor, err := decodeCursor(c.Query("cursor"), scope)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if cursor.Position > 0 {
+		*position = cursor.Position
+	}
+	return true
+}
+
+// readStatus - Okuma hatasının HTTP status'u
+// Arşivlenmiş aralık (ARCHIVE_READ_MODE=report) 410 Gone döner, diğer hatalar fallback ile
+func readStatus(err error, fallback int) int {
+	if errors.Is(err, repository.ErrArchived) {
+		return http.StatusGone
+	}
+	return fallback
+}
+
+// nextPosition - Kaldığı yerden devam etmek için bir sonraki istekte verilecek from_position
+func nextPosition(events []*model.Event, fromPosition uint64) uint64 {
+	next := fromPosition
+	for _, event := range events {
+		if event.Position >= next {
+			next = event.Position + 1
+		}
+	}
+	return next
+}
+
 func (h *EventHandler) GetEventCount(c *gin.Context) {
-	count, err := h.service.CountEvents()
+	count, err := h.service.CountEvents(tenantFrom(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -127,7 +283,7 @@ func (h *EventHandler) GetEventCount(c *gin.Context) {
 }
 
 func (h *EventHandler) HealthCheck(c *gin.Context) {
-	count, err := h.service.CountEvents()
+	count, err := h.service.CountEvents(tenantFrom(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status": "unhealthy",
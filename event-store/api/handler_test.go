@@ -0,0 +1,189 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/eyupaydin41/event-store/catalog"
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+	"github.com/eyupaydin41/event-store/service"
+	"github.com/gin-gonic/gin"
+)
+
+type eventsPage struct {
+	Events []*model.Event `json:"events"`
+	Next   *string        `json:"next"`
+}
+
+func newTestRouter(t *testing.T, events int) (*gin.Engine, *service.EventService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewEventService(repository.NewMemoryEventRepository(), catalog.NewCatalog())
+	for i := 0; i < events; i++ {
+		if err := svc.SaveEvent(&model.Event{
+			EventType:   "user.login.recorded",
+			AggregateID: fmt.Sprintf("user-%d", i%3),
+			Payload:     `{}`,
+		}); err != nil {
+			t.Fatalf("SaveEvent: %v", err)
+		}
+	}
+
+	handler := NewEventHandler(svc)
+	router := gin.New()
+	router.GET("/events", handler.GetEvents)
+	router.GET("/events/aggregate/:id", handler.GetEventsByAggregate)
+	return router, svc
+}
+
+func getPage(t *testing.T, router *gin.Engine, target string) (int, eventsPage) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var page eventsPage
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return rec.Code, page
+}
+
+func TestEventsCursorPagesThroughAllEvents(t *testing.T) {
+	router, svc := newTestRouter(t, 25)
+
+	seen := map[uint64]bool{}
+	target := "/events?limit=10"
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+
+		code, page := getPage(t, router, target)
+		if code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", target, code)
+		}
+		for _, event := range page.Events {
+			if seen[event.Position] {
+				t.Fatalf("position %d returned twice", event.Position)
+			}
+			seen[event.Position] = true
+		}
+
+		// Sayfalar arasında gelen yeni event'ler önceki sayfaları kaydırmamalı
+		if pages == 0 {
+			if err := svc.SaveEvent(&model.Event{EventType: "user.created", AggregateID: "user-9", Payload: `{}`}); err != nil {
+				t.Fatalf("SaveEvent: %v", err)
+			}
+		}
+
+		if page.Next == nil {
+			break
+		}
+		target = "/events?limit=10&cursor=" + url.QueryEscape(*page.Next)
+	}
+
+	if len(seen) != 26 {
+		t.Errorf("expected 26 distinct events, got %d", len(seen))
+	}
+}
+
+func TestCursorIsBoundToItsQuery(t *testing.T) {
+	router, _ := newTestRouter(t, 5)
+
+	_, page := getPage(t, router, "/events/aggregate/user-0?limit=1")
+	if page.Next == nil {
+		t.Fatal("expected next cursor")
+	}
+
+	code, _ := getPage(t, router, "/events/aggregate/user-1?limit=1&cursor="+url.QueryEscape(*page.Next))
+	if code != http.StatusBadRequest {
+		t.Errorf("expected 400 for cursor of another aggregate, got %d", code)
+	}
+
+	code, page = getPage(t, router, "/events/aggregate/user-0?limit=1&cursor="+url.QueryEscape(*page.Next))
+	if code != http.StatusOK || len(page.Events) != 1 || page.Events[0].Version != 2 {
+		t.Errorf("expected version 2 on second page, got %d %+v", code, page.Events)
+	}
+
+	// offset cursor'ın kapsamına girmediği için reddedilir
+	if code, _ := getPage(t, router, "/events?limit=1&offset=2"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for offset, got %d", code)
+	}
+}
+
+func TestEventsFilterByMetadata(t *testing.T) {
+	router, svc := newTestRouter(t, 5)
+
+	for i, correlationID := range []string{"req-1", "req-1", "req-2"} {
+		if err := svc.SaveEvent(&model.Event{
+			EventType:   "user.email.changed",
+			AggregateID: fmt.Sprintf("user-%d", i),
+			Payload:     `{}`,
+			Metadata: model.EventMetadata{
+				CorrelationID: correlationID,
+				ActorID:       "admin-1",
+				SourceService: "auth-service",
+			},
+		}); err != nil {
+			t.Fatalf("SaveEvent: %v", err)
+		}
+	}
+
+	code, page := getPage(t, router, "/events?correlation_id=req-1")
+	if code != http.StatusOK || len(page.Events) != 2 {
+		t.Fatalf("expected 2 events for req-1, got %d (status %d)", len(page.Events), code)
+	}
+	for _, event := range page.Events {
+		if event.Metadata.CorrelationID != "req-1" || event.Metadata.ActorID != "admin-1" {
+			t.Errorf("unexpected metadata: %+v", event.Metadata)
+		}
+	}
+
+	if _, page := getPage(t, router, "/events?actor_id=admin-1&source_service=auth-service"); len(page.Events) != 3 {
+		t.Errorf("expected 3 events by admin-1, got %d", len(page.Events))
+	}
+	if _, page := getPage(t, router, "/events?source_service=query-service"); len(page.Events) != 0 {
+		t.Errorf("expected no query-service events, got %d", len(page.Events))
+	}
+	if code, _ := getPage(t, router, "/events?schema_version=abc"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid schema_version, got %d", code)
+	}
+}
+
+func TestUserStateResponsesKeepLegacyFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := repository.NewMemoryEventRepository()
+	svc := service.NewEventService(repo, catalog.NewCatalog())
+	if err := svc.SaveEvent(&model.Event{EventType: "user.created", AggregateID: "user-1", Payload: `{"email":"a@example.com","password_hash":"h"}`}); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+
+	reducers := reducer.NewDefaultRegistry()
+	replay := NewReplayHandler(service.NewReplayService(repo, reducers))
+	snapshots := NewSnapshotHandler(service.NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, catalog.NewCatalog(), reducers))
+	router := gin.New()
+	router.GET("/replay/:aggregate_type/:id/state", replay.GetState)
+	router.GET("/snapshots/:aggregate_id/state", snapshots.GetAggregateState)
+
+	for target, legacy := range map[string]string{"/replay/user/user-1/state": "user_id", "/snapshots/user-1/state": "email"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d (%v)", target, rec.Code, err)
+		}
+		if body["aggregate_id"] != "user-1" || body["state"] == nil || body[legacy] == nil {
+			t.Errorf("%s: expected new and legacy %q fields, got %v", target, legacy, body)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/eyupaydin41/event-store/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService: privacyService}
+}
+
+// ForgetUser - Kullanıcının veri anahtarını yok eder (crypto-shredding)
+// POST /privacy/forget/:aggregate_id
+// Geri alınamaz: event'ler kalır ama PII alanları bundan sonra "[redacted]" döner
+func (h *PrivacyHandler) ForgetUser(c *gin.Context) {
+	aggregateID := c.Param("aggregate_id")
+
+	if err := h.privacyService.ForgetUser(tenantFrom(c), aggregateID); err != nil {
+		if errors.Is(err, service.ErrAggregateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"aggregate_id": aggregateID,
+		"forgotten":    true,
+	})
+}
+
+// GetPrivacyStatus - Aggregate unutulmuş mu?
+// GET /privacy/:aggregate_id
+func (h *PrivacyHandler) GetPrivacyStatus(c *gin.Context) {
+	aggregateID := c.Param("aggregate_id")
+
+	forgotten, err := h.privacyService.IsForgotten(tenantFrom(c), aggregateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"aggregate_id": aggregateID,
+		"forgotten":    forgotten,
+	})
+}
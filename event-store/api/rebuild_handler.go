@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eyupaydin41/event-store/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RebuildHandler - Toplu snapshot rebuild işlerini başlatır, izler, iptal eder ve devam ettirir
+type RebuildHandler struct {
+	rebuilder *service.SnapshotRebuilder
+}
+
+func NewRebuildHandler(rebuilder *service.SnapshotRebuilder) *RebuildHandler {
+	return &RebuildHandler{rebuilder: rebuilder}
+}
+
+// StartRebuild - Tenant'ın stream'lerinin snapshot'larını arka planda yeniden oluşturur
+// POST /snapshot-rebuilds?category=&aggregate_type=&active_since=&after=&workers=
+// Filtre verilmezse tüm stream'ler; active_since (RFC3339) son aktiviteye göre süzer
+func (h *RebuildHandler) StartRebuild(c *gin.Context) {
+	request := service.SnapshotRebuildRequest{
+		TenantID:      tenantFrom(c),
+		Category:      c.Query("category"),
+		AggregateType: c.Query("aggregate_type"),
+		After:         c.Query("after"),
+	}
+
+	if activeSince := c.Query("active_since"); activeSince != "" {
+		t, err := time.Parse(time.RFC3339, activeSince)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active_since format, use RFC3339"})
+			return
+		}
+		request.ActiveSince = t
+	}
+	if value := c.Query("workers"); value != "" {
+		workers, err := strconv.Atoi(value)
+		if err != nil || workers <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "workers must be a positive integer"})
+			return
+		}
+		request.Workers = workers
+	}
+
+	rebuild, err := h.rebuilder.Start(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, rebuild)
+}
+
+// ListRebuilds - Tenant'ın rebuild işleri, en yenisi önce
+// GET /snapshot-rebuilds
+func (h *RebuildHandler) ListRebuilds(c *gin.Context) {
+	rebuilds := h.rebuilder.List(tenantFrom(c))
+	c.JSON(http.StatusOK, gin.H{
+		"rebuilds": rebuilds,
+		"count":    len(rebuilds),
+	})
+}
+
+// GetRebuild - İşin ilerlemesi ve devam cursor'ı
+// GET /snapshot-rebuilds/:id
+func (h *RebuildHandler) GetRebuild(c *gin.Context) {
+	rebuild, err := h.rebuilder.Get(tenantFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(rebuildStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rebuild)
+}
+
+// CancelRebuild - İşi durdurur; dönen cursor'dan resume ile devam edilebilir
+// POST /snapshot-rebuilds/:id/cancel
+func (h *RebuildHandler) CancelRebuild(c *gin.Context) {
+	rebuild, err := h.rebuilder.Cancel(tenantFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(rebuildStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rebuild)
+}
+
+// ResumeRebuild - Bitmiş işi aynı filtrelerle cursor'dan devam ettiren yeni iş başlatır
+// POST /snapshot-rebuilds/:id/resume
+func (h *RebuildHandler) ResumeRebuild(c *gin.Context) {
+	rebuild, err := h.rebuilder.Resume(tenantFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(rebuildStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, rebuild)
+}
+
+// rebuildStatus - Rebuild işi hatasının HTTP status'u
+func rebuildStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrRebuildNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrRebuildRunning):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
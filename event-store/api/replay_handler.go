@@ -1,9 +1,12 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
 	"github.com/eyupaydin41/event-store/service"
 	"github.com/gin-gonic/gin"
 )
@@ -16,28 +19,29 @@ func NewReplayHandler(replayService *service.ReplayService) *ReplayHandler {
 	return &ReplayHandler{replayService: replayService}
 }
 
-// GetUserState - Kullanıcının şu anki durumunu event'lerden reconstruct eder
-// GET /replay/user/:id/state
-func (h *ReplayHandler) GetUserState(c *gin.Context) {
-	userID := c.Param("id")
+// GetState - Aggregate'in şu anki durumunu event'lerden reconstruct eder
+// GET /replay/:aggregate_type/:id/state
+func (h *ReplayHandler) GetState(c *gin.Context) {
+	aggregateType, aggregateID := c.Param("aggregate_type"), c.Param("id")
 
-	aggregate, err := h.replayService.ReplayUserState(userID)
+	state, err := h.replayService.ReplayState(tenantFrom(c), aggregateType, aggregateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(replayStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user_id": userID,
-		"state":   aggregate,
-		"message": "Current state reconstructed from events",
-	})
+	c.JSON(http.StatusOK, withLegacyUserID(gin.H{
+		"aggregate_id":   aggregateID,
+		"aggregate_type": aggregateType,
+		"state":          state,
+		"message":        "Current state reconstructed from events",
+	}))
 }
 
-// GetUserStateAt - Belirli bir zamandaki kullanıcı durumu (TIME TRAVEL!)
-// GET /replay/user/:id/state-at?timestamp=2024-01-15T10:00:00Z
-func (h *ReplayHandler) GetUserStateAt(c *gin.Context) {
-	userID := c.Param("id")
+// GetStateAt - Belirli bir zamandaki aggregate durumu (TIME TRAVEL!)
+// GET /replay/:aggregate_type/:id/state-at?timestamp=2024-01-15T10:00:00Z
+func (h *ReplayHandler) GetStateAt(c *gin.Context) {
+	aggregateType, aggregateID := c.Param("aggregate_type"), c.Param("id")
 	timestampStr := c.Query("timestamp")
 
 	if timestampStr == "" {
@@ -55,43 +59,54 @@ func (h *ReplayHandler) GetUserStateAt(c *gin.Context) {
 		return
 	}
 
-	aggregate, err := h.replayService.ReplayUserStateAt(userID, timestamp)
+	state, err := h.replayService.ReplayStateAt(tenantFrom(c), aggregateType, aggregateID, timestamp)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(replayStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user_id":       userID,
-		"point_in_time": timestamp,
-		"state":         aggregate,
-		"message":       "State reconstructed at specified time",
-	})
+	c.JSON(http.StatusOK, withLegacyUserID(gin.H{
+		"aggregate_id":   aggregateID,
+		"aggregate_type": aggregateType,
+		"point_in_time":  timestamp,
+		"state":          state,
+		"message":        "State reconstructed at specified time",
+	}))
 }
 
-// GetUserHistory - Kullanıcının tüm değişiklik geçmişi
-// GET /replay/user/:id/history
-func (h *ReplayHandler) GetUserHistory(c *gin.Context) {
-	userID := c.Param("id")
+// GetHistory - Aggregate'in değişiklik geçmişi, version sırasıyla sayfa sayfa
+// GET /replay/:aggregate_type/:id/history?limit=&cursor=
+func (h *ReplayHandler) GetHistory(c *gin.Context) {
+	aggregateType, aggregateID := c.Param("aggregate_type"), c.Param("id")
+	tenantID := tenantFrom(c)
 
-	history, err := h.replayService.GetUserHistory(userID)
+	scope := "history|" + tenantID + "|" + aggregateType + "|" + aggregateID
+	cursor, err := decodeCursor(c.Query("cursor"), scope)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user_id":       userID,
-		"history":       history,
-		"total_changes": len(history),
-		"message":       "Full history of state changes",
-	})
+	history, nextVersion, err := h.replayService.GetHistory(tenantID, aggregateType, aggregateID, cursor.Version, pageSize(c, 1000))
+	if err != nil {
+		c.JSON(replayStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, withLegacyUserID(gin.H{
+		"aggregate_id":   aggregateID,
+		"aggregate_type": aggregateType,
+		"history":        history,
+		"total_changes":  len(history),
+		"next":           nextToken(nextVersion > 0, scope, pageCursor{Version: nextVersion}),
+		"message":        "History of state changes",
+	}))
 }
 
 // CompareStates - İki farklı zamandaki state'leri karşılaştır
-// GET /replay/user/:id/compare?time1=2024-01-01T00:00:00Z&time2=2024-01-15T00:00:00Z
+// GET /replay/:aggregate_type/:id/compare?time1=2024-01-01T00:00:00Z&time2=2024-01-15T00:00:00Z
 func (h *ReplayHandler) CompareStates(c *gin.Context) {
-	userID := c.Param("id")
+	aggregateType, aggregateID := c.Param("aggregate_type"), c.Param("id")
 	time1Str := c.Query("time1")
 	time2Str := c.Query("time2")
 
@@ -114,33 +129,47 @@ func (h *ReplayHandler) CompareStates(c *gin.Context) {
 		return
 	}
 
-	before, after, err := h.replayService.CompareStates(userID, time1, time2)
+	before, after, err := h.replayService.CompareStates(tenantFrom(c), aggregateType, aggregateID, time1, time2)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(replayStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	// Değişiklikleri hesapla
-	changes := map[string]interface{}{}
-	if before.Email != after.Email {
-		changes["email"] = map[string]string{
-			"before": before.Email,
-			"after":  after.Email,
-		}
+	changes, err := reducer.DiffStates(before, after)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	if before.Status != after.Status {
-		changes["status"] = map[string]string{
-			"before": before.Status,
-			"after":  after.Status,
-		}
+
+	c.JSON(http.StatusOK, withLegacyUserID(gin.H{
+		"aggregate_id":   aggregateID,
+		"aggregate_type": aggregateType,
+		"time1":          time1,
+		"time2":          time2,
+		"before":         before,
+		"after":          after,
+		"changes":        changes,
+	}))
+}
+
+// withLegacyUserID - User aggregate'lerinin yanıtına eski /replay/user/:id yanıtındaki user_id'yi de ekler
+// Aggregate tipleri gelmeden önce yazılmış istemciler kırılmasın diye (deprecated; aggregate_id kullanılmalı)
+func withLegacyUserID(response gin.H) gin.H {
+	if response["aggregate_type"] == model.DefaultAggregateType {
+		response["user_id"] = response["aggregate_id"]
 	}
+	return response
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user_id": userID,
-		"time1":   time1,
-		"time2":   time2,
-		"before":  before,
-		"after":   after,
-		"changes": changes,
-	})
+// replayStatus - Replay hatasının HTTP status'u
+// Bilinmeyen tip ya da URL'deki tiple uyuşmayan stream 404, reducer'ın işlemediği event 422
+func replayStatus(err error) int {
+	switch {
+	case errors.Is(err, reducer.ErrUnknownAggregateType), errors.Is(err, service.ErrAggregateTypeMismatch):
+		return http.StatusNotFound
+	case errors.Is(err, reducer.ErrUnknownEventType):
+		return http.StatusUnprocessableEntity
+	}
+	return readStatus(err, http.StatusNotFound)
 }
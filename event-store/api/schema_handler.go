@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/eyupaydin41/event-store/schema"
+	"github.com/gin-gonic/gin"
+)
+
+// SchemaHandler - Schema registry'yi producer'lara açar
+// Producer'lar yeni bir payload şeklini publish etmeden önce validate endpoint'i ile uyumluluğu kontrol edebilir
+type SchemaHandler struct {
+	registry *schema.Registry
+}
+
+func NewSchemaHandler(registry *schema.Registry) *SchemaHandler {
+	return &SchemaHandler{
+		registry: registry,
+	}
+}
+
+// ListSchemas - Kayıtlı event tipleri ve versiyonları
+func (h *SchemaHandler) ListSchemas(c *gin.Context) {
+	types := h.registry.EventTypes()
+
+	schemas := make([]gin.H, 0, len(types))
+	for _, eventType := range types {
+		versions := h.registry.Versions(eventType)
+		schemas = append(schemas, gin.H{
+			"event_type":     eventType,
+			"versions":       versions,
+			"latest_version": versions[len(versions)-1],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schemas": schemas,
+		"count":   len(schemas),
+	})
+}
+
+// GetLatestSchema - Event tipinin en güncel şeması
+func (h *SchemaHandler) GetLatestSchema(c *gin.Context) {
+	eventType := c.Param("event_type")
+
+	versions := h.registry.Versions(eventType)
+	if len(versions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "schema not found", "event_type": eventType})
+		return
+	}
+
+	s, _ := h.registry.Get(eventType, versions[len(versions)-1])
+	c.JSON(http.StatusOK, gin.H{
+		"event_type": eventType,
+		"versions":   versions,
+		"latest":     s,
+	})
+}
+
+// GetSchema - Event tipinin belirli bir versiyondaki şeması
+func (h *SchemaHandler) GetSchema(c *gin.Context) {
+	eventType := c.Param("event_type")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return
+	}
+
+	s, ok := h.registry.Get(eventType, version)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "schema not found", "event_type": eventType, "version": version})
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// ValidatePayload - Request body'yi (event'in data kısmı) şemaya göre doğrular
+// Event store'a hiçbir şey yazılmaz; uyumlu değilse 422 ve hata listesi döner
+func (h *SchemaHandler) ValidatePayload(c *gin.Context) {
+	eventType := c.Param("event_type")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return
+	}
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return
+	}
+
+	err = h.registry.Validate(eventType, version, payload)
+
+	var validationErr *schema.ValidationError
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"event_type": eventType, "version": version, "valid": true})
+	case errors.Is(err, schema.ErrUnknownSchema):
+		c.JSON(http.StatusNotFound, gin.H{"error": "schema not found", "event_type": eventType, "version": version})
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"event_type": eventType,
+			"version":    version,
+			"valid":      false,
+			"errors":     validationErr.Errors,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,8 +1,13 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
 	"github.com/eyupaydin41/event-store/service"
 	"github.com/gin-gonic/gin"
 )
@@ -25,7 +30,7 @@ func (h *SnapshotHandler) CreateSnapshot(c *gin.Context) {
 		return
 	}
 
-	err := h.snapshotService.CreateSnapshot(aggregateID)
+	err := h.snapshotService.CreateSnapshot(tenantFrom(c), aggregateID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to create snapshot",
@@ -48,9 +53,9 @@ func (h *SnapshotHandler) GetLatestSnapshot(c *gin.Context) {
 		return
 	}
 
-	aggregate, err := h.snapshotService.LoadAggregateWithSnapshot(aggregateID)
+	loaded, err := h.snapshotService.LoadAggregateWithSnapshot(tenantFrom(c), aggregateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(replayStatus(err), gin.H{
 			"error":   "snapshot not found",
 			"details": err.Error(),
 		})
@@ -58,9 +63,10 @@ func (h *SnapshotHandler) GetLatestSnapshot(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"aggregate_id": aggregateID,
-		"version":      aggregate.Version,
-		"state":        aggregate,
+		"aggregate_id":   aggregateID,
+		"aggregate_type": loaded.AggregateType,
+		"version":        loaded.State.StreamVersion(),
+		"state":          loaded.State,
 	})
 }
 
@@ -72,22 +78,161 @@ func (h *SnapshotHandler) GetAggregateState(c *gin.Context) {
 		return
 	}
 
-	aggregate, err := h.snapshotService.LoadAggregateWithSnapshot(aggregateID)
+	loaded, err := h.snapshotService.LoadAggregateWithSnapshot(tenantFrom(c), aggregateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(replayStatus(err), gin.H{
 			"error":   "aggregate not found",
 			"details": err.Error(),
 		})
 		return
 	}
 
+	response := gin.H{
+		"aggregate_id":    aggregateID,
+		"aggregate_type":  loaded.AggregateType,
+		"version":         loaded.State.StreamVersion(),
+		"state":           loaded.State,
+		"from_snapshot":   loaded.FromSnapshot,
+		"stale_snapshot":  loaded.StaleSnapshot,
+		"events_replayed": loaded.EventsReplayed,
+	}
+	// Eski yanıtta user state'inin alanları üst seviyedeydi; mevcut istemciler için korunur (deprecated)
+	if user, ok := loaded.State.(*model.UserAggregate); ok {
+		response["email"] = user.Email
+		response["status"] = user.Status
+		response["created_at"] = user.CreatedAt
+		response["updated_at"] = user.UpdatedAt
+		response["event_count"] = user.EventCount
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetQueueStats - Snapshot worker kuyruğunun derinliği ve sayaçları (tüm tenant'lar)
+// GET /snapshot-queue
+func (h *SnapshotHandler) GetQueueStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.snapshotService.QueueStats())
+}
+
+// GetPolicy - Aggregate'e uygulanan snapshot politikası (her ayarın kaynağıyla) ve şu anki durumu
+// GET /snapshots/:aggregate_id/policy?event_type=
+// event_type verilirse o tipte bir event yazılmış gibi trigger_event_types da değerlendirilir
+func (h *SnapshotHandler) GetPolicy(c *gin.Context) {
+	aggregateID := c.Param("aggregate_id")
+
+	status, err := h.snapshotService.EvaluateSnapshotPolicy(tenantFrom(c), aggregateID, c.Query("event_type"))
+	if err != nil {
+		c.JSON(snapshotStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ListSnapshots - Aggregate'in snapshot'ları version sırasıyla, boyutlarıyla
+// GET /snapshots/:aggregate_id/versions
+func (h *SnapshotHandler) ListSnapshots(c *gin.Context) {
+	aggregateID := c.Param("aggregate_id")
+
+	snapshots, err := h.snapshotService.ListSnapshots(tenantFrom(c), aggregateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var totalBytes uint64
+	for _, snapshot := range snapshots {
+		totalBytes += snapshot.SizeBytes
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"aggregate_id": aggregateID,
-		"version":      aggregate.Version,
-		"email":        aggregate.Email,
-		"status":       aggregate.Status,
-		"created_at":   aggregate.CreatedAt,
-		"updated_at":   aggregate.UpdatedAt,
-		"event_count":  aggregate.EventCount,
+		"snapshots":    snapshots,
+		"count":        len(snapshots),
+		"total_bytes":  totalBytes,
 	})
 }
+
+// DeleteSnapshots - Aggregate'in snapshot'larını siler
+// DELETE /snapshots/:aggregate_id?keep_last=N (keep_last verilirse en yeni N tanesi kalır)
+func (h *SnapshotHandler) DeleteSnapshots(c *gin.Context) {
+	aggregateID := c.Param("aggregate_id")
+
+	keepLast := 0
+	if value := c.Query("keep_last"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "keep_last must be a positive integer"})
+			return
+		}
+		keepLast = n
+	}
+
+	if err := h.snapshotService.DeleteSnapshots(tenantFrom(c), aggregateID, keepLast); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"aggregate_id": aggregateID,
+		"kept_last":    keepLast,
+	})
+}
+
+// DeleteSnapshot - Aggregate'in tek bir version'daki snapshot'ını siler
+// DELETE /snapshots/:aggregate_id/versions/:version
+func (h *SnapshotHandler) DeleteSnapshot(c *gin.Context) {
+	aggregateID := c.Param("aggregate_id")
+
+	version, err := strconv.ParseUint(c.Param("version"), 10, 32)
+	if err != nil || version == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return
+	}
+
+	if err := h.snapshotService.DeleteSnapshot(tenantFrom(c), aggregateID, uint32(version)); err != nil {
+		c.JSON(snapshotStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"aggregate_id": aggregateID,
+		"version":      version,
+		"deleted":      true,
+	})
+}
+
+// VerifySnapshot - Snapshot'ı stream'i baştan replay ederek doğrular ve farkları döner
+// GET /snapshots/:aggregate_id/verify?version= (version verilmezse en son snapshot)
+func (h *SnapshotHandler) VerifySnapshot(c *gin.Context) {
+	aggregateID := c.Param("aggregate_id")
+
+	var version uint64
+	if value := c.Query("version"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+			return
+		}
+		version = parsed
+	}
+
+	verification, err := h.snapshotService.VerifySnapshot(tenantFrom(c), aggregateID, uint32(version))
+	if err != nil {
+		c.JSON(snapshotStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// snapshotStatus - Snapshot yönetimi hatasının HTTP status'u
+func snapshotStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrSnapshotNotFound), errors.Is(err, service.ErrAggregateNotFound),
+		errors.Is(err, reducer.ErrUnknownAggregateType):
+		return http.StatusNotFound
+	case errors.Is(err, reducer.ErrUnknownEventType):
+		return http.StatusUnprocessableEntity
+	}
+	return readStatus(err, http.StatusInternalServerError)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/service"
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler - Materialized view'lardan event istatistikleri
+type StatsHandler struct {
+	stats *service.StatsService
+}
+
+// NewStatsHandler - stats nil ise (backend materialized view desteklemiyor) endpoint'ler 501 döner
+func NewStatsHandler(stats *service.StatsService) *StatsHandler {
+	return &StatsHandler{stats: stats}
+}
+
+// GetEventCounts - Bucket ve event tipi başına event sayıları
+// GET /stats/events?from=&to=&bucket=hour|day|week|month&event_type=a,b
+func (h *StatsHandler) GetEventCounts(c *gin.Context) {
+	h.series(c, h.stats.EventCounts)
+}
+
+// GetSignups - Bucket başına yeni kullanıcılar (user.created)
+// GET /stats/signups?from=&to=&bucket=
+func (h *StatsHandler) GetSignups(c *gin.Context) {
+	h.series(c, h.stats.Signups)
+}
+
+// GetPasswordChanges - Bucket başına şifre değişiklikleri (user.password.changed)
+// GET /stats/password-changes?from=&to=&bucket=
+func (h *StatsHandler) GetPasswordChanges(c *gin.Context) {
+	h.series(c, h.stats.PasswordChanges)
+}
+
+// GetLoginsByUserAgent - Bucket ve user agent ailesi başına login'ler
+// GET /stats/logins/user-agents?from=&to=&bucket=
+func (h *StatsHandler) GetLoginsByUserAgent(c *gin.Context) {
+	h.series(c, h.stats.LoginsByUserAgent)
+}
+
+// GetTopAggregates - Aralıkta en çok event alan aggregate'ler
+// GET /stats/aggregates/top?from=&to=&aggregate_type=&limit=
+func (h *StatsHandler) GetTopAggregates(c *gin.Context) {
+	if h.stats == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "event store backend does not support stats"})
+		return
+	}
+
+	query, ok := statsQuery(c)
+	if !ok {
+		return
+	}
+
+	aggregates, err := h.stats.TopAggregates(query)
+	if err != nil {
+		c.JSON(statsStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"aggregates": aggregates,
+		"count":      len(aggregates),
+	})
+}
+
+func (h *StatsHandler) series(c *gin.Context, fn func(model.StatsQuery) (*service.StatsSeries, error)) {
+	if h.stats == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "event store backend does not support stats"})
+		return
+	}
+
+	query, ok := statsQuery(c)
+	if !ok {
+		return
+	}
+
+	series, err := fn(query)
+	if err != nil {
+		c.JSON(statsStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// statsQuery - Ortak parametreleri okur; hatalıysa 400 yazıp false döner
+func statsQuery(c *gin.Context) (model.StatsQuery, bool) {
+	query := model.StatsQuery{
+		TenantID:      tenantFrom(c),
+		Bucket:        c.Query("bucket"),
+		AggregateType: c.Query("aggregate_type"),
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{
+		{"from", &query.From},
+		{"to", &query.To},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param.name + " format, use RFC3339"})
+			return query, false
+		}
+		*param.target = t
+	}
+
+	for _, eventType := range strings.Split(c.Query("event_type"), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			query.EventTypes = append(query.EventTypes, eventType)
+		}
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return query, false
+		}
+		query.Limit = limit
+	}
+
+	return query, true
+}
+
+func statsStatus(err error) int {
+	if errors.Is(err, service.ErrInvalidStatsQuery) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/service"
+	"github.com/gin-gonic/gin"
+)
+
+// StreamHandler - Tenant'ın stream kategorilerini ve stream'lerini listeler
+type StreamHandler struct {
+	service *service.EventService
+}
+
+func NewStreamHandler(service *service.EventService) *StreamHandler {
+	return &StreamHandler{service: service}
+}
+
+// ListCategories - Kategoriler, aggregate tipleri, stream/event sayıları ve snapshot ayarları
+// GET /categories
+func (h *StreamHandler) ListCategories(c *gin.Context) {
+	categories, err := h.service.ListCategories(tenantFrom(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+		"count":      len(categories),
+	})
+}
+
+// ListStreams - Kategorideki stream'ler aggregate ID sırasıyla sayfa sayfa
+// GET /categories/:category/streams?aggregate_type=&active_since=&limit=&cursor=
+// active_since (RFC3339) verilirse sadece o andan sonra event almış stream'ler döner
+func (h *StreamHandler) ListStreams(c *gin.Context) {
+	query := model.StreamListQuery{
+		TenantID:      tenantFrom(c),
+		Category:      c.Param("category"),
+		AggregateType: c.Query("aggregate_type"),
+		Limit:         pageSize(c, defaultPageSize),
+	}
+
+	if activeSince := c.Query("active_since"); activeSince != "" {
+		t, err := time.Parse(time.RFC3339, activeSince)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active_since format, use RFC3339"})
+			return
+		}
+		query.ActiveSince = t
+	}
+
+	scope := "streams|" + query.TenantID + "|" + query.Category + "|" + query.AggregateType + "|" + c.Query("active_since")
+	cursor, err := decodeCursor(c.Query("cursor"), scope)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query.AfterAggregateID = cursor.AggregateID
+
+	streams, err := h.service.ListStreams(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var last string
+	if len(streams) > 0 {
+		last = streams[len(streams)-1].AggregateID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"category": query.Category,
+		"streams":  streams,
+		"count":    len(streams),
+		"next":     nextToken(len(streams) == query.Limit, scope, pageCursor{AggregateID: last}),
+	})
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHeader - İsteğin tenant'ını taşıyan header (yoksa default tenant)
+const TenantHeader = "X-Tenant-ID"
+
+const tenantContextKey = "tenant_id"
+
+// TenantScope - X-Tenant-ID header'ını doğrulayıp context'e yazar
+// Handler'lar tenant'ı sadece buradan okur; geçersiz değer 400 ile reddedilir
+func TenantScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := model.NormalizeTenantID(c.GetHeader(TenantHeader))
+		if !model.ValidTenantID(tenantID) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + TenantHeader + " header"})
+			return
+		}
+		c.Set(tenantContextKey, tenantID)
+		c.Next()
+	}
+}
+
+// tenantFrom - TenantScope'un yazdığı tenant; middleware yoksa default tenant
+func tenantFrom(c *gin.Context) string {
+	return model.NormalizeTenantID(c.GetString(tenantContextKey))
+}
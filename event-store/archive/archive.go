@@ -0,0 +1,526 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// versionRange - Bir aggregate'in partition'daki ilk ve son version'ı, tenant'ı ve stream bilgisi
+// Tenant'sız yazılmış eski index'lerde Tenant boştur (DefaultTenantID); tip, kategori ve son event
+// zamanı sonradan eklendi, eski index'lerde boştur
+type versionRange struct {
+	Min      uint32    `json:"min"`
+	Max      uint32    `json:"max"`
+	Tenant   string    `json:"tenant,omitempty"`
+	Type     string    `json:"type,omitempty"`
+	Category string    `json:"category,omitempty"`
+	Last     time.Time `json:"last,omitempty"`
+}
+
+// ownedBy - Aralık bu tenant'ın stream'ine mi ait?
+func (r versionRange) ownedBy(tenantID string) bool {
+	return model.NormalizeTenantID(r.Tenant) == model.NormalizeTenantID(tenantID)
+}
+
+// Archive - Soğuk depolama dizini: partition başına gzip'li NDJSON, aggregate index'i ve manifest
+// Index'ler ilk ihtiyaç duyulduğunda yüklenip bellekte tutulur
+type Archive struct {
+	dir string
+
+	mu       sync.RWMutex
+	manifest *Manifest
+	indexes  map[string]map[string]versionRange
+	// ids - Partition başına sıralı event ID'leri (ilk duplicate kontrolünde yüklenir)
+	ids map[string][]string
+}
+
+// Open - Dizini (yoksa) oluşturur ve manifest'i yükler
+func Open(dir string) (*Archive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive dir: %w", err)
+	}
+
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Archive{
+		dir:      dir,
+		manifest: manifest,
+		indexes:  make(map[string]map[string]versionRange),
+		ids:      make(map[string][]string),
+	}, nil
+}
+
+// Entries - Arşivlenmiş partition'lar (eskiden yeniye)
+func (a *Archive) Entries() []Entry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	entries := make([]Entry, len(a.manifest.Partitions))
+	copy(entries, a.manifest.Partitions)
+	return entries
+}
+
+// Contains - Partition arşivde mi?
+func (a *Archive) Contains(partition string) (Entry, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, entry := range a.manifest.Partitions {
+		if entry.Partition == partition {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Write - read'in ilettiği event'leri partition dosyasına yazar; manifest'e eklemez (Commit)
+// Dosyalar önce geçici isimle yazılır, sayım ve checksum tamamlanınca yerine taşınır
+func (a *Archive) Write(partition string, read func(fn func(*model.Event) error) error) (Entry, error) {
+	entry := Entry{
+		Partition:  partition,
+		File:       "events-" + partition + ".ndjson.gz",
+		IndexFile:  "events-" + partition + ".index.json.gz",
+		IDsFile:    "events-" + partition + ".ids.gz",
+		TenantRows: make(map[string]uint64),
+	}
+	index := make(map[string]versionRange)
+	var ids []string
+
+	tmp, err := os.CreateTemp(a.dir, entry.File+".tmp-*")
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	checksum := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(tmp, checksum)}
+	gz := gzip.NewWriter(counter)
+	encoder := json.NewEncoder(gz)
+
+	err = read(func(event *model.Event) error {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+		}
+
+		if entry.Rows == 0 || event.Timestamp.Before(entry.MinTime) {
+			entry.MinTime = event.Timestamp
+		}
+		if event.Timestamp.After(entry.MaxTime) {
+			entry.MaxTime = event.Timestamp
+		}
+		if entry.Rows == 0 || event.Position < entry.MinPosition {
+			entry.MinPosition = event.Position
+		}
+		if event.Position > entry.MaxPosition {
+			entry.MaxPosition = event.Position
+		}
+		entry.Rows++
+		entry.TenantRows[model.NormalizeTenantID(event.TenantID)]++
+		ids = append(ids, event.ID)
+
+		r, ok := index[event.AggregateID]
+		if !ok || event.Version < r.Min {
+			r.Min = event.Version
+		}
+		if event.Version > r.Max {
+			r.Max = event.Version
+		}
+		r.Tenant = model.NormalizeTenantID(event.TenantID)
+		r.Type = event.AggregateType
+		r.Category = event.Category
+		if event.Timestamp.After(r.Last) {
+			r.Last = event.Timestamp
+		}
+		index[event.AggregateID] = r
+		return nil
+	})
+	if err != nil {
+		tmp.Close()
+		return Entry{}, err
+	}
+
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return Entry{}, fmt.Errorf("failed to finish archive file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return Entry{}, fmt.Errorf("failed to sync archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return Entry{}, fmt.Errorf("failed to close archive file: %w", err)
+	}
+	entry.SHA256 = hex.EncodeToString(checksum.Sum(nil))
+	entry.Bytes = counter.n
+
+	indexData, indexSum, err := encodeIndex(index)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.IndexSHA256 = indexSum
+
+	if err := writeFileAtomic(filepath.Join(a.dir, entry.IndexFile), indexData); err != nil {
+		return Entry{}, fmt.Errorf("failed to write archive index: %w", err)
+	}
+
+	sort.Strings(ids)
+	idsData, idsSum, err := encodeIDs(ids)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.IDsSHA256 = idsSum
+	if err := writeFileAtomic(filepath.Join(a.dir, entry.IDsFile), idsData); err != nil {
+		return Entry{}, fmt.Errorf("failed to write archive event IDs: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(a.dir, entry.File)); err != nil {
+		return Entry{}, fmt.Errorf("failed to move archive file: %w", err)
+	}
+
+	// Yazılan dosya tekrar okunup satır sayısı doğrulanır
+	var rows uint64
+	if err := a.Read(entry, func(*model.Event) error { rows++; return nil }); err != nil {
+		return Entry{}, fmt.Errorf("failed to verify archive file: %w", err)
+	}
+	if rows != entry.Rows {
+		return Entry{}, fmt.Errorf("archive file of %s has %d rows, expected %d", partition, rows, entry.Rows)
+	}
+
+	return entry, nil
+}
+
+// Commit - Yazılmış partition'ı manifest'e ekler; bundan sonra okumalar arşivi görür
+func (a *Archive) Commit(entry Entry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry.ArchivedAt = time.Now().UTC()
+	partitions := make([]Entry, 0, len(a.manifest.Partitions)+1)
+	for _, existing := range a.manifest.Partitions {
+		if existing.Partition != entry.Partition {
+			partitions = append(partitions, existing)
+		}
+	}
+	partitions = append(partitions, entry)
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].Partition < partitions[j].Partition
+	})
+
+	manifest := &Manifest{Partitions: partitions}
+	if err := manifest.save(a.dir); err != nil {
+		return err
+	}
+	a.manifest = manifest
+	delete(a.indexes, entry.Partition)
+	delete(a.ids, entry.Partition)
+	return nil
+}
+
+// Discard - Commit edilmemiş partition dosyalarını siler
+func (a *Archive) Discard(entry Entry) {
+	os.Remove(filepath.Join(a.dir, entry.File))
+	os.Remove(filepath.Join(a.dir, entry.IndexFile))
+	if entry.IDsFile != "" {
+		os.Remove(filepath.Join(a.dir, entry.IDsFile))
+	}
+}
+
+// Read - Partition dosyasındaki event'leri position sırasıyla fn'e iletir
+// Checksum dosya okunurken hesaplanır; uyuşmazsa dosya sonunda hata döner
+func (a *Archive) Read(entry Entry, fn func(*model.Event) error) error {
+	file, err := os.Open(filepath.Join(a.dir, entry.File))
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer file.Close()
+
+	checksum := sha256.New()
+	buffered := bufio.NewReader(file)
+	gz, err := gzip.NewReader(io.TeeReader(buffered, checksum))
+	if err != nil {
+		return fmt.Errorf("failed to read archive file %s: %w", entry.File, err)
+	}
+	defer gz.Close()
+
+	decoder := json.NewDecoder(gz)
+	for {
+		var event model.Event
+		err := decoder.Decode(&event)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode archive file %s: %w", entry.File, err)
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+
+	return verifyChecksum(checksum, buffered, entry.File, entry.SHA256)
+}
+
+// Verify - Tüm arşiv dosyalarının checksum'larını kontrol eder; bozuk dosyaların hatalarını döner
+func (a *Archive) Verify(ctx context.Context) map[string]error {
+	failures := make(map[string]error)
+	for _, entry := range a.Entries() {
+		if ctx.Err() != nil {
+			failures[entry.Partition] = ctx.Err()
+			continue
+		}
+		if err := fileChecksum(filepath.Join(a.dir, entry.File), entry.SHA256); err != nil {
+			failures[entry.Partition] = err
+			continue
+		}
+		if err := fileChecksum(filepath.Join(a.dir, entry.IndexFile), entry.IndexSHA256); err != nil {
+			failures[entry.Partition] = err
+			continue
+		}
+		if entry.IDsFile != "" {
+			if err := fileChecksum(filepath.Join(a.dir, entry.IDsFile), entry.IDsSHA256); err != nil {
+				failures[entry.Partition] = err
+			}
+		}
+	}
+	return failures
+}
+
+// TenantRows - Tenant'ın arşivlenmiş event sayısı
+// TenantRows'u olmayan eski entry'lerde aggregate'lerin version aralıklarından hesaplanır
+func (a *Archive) TenantRows(tenantID string) (uint64, error) {
+	tenantID = model.NormalizeTenantID(tenantID)
+	var rows uint64
+	for _, entry := range a.Entries() {
+		if entry.TenantRows != nil {
+			rows += entry.TenantRows[tenantID]
+			continue
+		}
+		index, err := a.index(entry)
+		if err != nil {
+			return 0, err
+		}
+		for _, r := range index {
+			if r.ownedBy(tenantID) {
+				rows += uint64(r.Max-r.Min) + 1
+			}
+		}
+	}
+	return rows, nil
+}
+
+// aggregateEntries - Aggregate'in event'i bulunan partition'lar ve bu partition'lardaki version aralıkları
+func (a *Archive) aggregateEntries(aggregateID string) ([]Entry, []versionRange, error) {
+	var entries []Entry
+	var ranges []versionRange
+	for _, entry := range a.Entries() {
+		index, err := a.index(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		if r, ok := index[aggregateID]; ok {
+			entries = append(entries, entry)
+			ranges = append(ranges, r)
+		}
+	}
+	return entries, ranges, nil
+}
+
+func (a *Archive) index(entry Entry) (map[string]versionRange, error) {
+	a.mu.RLock()
+	index, ok := a.indexes[entry.Partition]
+	a.mu.RUnlock()
+	if ok {
+		return index, nil
+	}
+
+	path := filepath.Join(a.dir, entry.IndexFile)
+	if err := fileChecksum(path, entry.IndexSHA256); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive index: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive index %s: %w", entry.IndexFile, err)
+	}
+	defer gz.Close()
+
+	if err := json.NewDecoder(gz).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to decode archive index %s: %w", entry.IndexFile, err)
+	}
+
+	a.mu.Lock()
+	a.indexes[entry.Partition] = index
+	a.mu.Unlock()
+	return index, nil
+}
+
+// existingIDs - Verilen ID'lerden arşivdeki partition'larda bulunanlar
+func (a *Archive) existingIDs(eventIDs []string) (map[string]bool, error) {
+	found := make(map[string]bool)
+	for _, entry := range a.Entries() {
+		ids, err := a.eventIDs(entry)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range eventIDs {
+			if i := sort.SearchStrings(ids, id); i < len(ids) && ids[i] == id {
+				found[id] = true
+			}
+		}
+	}
+	return found, nil
+}
+
+// eventIDs - Partition'ın sıralı event ID'leri; ID dosyası olmayan eski entry'lerde partition dosyasından okunur
+func (a *Archive) eventIDs(entry Entry) ([]string, error) {
+	a.mu.RLock()
+	ids, ok := a.ids[entry.Partition]
+	a.mu.RUnlock()
+	if ok {
+		return ids, nil
+	}
+
+	if entry.IDsFile != "" {
+		loaded, err := a.readIDs(entry)
+		if err != nil {
+			return nil, err
+		}
+		ids = loaded
+	} else {
+		err := a.Read(entry, func(event *model.Event) error {
+			ids = append(ids, event.ID)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(ids)
+	}
+
+	a.mu.Lock()
+	a.ids[entry.Partition] = ids
+	a.mu.Unlock()
+	return ids, nil
+}
+
+func (a *Archive) readIDs(entry Entry) ([]string, error) {
+	path := filepath.Join(a.dir, entry.IDsFile)
+	if err := fileChecksum(path, entry.IDsSHA256); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive event IDs: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive event IDs %s: %w", entry.IDsFile, err)
+	}
+	defer gz.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		ids = append(ids, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive event IDs %s: %w", entry.IDsFile, err)
+	}
+	return ids, nil
+}
+
+// encodeIDs - Sıralı ID'ler, satır başına bir tane (gzip)
+func encodeIDs(ids []string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, id := range ids {
+		if _, err := io.WriteString(gz, id+"\n"); err != nil {
+			return nil, "", fmt.Errorf("failed to encode archive event IDs: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode archive event IDs: %w", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), hex.EncodeToString(sum[:]), nil
+}
+
+func encodeIndex(index map[string]versionRange) ([]byte, string, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(index); err != nil {
+		return nil, "", fmt.Errorf("failed to encode archive index: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode archive index: %w", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), hex.EncodeToString(sum[:]), nil
+}
+
+func fileChecksum(path, expected string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	checksum := sha256.New()
+	if _, err := io.Copy(checksum, file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if actual := hex.EncodeToString(checksum.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", filepath.Base(path), expected, actual)
+	}
+	return nil
+}
+
+// verifyChecksum - gzip okuyucusunun tüketmediği kuyruğu da hash'e ekleyip karşılaştırır
+func verifyChecksum(checksum hash.Hash, rest io.Reader, name, expected string) error {
+	if _, err := io.Copy(checksum, rest); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if actual := hex.EncodeToString(checksum.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,363 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// memoryPartitions - MemoryEventRepository üstünde toYYYYMM(timestamp) partition'ları
+type memoryPartitions struct {
+	*repository.MemoryEventRepository
+	dropped map[string]bool
+}
+
+func (m *memoryPartitions) all() []*model.Event {
+	events, _ := m.MemoryEventRepository.GetEvents(model.EventFilter{FromPosition: 1})
+	var live []*model.Event
+	for _, event := range events {
+		if !m.dropped[event.Timestamp.UTC().Format("200601")] {
+			live = append(live, event)
+		}
+	}
+	return live
+}
+
+func (m *memoryPartitions) ListPartitions() ([]repository.EventPartition, error) {
+	var partitions []repository.EventPartition
+	for _, event := range m.all() {
+		id := event.Timestamp.UTC().Format("200601")
+		if len(partitions) == 0 || partitions[len(partitions)-1].ID != id {
+			partitions = append(partitions, repository.EventPartition{ID: id})
+		}
+		partitions[len(partitions)-1].Rows++
+	}
+	return partitions, nil
+}
+
+func (m *memoryPartitions) CountPartition(partition string) (uint64, error) {
+	var count uint64
+	for _, event := range m.all() {
+		if event.Timestamp.UTC().Format("200601") == partition {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryPartitions) ReadPartition(ctx context.Context, partition string, fn func(*model.Event) error) error {
+	for _, event := range m.all() {
+		if event.Timestamp.UTC().Format("200601") == partition {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryPartitions) DropPartition(partition string) error {
+	m.dropped[partition] = true
+	return nil
+}
+
+func (m *memoryPartitions) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
+	var events []*model.Event
+	for _, event := range m.all() {
+		if filter.Matches(event) {
+			events = append(events, event)
+		}
+	}
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+	return events, nil
+}
+
+func (m *memoryPartitions) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	for _, event := range m.all() {
+		if query.Matches(event) {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryPartitions) GetLatestVersionForAggregate(aggregateID string) (uint32, error) {
+	var version uint32
+	for _, event := range m.all() {
+		if event.AggregateID == aggregateID && event.Version > version {
+			version = event.Version
+		}
+	}
+	return version, nil
+}
+
+func (m *memoryPartitions) GetLastPosition() (uint64, error) {
+	events := m.all()
+	if len(events) == 0 {
+		return 0, nil
+	}
+	return events[len(events)-1].Position, nil
+}
+
+func (m *memoryPartitions) CountEvents(tenantID string) (uint64, error) {
+	var count uint64
+	for _, event := range m.all() {
+		if model.NormalizeTenantID(event.TenantID) == model.NormalizeTenantID(tenantID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryPartitions) ListStreams(query model.StreamListQuery) ([]*model.StreamInfo, error) {
+	live := repository.NewMemoryEventRepository()
+	if err := live.SaveEvents(m.all()); err != nil {
+		return nil, err
+	}
+	return live.ListStreams(query)
+}
+
+// seedMonths - user-1 için Ocak, Şubat ve Mart'ta birer event, user-2 için Mart'ta bir event
+func seedMonths(t *testing.T) *memoryPartitions {
+	t.Helper()
+	repo := &memoryPartitions{MemoryEventRepository: repository.NewMemoryEventRepository(), dropped: map[string]bool{}}
+	events := []*model.Event{
+		{ID: "e1", EventType: "user.created", AggregateID: "user-1", Version: 1, Timestamp: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: "e2", EventType: "user.email.changed", AggregateID: "user-1", Version: 2, Timestamp: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: "e3", EventType: "user.email.changed", AggregateID: "user-1", Version: 3, Timestamp: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: "e4", EventType: "user.created", AggregateID: "user-2", Version: 1, Timestamp: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, event := range events {
+		event.Position = uint64(i + 1)
+		event.Payload = fmt.Sprintf(`{"n":%d}`, i+1)
+		if err := repo.SaveEvent(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func newTestArchiver(t *testing.T, repo *memoryPartitions) (*Archive, *Archiver) {
+	t.Helper()
+	archive, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	archiver := NewArchiver(repo, archive, 1)
+	archiver.now = func() time.Time { return time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC) }
+	return archive, archiver
+}
+
+func TestArchiverExportsClosedPartitionsAndDropsThem(t *testing.T) {
+	repo := seedMonths(t)
+	archive, archiver := newTestArchiver(t, repo)
+
+	result, err := archiver.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.Archived) != 2 || result.Archived[0].Partition != "202401" || result.Archived[1].Partition != "202402" {
+		t.Fatalf("expected January and February to be archived, got %+v", result.Archived)
+	}
+	if !repo.dropped["202401"] || !repo.dropped["202402"] || repo.dropped["202403"] {
+		t.Errorf("unexpected dropped partitions: %v", repo.dropped)
+	}
+
+	reopened, err := Open(archive.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := reopened.Entries()
+	if len(entries) != 2 || entries[0].Rows != 1 || entries[0].SHA256 == "" || entries[1].MaxPosition != 2 {
+		t.Fatalf("unexpected manifest: %+v", entries)
+	}
+	if failures := reopened.Verify(context.Background()); len(failures) != 0 {
+		t.Errorf("expected valid checksums, got %v", failures)
+	}
+
+	// Bozulan dosya checksum kontrolünde yakalanır
+	path := filepath.Join(archive.dir, entries[0].File)
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if failures := reopened.Verify(context.Background()); failures["202401"] == nil {
+		t.Error("expected corrupted file to fail verification")
+	}
+}
+
+func TestStoreFallsBackToArchive(t *testing.T) {
+	repo := seedMonths(t)
+	archive, archiver := newTestArchiver(t, repo)
+	if _, err := archiver.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewStore(repo, archive, ReadFallback)
+
+	var versions []uint32
+	err := store.ReadStream(context.Background(), model.StreamQuery{AggregateID: "user-1"}, func(event *model.Event) error {
+		versions = append(versions, event.Version)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadStream: %v", err)
+	}
+	if fmt.Sprint(versions) != "[1 2 3]" {
+		t.Errorf("expected archived and live versions in order, got %v", versions)
+	}
+
+	versions = nil
+	err = store.ReadStream(context.Background(), model.StreamQuery{AggregateID: "user-1", Direction: model.ReadBackward, MaxCount: 2}, func(event *model.Event) error {
+		versions = append(versions, event.Version)
+		return nil
+	})
+	if err != nil || fmt.Sprint(versions) != "[3 2]" {
+		t.Errorf("expected backward read [3 2], got %v (%v)", versions, err)
+	}
+
+	events, err := store.GetEvents(model.EventFilter{FromPosition: 1, Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "e2" || events[1].ID != "e3" {
+		t.Errorf("expected e2 and e3, got %+v", events)
+	}
+
+	if version, _ := store.GetLatestVersionForAggregate("user-1"); version != 3 {
+		t.Errorf("expected latest version 3, got %d", version)
+	}
+	if count, _ := store.CountEvents(model.DefaultTenantID); count != 4 {
+		t.Errorf("expected 4 events including archive, got %d", count)
+	}
+
+	tail, err := store.StreamTail(model.DefaultTenantID, "user-1", 2)
+	if err != nil || tail.Events != 2 || tail.Bytes != 14 || !tail.FirstAt.Equal(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected tail of archived and live events, got %+v (%v)", tail, err)
+	}
+
+	// Tamamen arşivlenmiş aggregate'in version'ı ve global position kaybolmaz
+	repo.dropped["202403"] = true
+	if position, _ := store.GetLastPosition(); position != 2 {
+		t.Errorf("expected last position from archive, got %d", position)
+	}
+	if version, _ := store.GetLatestVersionForAggregate("user-1"); version != 2 {
+		t.Errorf("expected archived latest version 2, got %d", version)
+	}
+}
+
+func TestStoreReportsArchivedRanges(t *testing.T) {
+	repo := seedMonths(t)
+	archive, archiver := newTestArchiver(t, repo)
+	if _, err := archiver.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewStore(repo, archive, ReadReport)
+
+	err := store.ReadStream(context.Background(), model.StreamQuery{AggregateID: "user-1"}, func(*model.Event) error { return nil })
+	if !errors.Is(err, repository.ErrArchived) {
+		t.Errorf("expected ErrArchived, got %v", err)
+	}
+
+	// Arşive dokunmayan okumalar etkilenmez
+	events, err := store.GetEventsAfterVersion(model.DefaultTenantID, "user-1", 2)
+	if err != nil || len(events) != 1 || events[0].Version != 3 {
+		t.Errorf("expected live event only, got %+v (%v)", events, err)
+	}
+	if _, err := store.GetEvents(model.EventFilter{StartTime: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Errorf("expected live time range to be readable, got %v", err)
+	}
+	if _, err := store.GetEvents(model.EventFilter{}); !errors.Is(err, repository.ErrArchived) {
+		t.Errorf("expected full scan to report archive, got %v", err)
+	}
+}
+
+func TestStoreListsArchivedStreams(t *testing.T) {
+	repo := seedMonths(t)
+	// user-0'ın tek event'i Ocak'ta; arşivlenince canlı tabloda event'i kalmaz
+	old := &model.Event{ID: "e5", EventType: "user.created", AggregateID: "user-0", Version: 1, Position: 5,
+		Timestamp: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC), Payload: `{"n":5}`}
+	if err := repo.SaveEvent(old); err != nil {
+		t.Fatal(err)
+	}
+	archive, archiver := newTestArchiver(t, repo)
+	if _, err := archiver.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []ReadMode{ReadFallback, ReadReport} {
+		store := NewStore(repo, archive, mode)
+		streams, err := store.ListStreams(model.StreamListQuery{})
+		if err != nil {
+			t.Fatalf("ListStreams: %v", err)
+		}
+		var listed []string
+		for _, stream := range streams {
+			listed = append(listed, fmt.Sprintf("%s:%s:%d:%d", stream.AggregateID, stream.AggregateType, stream.Version, stream.EventCount))
+		}
+		if fmt.Sprint(listed) != "[user-0:user:1:1 user-1:user:3:3 user-2:user:1:1]" {
+			t.Errorf("%s: expected archived stream and archived event counts, got %v", mode, listed)
+		}
+
+		page, _ := store.ListStreams(model.StreamListQuery{Limit: 1})
+		next, _ := store.ListStreams(model.StreamListQuery{AfterAggregateID: "user-0", Limit: 1})
+		if len(page) != 1 || page[0].AggregateID != "user-0" || len(next) != 1 || next[0].AggregateID != "user-1" {
+			t.Errorf("%s: expected pages [user-0] [user-1], got %+v %+v", mode, page, next)
+		}
+
+		if others, _ := store.ListStreams(model.StreamListQuery{AggregateType: "order"}); len(others) != 0 {
+			t.Errorf("%s: expected type filter to apply to archived streams, got %+v", mode, others)
+		}
+		active, _ := store.ListStreams(model.StreamListQuery{ActiveSince: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)})
+		if len(active) != 2 || active[0].AggregateID != "user-1" {
+			t.Errorf("%s: expected only streams active in March, got %+v", mode, active)
+		}
+	}
+}
+
+func TestStoreDeduplicatesArchivedEventIDs(t *testing.T) {
+	repo := seedMonths(t)
+	archive, archiver := newTestArchiver(t, repo)
+	if _, err := archiver.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(repo, archive, ReadFallback)
+
+	if exists, err := store.EventExists("e1"); err != nil || !exists {
+		t.Errorf("expected archived event e1 to exist, got %v (%v)", exists, err)
+	}
+	existing, err := store.FindExistingEventIDs([]string{"e2", "e4", "missing"})
+	if err != nil {
+		t.Fatalf("FindExistingEventIDs: %v", err)
+	}
+	if len(existing) != 2 || !existing["e2"] || !existing["e4"] {
+		t.Errorf("expected archived e2 and live e4, got %v", existing)
+	}
+
+	// ID dosyası olmayan eski entry'lerde ID'ler partition dosyasından okunur
+	archive.mu.Lock()
+	for i := range archive.manifest.Partitions {
+		archive.manifest.Partitions[i].IDsFile = ""
+	}
+	archive.ids = make(map[string][]string)
+	archive.mu.Unlock()
+	if exists, err := store.EventExists("e2"); err != nil || !exists {
+		t.Errorf("expected e2 to be found without an IDs file, got %v (%v)", exists, err)
+	}
+	if failures := archive.Verify(context.Background()); len(failures) != 0 {
+		t.Errorf("expected archive to verify, got %v", failures)
+	}
+}
@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// Archiver - Kapanmış aylık partition'ları arşive taşıyan job
+// Sıra: dosyaya yaz + doğrula -> partition'ı tekrar say -> manifest'e ekle -> partition'ı drop et.
+// Manifest drop'tan önce güncellendiği için okumalar hiçbir an boşluk görmez
+type Archiver struct {
+	source  repository.PartitionStore
+	archive *Archive
+	// afterMonths - İçinde bulunulan aydan kaç ay önceki partition'lar arşivlenir
+	afterMonths int
+	now         func() time.Time
+}
+
+// Result - Bir çalıştırmada arşivlenen partition'lar
+type Result struct {
+	Archived []Entry  `json:"archived"`
+	Skipped  []string `json:"skipped,omitempty"`
+}
+
+func NewArchiver(source repository.PartitionStore, archive *Archive, afterMonths int) *Archiver {
+	if afterMonths < 1 {
+		// İçinde bulunulan ay hâlâ yazılıyor, en az bir ay beklenir
+		afterMonths = 1
+	}
+	return &Archiver{source: source, archive: archive, afterMonths: afterMonths, now: time.Now}
+}
+
+// Cutoff - Bu partition'dan (dahil değil) eski partition'lar arşivlenir
+func (a *Archiver) Cutoff() string {
+	now := a.now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -a.afterMonths+1, 0)
+	return month.Format("200601")
+}
+
+// Run - Cutoff'tan eski tüm partition'ları arşivler
+// Bir partition'da hata olursa sonraki partition'lara geçilmez (sıra korunur)
+func (a *Archiver) Run(ctx context.Context) (Result, error) {
+	var result Result
+
+	partitions, err := a.source.ListPartitions()
+	if err != nil {
+		return result, err
+	}
+
+	cutoff := a.Cutoff()
+	for _, partition := range partitions {
+		if partition.ID >= cutoff {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		// Önceki çalıştırma manifest'i yazıp drop edemeden durmuş olabilir
+		if entry, ok := a.archive.Contains(partition.ID); ok {
+			if err := a.drop(entry); err != nil {
+				log.Printf("archive: partition %s is archived but could not be dropped: %v", partition.ID, err)
+				result.Skipped = append(result.Skipped, partition.ID)
+			}
+			continue
+		}
+
+		entry, err := a.archivePartition(ctx, partition.ID)
+		if err != nil {
+			return result, fmt.Errorf("failed to archive partition %s: %w", partition.ID, err)
+		}
+		result.Archived = append(result.Archived, entry)
+		log.Printf("archive: partition %s archived (%d rows, %d bytes)", entry.Partition, entry.Rows, entry.Bytes)
+	}
+
+	return result, nil
+}
+
+func (a *Archiver) archivePartition(ctx context.Context, partition string) (Entry, error) {
+	entry, err := a.archive.Write(partition, func(fn func(*model.Event) error) error {
+		return a.source.ReadPartition(ctx, partition, fn)
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+
+	// Export sırasında geç gelen (eski timestamp'li) event yazıldıysa partition drop edilmez
+	rows, err := a.source.CountPartition(partition)
+	if err != nil {
+		a.archive.Discard(entry)
+		return Entry{}, err
+	}
+	if rows != entry.Rows {
+		a.archive.Discard(entry)
+		return Entry{}, fmt.Errorf("partition changed during export: archived %d rows, table has %d", entry.Rows, rows)
+	}
+
+	if err := a.archive.Commit(entry); err != nil {
+		a.archive.Discard(entry)
+		return Entry{}, err
+	}
+
+	if err := a.drop(entry); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+func (a *Archiver) drop(entry Entry) error {
+	rows, err := a.source.CountPartition(entry.Partition)
+	if err != nil {
+		return err
+	}
+	if rows != entry.Rows {
+		return fmt.Errorf("partition has %d rows, archive has %d", rows, entry.Rows)
+	}
+	return a.source.DropPartition(entry.Partition)
+}
+
+// Start - Run'ı interval aralıklarla çalıştırır (ctx iptal edilene kadar)
+func (a *Archiver) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.Run(ctx); err != nil {
+			log.Printf("archive: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
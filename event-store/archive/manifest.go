@@ -0,0 +1,94 @@
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifestFile - Arşiv dizinindeki partition listesi
+const manifestFile = "manifest.json"
+
+// Manifest - Arşivlenmiş partition'lar (eskiden yeniye)
+type Manifest struct {
+	Partitions []Entry `json:"partitions"`
+}
+
+// Entry - Tek bir arşivlenmiş partition
+// Dosyalar: events-<partition>.ndjson.gz (her satır bir event),
+// events-<partition>.index.json.gz (aggregate_id -> version aralığı) ve
+// events-<partition>.ids.gz (ingest'te tekrar gelen event'leri bulmak için event ID'leri)
+type Entry struct {
+	Partition   string `json:"partition"`
+	File        string `json:"file"`
+	SHA256      string `json:"sha256"`
+	Bytes       int64  `json:"bytes"`
+	IndexFile   string `json:"index_file"`
+	IndexSHA256 string `json:"index_sha256"`
+	// IDsFile - Sıralı event ID'leri (events-<partition>.ids.gz); eski manifest'lerde yok,
+	// ID'ler partition dosyasından okunur
+	IDsFile   string `json:"ids_file,omitempty"`
+	IDsSHA256 string `json:"ids_sha256,omitempty"`
+	Rows      uint64 `json:"rows"`
+	// TenantRows - Tenant başına satır sayısı (eski manifest'lerde yok, index'ten hesaplanır)
+	TenantRows  map[string]uint64 `json:"tenant_rows,omitempty"`
+	MinTime     time.Time         `json:"min_time"`
+	MaxTime     time.Time         `json:"max_time"`
+	MinPosition uint64            `json:"min_position"`
+	MaxPosition uint64            `json:"max_position"`
+	ArchivedAt  time.Time         `json:"archived_at"`
+}
+
+// loadManifest - Manifest yoksa boş döner
+func loadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse archive manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// save - Manifest'i geçici dosyaya yazıp rename eder; yarım yazılmış manifest kalmaz
+func (m *Manifest) save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal archive manifest: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, manifestFile), data); err != nil {
+		return fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,557 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// ReadMode - Arşivlenmiş aralığa düşen okumaların davranışı
+type ReadMode string
+
+const (
+	// ReadFallback - Arşivdeki event'ler dosyalardan okunup canlı event'lerle birleştirilir
+	ReadFallback ReadMode = "fallback"
+	// ReadReport - Okuma repository.ErrArchived ile reddedilir (API 410 döner)
+	ReadReport ReadMode = "report"
+)
+
+// ParseReadMode - ARCHIVE_READ_MODE değeri (boş = fallback)
+func ParseReadMode(value string) (ReadMode, error) {
+	switch ReadMode(value) {
+	case "", ReadFallback:
+		return ReadFallback, nil
+	case ReadReport:
+		return ReadReport, nil
+	default:
+		return "", fmt.Errorf("unknown archive read mode %q (expected fallback or report)", value)
+	}
+}
+
+// errStop - MaxCount/Limit dolunca okumayı durdurmak için
+var errStop = errors.New("stop reading")
+
+// Store - Drop edilmiş partition'ları arşivden okuyan EventStore decorator'ı
+// Arşivlenmiş event'ler her zaman canlı event'lerden eskidir (kapanmış aylar); bu yüzden
+// arşivdeki eşleşmeler önce, canlı tablodakiler sonra döner. Version ve position hesapları
+// (GetLatestVersionForAggregate, GetLastPosition) arşivi her modda hesaba katar.
+// ListStreams tamamen arşivlenmiş stream'leri de döner; kategori listesi (ListCategories) sadece
+// canlı tabloyu sayar
+type Store struct {
+	repository.EventStore
+	archive *Archive
+	mode    ReadMode
+}
+
+func NewStore(store repository.EventStore, archive *Archive, mode ReadMode) *Store {
+	return &Store{EventStore: store, archive: archive, mode: mode}
+}
+
+func (s *Store) CountEvents(tenantID string) (uint64, error) {
+	count, err := s.EventStore.CountEvents(tenantID)
+	if err != nil {
+		return 0, err
+	}
+	archived, err := s.archive.TenantRows(tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return count + archived, nil
+}
+
+// GetLastPosition - Tüm event'ler arşivlenmiş olsa bile position'lar kaldığı yerden devam eder
+func (s *Store) GetLastPosition() (uint64, error) {
+	position, err := s.EventStore.GetLastPosition()
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range s.archive.Entries() {
+		if entry.MaxPosition > position {
+			position = entry.MaxPosition
+		}
+	}
+	return position, nil
+}
+
+// GetLatestVersionForAggregate - Canlı event'i kalmamış aggregate'ler için arşivdeki son version
+func (s *Store) GetLatestVersionForAggregate(aggregateID string) (uint32, error) {
+	version, err := s.EventStore.GetLatestVersionForAggregate(aggregateID)
+	if err != nil || version > 0 {
+		return version, err
+	}
+
+	_, ranges, err := s.archive.aggregateEntries(aggregateID)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range ranges {
+		if r.Max > version {
+			version = r.Max
+		}
+	}
+	return version, nil
+}
+
+// EventExists - Canlı tabloda yoksa arşivlenmiş event ID'lerine de bakılır
+func (s *Store) EventExists(eventID string) (bool, error) {
+	existing, err := s.FindExistingEventIDs([]string{eventID})
+	if err != nil {
+		return false, err
+	}
+	return existing[eventID], nil
+}
+
+// FindExistingEventIDs - Ingest deduplication'ı arşivlenmiş event'leri de görür; tekrar gelen eski
+// bir event (Kafka replay) arşivlendikten sonra yeniden yazılmaz
+func (s *Store) FindExistingEventIDs(eventIDs []string) (map[string]bool, error) {
+	existing, err := s.EventStore.FindExistingEventIDs(eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		existing = make(map[string]bool)
+	}
+
+	var missing []string
+	for _, id := range eventIDs {
+		if !existing[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 || len(s.archive.Entries()) == 0 {
+		return existing, nil
+	}
+
+	archived, err := s.archive.existingIDs(missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check archived event IDs: %w", err)
+	}
+	for id := range archived {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+// ListStreams - Canlı stream'lere event'i tamamen arşivde kalmış stream'leri de ekler
+// Aynı stream'in arşivdeki event'leri EventCount'a eklenir. Bulk snapshot rebuild (restore sonrası)
+// stream'leri buradan sayfaladığı için arşivlenmiş aggregate'ler atlanmaz
+func (s *Store) ListStreams(query model.StreamListQuery) ([]*model.StreamInfo, error) {
+	live, err := s.EventStore.ListStreams(query)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.archivedStreams(query.TenantID, query.AfterAggregateID)
+	if err != nil {
+		return nil, err
+	}
+	if len(archived) == 0 {
+		return live, nil
+	}
+
+	streams := make([]*model.StreamInfo, 0, len(live))
+	for _, stream := range live {
+		if old, ok := archived[stream.AggregateID]; ok {
+			stream.EventCount += old.EventCount
+			delete(archived, stream.AggregateID)
+		}
+		streams = append(streams, stream)
+	}
+
+	ids := make([]string, 0, len(archived))
+	for id := range archived {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// Canlı sayfa doluysa ondan sonraki ID'ler bir sonraki sayfaya kalır
+	var lastLive string
+	if query.Limit > 0 && len(live) >= query.Limit {
+		lastLive = live[len(live)-1].AggregateID
+	}
+	added := 0
+	for _, id := range ids {
+		if (lastLive != "" && id > lastLive) || (query.Limit > 0 && added >= query.Limit) {
+			break
+		}
+		stream := archived[id]
+		if stream.AggregateType == "" {
+			// Tip bilgisi olmayan eski index: stream'in ilk event'inden okunur
+			if err := s.resolveStreamType(query.TenantID, stream); err != nil {
+				return nil, err
+			}
+		}
+		if !archivedStreamMatches(query, stream) {
+			continue
+		}
+		streams = append(streams, stream)
+		added++
+	}
+
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].AggregateID < streams[j].AggregateID
+	})
+	if query.Limit > 0 && len(streams) > query.Limit {
+		streams = streams[:query.Limit]
+	}
+	return streams, nil
+}
+
+// archivedStreams - Tenant'ın arşivde event'i olan, afterAggregateID'den sonraki stream'leri
+func (s *Store) archivedStreams(tenantID, afterAggregateID string) (map[string]*model.StreamInfo, error) {
+	streams := make(map[string]*model.StreamInfo)
+	for _, entry := range s.archive.Entries() {
+		index, err := s.archive.index(entry)
+		if err != nil {
+			return nil, err
+		}
+		for aggregateID, r := range index {
+			if aggregateID <= afterAggregateID || !r.ownedBy(tenantID) {
+				continue
+			}
+			stream, ok := streams[aggregateID]
+			if !ok {
+				stream = &model.StreamInfo{AggregateID: aggregateID}
+				streams[aggregateID] = stream
+			}
+			if r.Type != "" {
+				stream.AggregateType = r.Type
+				stream.Category = model.NormalizeCategory(r.Category, r.Type)
+			}
+			stream.EventCount += uint64(r.Max-r.Min) + 1
+			if r.Max > stream.Version {
+				stream.Version = r.Max
+			}
+			// Son event zamanı olmayan eski index'te partition'ın son zamanı (üst sınır) kullanılır
+			last := r.Last
+			if last.IsZero() {
+				last = entry.MaxTime
+			}
+			if last.After(stream.LastEventAt) {
+				stream.LastEventAt = last
+			}
+		}
+	}
+	return streams, nil
+}
+
+// resolveStreamType - Stream'in tipini ve kategorisini arşivdeki ilk event'inden okur (okuma modundan bağımsız)
+func (s *Store) resolveStreamType(tenantID string, stream *model.StreamInfo) error {
+	entries, _, err := s.tenantEntries(tenantID, stream.AggregateID)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	err = s.archive.Read(entries[0], func(event *model.Event) error {
+		if event.AggregateID != stream.AggregateID {
+			return nil
+		}
+		stream.AggregateType = model.NormalizeAggregateType(event.AggregateType)
+		stream.Category = model.NormalizeCategory(event.Category, event.AggregateType)
+		return errStop
+	})
+	return ignoreStop(err)
+}
+
+// archivedStreamMatches - Arşivdeki stream sorgunun tip, kategori ve aktiflik filtrelerine uyuyor mu?
+func archivedStreamMatches(query model.StreamListQuery, stream *model.StreamInfo) bool {
+	if query.Category != "" && stream.Category != query.Category {
+		return false
+	}
+	if query.AggregateType != "" && stream.AggregateType != query.AggregateType {
+		return false
+	}
+	return query.ActiveSince.IsZero() || !stream.LastEventAt.Before(query.ActiveSince)
+}
+
+// StreamTail - Aralık arşive uzanıyorsa arşivdeki event'ler dosyadan okunarak eklenir (her modda)
+// Canlı tablo arşivin son version'ından sonrası için sayılır; drop edilmeyi bekleyen satırlar iki kez sayılmaz
+func (s *Store) StreamTail(tenantID, aggregateID string, fromVersion uint32) (*model.StreamTail, error) {
+	entries, ranges, err := s.tenantEntries(tenantID, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	var archivedMax uint32
+	for _, r := range ranges {
+		if r.Max > archivedMax {
+			archivedMax = r.Max
+		}
+	}
+	if len(entries) == 0 || fromVersion > archivedMax {
+		return s.EventStore.StreamTail(tenantID, aggregateID, fromVersion)
+	}
+
+	query := model.StreamQuery{TenantID: tenantID, AggregateID: aggregateID, FromVersion: fromVersion}
+	archived, err := s.readArchived(context.Background(), entries, query.Matches)
+	if err != nil {
+		return nil, err
+	}
+	tail, err := s.EventStore.StreamTail(tenantID, aggregateID, archivedMax+1)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range archived {
+		tail.Events++
+		tail.Bytes += uint64(len(event.Payload))
+		if tail.FirstAt.IsZero() || event.Timestamp.Before(tail.FirstAt) {
+			tail.FirstAt = event.Timestamp
+		}
+	}
+	return tail, nil
+}
+
+func (s *Store) GetEventsAfterVersion(tenantID, aggregateID string, afterVersion uint32) ([]*model.Event, error) {
+	var events []*model.Event
+	err := s.ReadStream(context.Background(), model.StreamQuery{
+		TenantID:    tenantID,
+		AggregateID: aggregateID,
+		FromVersion: afterVersion + 1,
+	}, func(event *model.Event) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Store) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	entries, ranges, err := s.tenantEntries(query.TenantID, query.AggregateID)
+	if err != nil {
+		return err
+	}
+
+	var archivedMin, archivedMax uint32
+	for i, r := range ranges {
+		if i == 0 || r.Min < archivedMin {
+			archivedMin = r.Min
+		}
+		if r.Max > archivedMax {
+			archivedMax = r.Max
+		}
+	}
+
+	// Sorgunun version aralığı arşivle kesişmiyorsa sadece canlı tablo okunur
+	if len(entries) == 0 || query.FromVersion > archivedMax || (query.ToVersion > 0 && query.ToVersion < archivedMin) {
+		return s.EventStore.ReadStream(ctx, query, fn)
+	}
+	if s.mode == ReadReport {
+		return fmt.Errorf("%w: aggregate %s versions %d-%d", repository.ErrArchived, query.AggregateID, archivedMin, archivedMax)
+	}
+
+	emitted := 0
+	emit := func(event *model.Event) error {
+		if query.MaxCount > 0 && emitted >= query.MaxCount {
+			return errStop
+		}
+		emitted++
+		return fn(event)
+	}
+
+	liveQuery := query
+	liveQuery.FromVersion = archivedMax + 1
+	readLive := query.ToVersion == 0 || query.ToVersion > archivedMax
+	readLiveWith := func(emitFn func(*model.Event) error) error {
+		if !readLive {
+			return nil
+		}
+		if query.MaxCount > 0 {
+			liveQuery.MaxCount = query.MaxCount - emitted
+			if liveQuery.MaxCount <= 0 {
+				return nil
+			}
+		}
+		return s.EventStore.ReadStream(ctx, liveQuery, emitFn)
+	}
+
+	if query.Direction == model.ReadBackward {
+		if err := readLiveWith(emit); err != nil {
+			return ignoreStop(err)
+		}
+		archived, err := s.readArchived(ctx, entries, query.Matches)
+		if err != nil {
+			return err
+		}
+		for i := len(archived) - 1; i >= 0; i-- {
+			if err := emit(archived[i]); err != nil {
+				return ignoreStop(err)
+			}
+		}
+		return nil
+	}
+
+	for _, entry := range entries {
+		err := s.archive.Read(entry, func(event *model.Event) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !query.Matches(event) {
+				return nil
+			}
+			return emit(event)
+		})
+		if err != nil {
+			return ignoreStop(err)
+		}
+	}
+
+	return ignoreStop(readLiveWith(emit))
+}
+
+// tenantEntries - Aggregate'in sadece bu tenant'a ait arşiv aralıkları
+// Başka tenant'ın stream'i arşivde olsa da görünmez (report modunda da ErrArchived dönmez)
+func (s *Store) tenantEntries(tenantID, aggregateID string) ([]Entry, []versionRange, error) {
+	entries, ranges, err := s.archive.aggregateEntries(aggregateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var owned []Entry
+	var ownedRanges []versionRange
+	for i, r := range ranges {
+		if r.ownedBy(tenantID) {
+			owned = append(owned, entries[i])
+			ownedRanges = append(ownedRanges, r)
+		}
+	}
+	return owned, ownedRanges, nil
+}
+
+// GetEvents - Filtre arşivlenmiş partition'lara dokunuyorsa önce arşivdeki eşleşmeler döner
+// Offset/Limit arşiv + canlı tablo birlikte tek liste gibi uygulanır
+func (s *Store) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
+	entries, err := s.overlapping(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return s.liveEvents(filter)
+	}
+	if s.mode == ReadReport {
+		return nil, fmt.Errorf("%w: partitions %s-%s", repository.ErrArchived, entries[0].Partition, entries[len(entries)-1].Partition)
+	}
+
+	var events []*model.Event
+	matched := 0
+	for _, entry := range entries {
+		err := s.archive.Read(entry, func(event *model.Event) error {
+			if !filter.Matches(event) {
+				return nil
+			}
+			matched++
+			if matched <= filter.Offset {
+				return nil
+			}
+			if filter.Limit > 0 && len(events) >= filter.Limit {
+				return errStop
+			}
+			events = append(events, event)
+			return nil
+		})
+		if errors.Is(err, errStop) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	liveFilter := filter
+	liveFilter.Offset = 0
+	if filter.Offset > matched {
+		liveFilter.Offset = filter.Offset - matched
+	}
+	if filter.Limit > 0 {
+		liveFilter.Limit = filter.Limit - len(events)
+		if liveFilter.Limit == 0 {
+			return events, nil
+		}
+	}
+
+	live, err := s.liveEvents(liveFilter)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, live...), nil
+}
+
+// liveEvents - Canlı tablodan okur; arşivlenip henüz drop edilmemiş partition'daki
+// satırlar (Commit ile drop arasındaki kısa an) arşivden zaten döndüğü için atlanır
+func (s *Store) liveEvents(filter model.EventFilter) ([]*model.Event, error) {
+	events, err := s.EventStore.GetEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	archived := make(map[string]bool)
+	for _, entry := range s.archive.Entries() {
+		archived[entry.Partition] = true
+	}
+	if len(archived) == 0 {
+		return events, nil
+	}
+
+	live := events[:0]
+	for _, event := range events {
+		if !archived[event.Timestamp.UTC().Format("200601")] {
+			live = append(live, event)
+		}
+	}
+	return live, nil
+}
+
+// overlapping - Filtrenin zaman/position/aggregate kriterleriyle kesişen arşiv partition'ları
+func (s *Store) overlapping(filter model.EventFilter) ([]Entry, error) {
+	var entries []Entry
+	for _, entry := range s.archive.Entries() {
+		if !filter.StartTime.IsZero() && entry.MaxTime.Before(filter.StartTime) {
+			continue
+		}
+		if !filter.EndTime.IsZero() && entry.MinTime.After(filter.EndTime) {
+			continue
+		}
+		if filter.FromPosition > entry.MaxPosition {
+			continue
+		}
+		if filter.AggregateID != "" {
+			index, err := s.archive.index(entry)
+			if err != nil {
+				return nil, err
+			}
+			if r, ok := index[filter.AggregateID]; !ok || !r.ownedBy(filter.TenantID) {
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (s *Store) readArchived(ctx context.Context, entries []Entry, match func(*model.Event) bool) ([]*model.Event, error) {
+	var events []*model.Event
+	for _, entry := range entries {
+		err := s.archive.Read(entry, func(event *model.Event) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if match(event) {
+				events = append(events, event)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func ignoreStop(err error) error {
+	if errors.Is(err, errStop) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,296 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+//go:embed catalog.json
+var builtinCatalog []byte
+
+// SnapshotSettings - Otomatik snapshot politikası (0/boş olan alan bir üst seviyeden gelir)
+// Tetikleyicilerden biri tutunca ve son snapshot'tan sonra yeni event varsa snapshot alınır
+type SnapshotSettings struct {
+	// FirstAfter - Snapshot'ı olmayan stream'de ilk snapshot bu version'a ulaşınca alınır
+	FirstAfter uint32 `json:"first_after,omitempty"`
+	// Interval - Son snapshot'tan sonra bu kadar event birikince yeni snapshot alınır
+	Interval uint32 `json:"interval,omitempty"`
+	// MaxAge - Son snapshot (yoksa stream'in ilk event'i) bu süreden eskiyse snapshot alınır
+	MaxAge Duration `json:"max_age,omitempty"`
+	// MaxReplayBytes - Son snapshot'tan sonraki event payload'ları toplamı (replay maliyeti) bu boyutu geçince
+	MaxReplayBytes uint64 `json:"max_replay_bytes,omitempty"`
+	// TriggerEventTypes - Bu tiplerden bir event yazılınca hemen snapshot alınır
+	TriggerEventTypes []string `json:"trigger_event_types,omitempty"`
+}
+
+// withDefaults - Boş alanları defaults'tan doldurur
+func (s SnapshotSettings) withDefaults(defaults SnapshotSettings) SnapshotSettings {
+	if s.FirstAfter == 0 {
+		s.FirstAfter = defaults.FirstAfter
+	}
+	if s.Interval == 0 {
+		s.Interval = defaults.Interval
+	}
+	if s.MaxAge == 0 {
+		s.MaxAge = defaults.MaxAge
+	}
+	if s.MaxReplayBytes == 0 {
+		s.MaxReplayBytes = defaults.MaxReplayBytes
+	}
+	if s.TriggerEventTypes == nil {
+		s.TriggerEventTypes = defaults.TriggerEventTypes
+	}
+	return s
+}
+
+// sources - Dolu alanların adları (politikada her ayarın hangi seviyeden geldiğini göstermek için)
+func (s SnapshotSettings) sources() []string {
+	var fields []string
+	if s.FirstAfter != 0 {
+		fields = append(fields, "first_after")
+	}
+	if s.Interval != 0 {
+		fields = append(fields, "interval")
+	}
+	if s.MaxAge != 0 {
+		fields = append(fields, "max_age")
+	}
+	if s.MaxReplayBytes != 0 {
+		fields = append(fields, "max_replay_bytes")
+	}
+	if s.TriggerEventTypes != nil {
+		fields = append(fields, "trigger_event_types")
+	}
+	return fields
+}
+
+func (s SnapshotSettings) validate() error {
+	if s.MaxAge < 0 {
+		return fmt.Errorf("max_age must not be negative")
+	}
+	for _, eventType := range s.TriggerEventTypes {
+		if eventType == "" {
+			return fmt.Errorf("trigger_event_types must not contain empty names")
+		}
+	}
+	return nil
+}
+
+// Triggers - Event tipi politikanın tetikleyici tiplerinden mi?
+func (s SnapshotSettings) Triggers(eventType string) bool {
+	for _, trigger := range s.TriggerEventTypes {
+		if trigger == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Duration - JSON'da "30m", "24h" gibi yazılan süre
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"24h\": %w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// SnapshotPolicy - Bir aggregate tipine uygulanan snapshot politikası
+// Ayarlar aggregate tipinden, yoksa kategorisinden, yoksa genel varsayılandan gelir
+type SnapshotPolicy struct {
+	AggregateType string `json:"aggregate_type"`
+	Category      string `json:"category"`
+	SnapshotSettings
+	// Sources - Ayar adı -> geldiği seviye (aggregate_type, category, default)
+	Sources map[string]string `json:"sources"`
+}
+
+// AggregateType - Store'a yazan bir aggregate tipinin tanımı
+type AggregateType struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Description string `json:"description,omitempty"`
+	// Snapshot - Tipe özel snapshot politikası; verilmeyen alanlar kategoriden gelir
+	Snapshot SnapshotSettings `json:"snapshot"`
+}
+
+// Category - Stream kategorisi ve kategoriye özel ayarlar
+type Category struct {
+	Name     string           `json:"name"`
+	Snapshot SnapshotSettings `json:"snapshot"`
+}
+
+// file - catalog.json (ve STREAM_CATALOG_FILE) şekli
+type file struct {
+	Snapshot       SnapshotSettings         `json:"snapshot"`
+	AggregateTypes map[string]AggregateType `json:"aggregate_types"`
+	Categories     map[string]Category      `json:"categories"`
+}
+
+// Catalog - Aggregate tipi -> kategori eşlemesi ve tip/kategori başına snapshot politikaları
+// Catalog'da olmayan tipler de yazılabilir; kategorileri kendi adlarıdır ve varsayılan ayarları kullanırlar
+type Catalog struct {
+	snapshot   SnapshotSettings
+	types      map[string]AggregateType
+	categories map[string]Category
+}
+
+// NewCatalog - Sadece gömülü tanımlar (user)
+func NewCatalog() *Catalog {
+	c, err := parse(nil, builtinCatalog)
+	if err != nil {
+		panic(fmt.Sprintf("invalid builtin catalog: %v", err))
+	}
+	return c
+}
+
+// LoadCatalog - Gömülü tanımlar, path boş değilse üzerine oradaki dosya
+// Dosyadaki tip ve kategoriler aynı adlı gömülü tanımı ezer; yeni tipler kod değişmeden eklenebilir
+func LoadCatalog(path string) (*Catalog, error) {
+	c := NewCatalog()
+	if path == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream catalog: %w", err)
+	}
+	return parse(c, data)
+}
+
+func parse(base *Catalog, data []byte) (*Catalog, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse stream catalog: %w", err)
+	}
+
+	c := &Catalog{
+		types:      make(map[string]AggregateType),
+		categories: make(map[string]Category),
+	}
+	if base != nil {
+		c.snapshot = base.snapshot
+		for name, t := range base.types {
+			c.types[name] = t
+		}
+		for name, category := range base.categories {
+			c.categories[name] = category
+		}
+	}
+	if err := f.Snapshot.validate(); err != nil {
+		return nil, fmt.Errorf("invalid default snapshot policy: %w", err)
+	}
+	c.snapshot = f.Snapshot.withDefaults(c.snapshot)
+
+	for name, t := range f.AggregateTypes {
+		t.Name = name
+		t.Category = model.NormalizeCategory(t.Category, name)
+		if !model.ValidStreamName(t.Name) || !model.ValidStreamName(t.Category) {
+			return nil, fmt.Errorf("invalid aggregate type %q (category %q)", t.Name, t.Category)
+		}
+		if err := t.Snapshot.validate(); err != nil {
+			return nil, fmt.Errorf("invalid snapshot policy for aggregate type %s: %w", name, err)
+		}
+		c.types[name] = t
+		if _, ok := c.categories[t.Category]; !ok {
+			c.categories[t.Category] = Category{Name: t.Category}
+		}
+	}
+	for name, category := range f.Categories {
+		if !model.ValidStreamName(name) {
+			return nil, fmt.Errorf("invalid category %q", name)
+		}
+		if err := category.Snapshot.validate(); err != nil {
+			return nil, fmt.Errorf("invalid snapshot policy for category %s: %w", name, err)
+		}
+		category.Name = name
+		c.categories[name] = category
+	}
+
+	return c, nil
+}
+
+// CategoryOf - Aggregate tipinin kategorisi (tanımlı değilse tipin kendi adı)
+func (c *Catalog) CategoryOf(aggregateType string) string {
+	aggregateType = model.NormalizeAggregateType(aggregateType)
+	if t, ok := c.types[aggregateType]; ok {
+		return t.Category
+	}
+	return aggregateType
+}
+
+// Snapshot - Kategorinin snapshot eşikleri (kategoride verilmeyen alanlar genel varsayılandan)
+func (c *Catalog) Snapshot(category string) SnapshotSettings {
+	return c.categories[category].Snapshot.withDefaults(c.snapshot)
+}
+
+// SnapshotPolicy - Aggregate tipine uygulanan politika: tip > kategori > genel varsayılan
+func (c *Catalog) SnapshotPolicy(aggregateType string) SnapshotPolicy {
+	aggregateType = model.NormalizeAggregateType(aggregateType)
+	category := c.CategoryOf(aggregateType)
+	typeSettings := c.types[aggregateType].Snapshot
+	categorySettings := c.categories[category].Snapshot
+
+	sources := make(map[string]string)
+	for _, layer := range []struct {
+		name     string
+		settings SnapshotSettings
+	}{
+		{"default", c.snapshot},
+		{"category", categorySettings},
+		{"aggregate_type", typeSettings},
+	} {
+		for _, field := range layer.settings.sources() {
+			sources[field] = layer.name
+		}
+	}
+
+	return SnapshotPolicy{
+		AggregateType:    aggregateType,
+		Category:         category,
+		SnapshotSettings: typeSettings.withDefaults(categorySettings.withDefaults(c.snapshot)),
+		Sources:          sources,
+	}
+}
+
+// Categories - Tanımlı kategoriler (alfabetik, snapshot ayarları varsayılanlarla doldurulmuş)
+func (c *Catalog) Categories() []Category {
+	categories := make([]Category, 0, len(c.categories))
+	for name := range c.categories {
+		categories = append(categories, Category{Name: name, Snapshot: c.Snapshot(name)})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+// AggregateTypes - Tanımlı aggregate tipleri (alfabetik)
+func (c *Catalog) AggregateTypes() []AggregateType {
+	types := make([]AggregateType, 0, len(c.types))
+	for _, t := range c.types {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types
+}
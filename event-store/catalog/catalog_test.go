@@ -0,0 +1,136 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuiltinCatalogDefinesUser(t *testing.T) {
+	c := NewCatalog()
+
+	if category := c.CategoryOf("user"); category != "user" {
+		t.Errorf("expected user category, got %q", category)
+	}
+	if category := c.CategoryOf(""); category != "user" {
+		t.Errorf("expected empty type to fall back to user, got %q", category)
+	}
+	if category := c.CategoryOf("device"); category != "device" {
+		t.Errorf("expected unknown type to be its own category, got %q", category)
+	}
+
+	settings := c.Snapshot("user")
+	if settings.FirstAfter != 10 || settings.Interval != 50 {
+		t.Errorf("unexpected user snapshot settings: %+v", settings)
+	}
+}
+
+func TestLoadCatalogMergesFileOverBuiltin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	file := `{
+		"aggregate_types": {
+			"order": {"category": "commerce"},
+			"invoice": {"category": "commerce"}
+		},
+		"categories": {
+			"commerce": {"snapshot": {"interval": 200}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCatalog: %v", err)
+	}
+
+	if category := c.CategoryOf("invoice"); category != "commerce" {
+		t.Errorf("expected commerce category, got %q", category)
+	}
+	if category := c.CategoryOf("user"); category != "user" {
+		t.Errorf("builtin user type must survive the override, got %q", category)
+	}
+
+	// Kategoride verilmeyen eşik genel varsayılandan gelir
+	settings := c.Snapshot("commerce")
+	if settings.Interval != 200 || settings.FirstAfter != 10 {
+		t.Errorf("unexpected commerce snapshot settings: %+v", settings)
+	}
+
+	categories := c.Categories()
+	if len(categories) != 2 || categories[0].Name != "commerce" || categories[1].Name != "user" {
+		t.Errorf("unexpected categories: %+v", categories)
+	}
+}
+
+func TestLoadCatalogRejectsInvalidNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte(`{"aggregate_types": {"Order Line": {}}}`), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := LoadCatalog(path); err == nil {
+		t.Fatal("expected an error for an invalid aggregate type name")
+	}
+}
+
+func TestSnapshotPolicyLayersTypeOverCategoryOverDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	file := `{
+		"aggregate_types": {
+			"order": {"category": "commerce", "snapshot": {"trigger_event_types": ["order.shipped"], "max_age": "24h"}},
+			"invoice": {"category": "commerce"}
+		},
+		"categories": {
+			"commerce": {"snapshot": {"interval": 200, "max_replay_bytes": 65536}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCatalog: %v", err)
+	}
+
+	order := c.SnapshotPolicy("order")
+	if order.Category != "commerce" || order.FirstAfter != 10 || order.Interval != 200 ||
+		order.MaxReplayBytes != 65536 || time.Duration(order.MaxAge) != 24*time.Hour || !order.Triggers("order.shipped") {
+		t.Errorf("unexpected order policy: %+v", order)
+	}
+	expected := map[string]string{
+		"first_after":         "default",
+		"interval":            "category",
+		"max_replay_bytes":    "category",
+		"max_age":             "aggregate_type",
+		"trigger_event_types": "aggregate_type",
+	}
+	for field, source := range expected {
+		if order.Sources[field] != source {
+			t.Errorf("expected %s from %s, got %q", field, source, order.Sources[field])
+		}
+	}
+
+	if invoice := c.SnapshotPolicy("invoice"); invoice.MaxAge != 0 || invoice.Triggers("order.shipped") {
+		t.Errorf("type-level settings must not leak into the category: %+v", invoice)
+	}
+}
+
+func TestLoadCatalogRejectsInvalidSnapshotPolicies(t *testing.T) {
+	for _, file := range []string{
+		`{"snapshot": {"max_age": "soon"}}`,
+		`{"aggregate_types": {"order": {"snapshot": {"max_age": "-1h"}}}}`,
+		`{"categories": {"commerce": {"snapshot": {"trigger_event_types": [""]}}}}`,
+	} {
+		path := filepath.Join(t.TempDir(), "catalog.json")
+		if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		if _, err := LoadCatalog(path); err == nil {
+			t.Errorf("expected an error for %s", file)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// eventctl - Event geçmişini ortamlar arasında taşımak için komut satırı aracı
+//
+//	eventctl export [-since RFC3339] [-until RFC3339] [-aggregates id1,id2] [-tenant id] [-o events.ndjson.gz]
+//	eventctl import -i events.ndjson.gz [-batch 1000] [-allow-gaps] [-service-url http://localhost:8090] [-service-stopped]
+//	eventctl verify -i events.ndjson.gz
+//	eventctl rebuild-snapshots [-url http://localhost:8090] [-tenant id] [-category c] [-aggregate-type t] [-active-since RFC3339] [-workers 4] [-resume job-id]
+//
+// Bağlantı ayarları event-store servisiyle aynı env değişkenlerinden okunur (EVENT_STORE_BACKEND,
+// CLICKHOUSE_*, EVENT_DB_*, ARCHIVE_DIR). ".gz" uzantılı dosyalar gzip ile yazılır/okunur;
+// -o/-i verilmezse stdout/stdin kullanılır. rebuild-snapshots store'a değil çalışan servise bağlanır.
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/eyupaydin41/event-store/archive"
+	"github.com/eyupaydin41/event-store/config"
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/privacy"
+	"github.com/eyupaydin41/event-store/repository"
+	"github.com/eyupaydin41/event-store/transfer"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("eventctl: ")
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	case "import":
+		err = runImport(ctx, os.Args[2:])
+	case "verify":
+		err = runVerify(ctx, os.Args[2:])
+	case "rebuild-snapshots":
+		err = runRebuildSnapshots(ctx, os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: eventctl <export|import|verify|rebuild-snapshots> [flags]")
+	fmt.Fprintln(os.Stderr, "run 'eventctl <command> -h' for the flags of a command")
+}
+
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	backend := flags.String("backend", "", "event store backend: clickhouse | postgres (default: EVENT_STORE_BACKEND)")
+	since := flags.String("since", "", "only events at or after this time (RFC3339)")
+	until := flags.String("until", "", "only events at or before this time (RFC3339)")
+	aggregates := flags.String("aggregates", "", "comma separated aggregate IDs (default: all aggregates)")
+	tenant := flags.String("tenant", "", "tenant to export (default: the default tenant)")
+	output := flags.String("o", "", "output file, gzip compressed if it ends with .gz (default: stdout)")
+	flags.Parse(args)
+
+	selection := transfer.Selection{TenantID: model.NormalizeTenantID(*tenant)}
+	if !model.ValidTenantID(selection.TenantID) {
+		return fmt.Errorf("invalid -tenant %q", *tenant)
+	}
+	var err error
+	if selection.StartTime, err = parseTime(*since); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if selection.EndTime, err = parseTime(*until); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+	for _, id := range strings.Split(*aggregates, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			selection.AggregateIDs = append(selection.AggregateIDs, id)
+		}
+	}
+
+	store, closeStore, err := openStore(*backend)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	w, closeOutput, err := createOutput(*output)
+	if err != nil {
+		return err
+	}
+
+	result, err := transfer.Export(ctx, store, selection, w)
+	if closeErr := closeOutput(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("exported %d events of %d aggregates", result.Events, result.Aggregates)
+	return nil
+}
+
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	backend := flags.String("backend", "", "event store backend: clickhouse | postgres (default: EVENT_STORE_BACKEND)")
+	input := flags.String("i", "", "input file, gzip compressed if it ends with .gz (default: stdin)")
+	batch := flags.Int("batch", transfer.DefaultBatchSize, "events per write batch")
+	allowGaps := flags.Bool("allow-gaps", false, "import streams whose earlier versions are not in the file (time range exports)")
+	serviceURL := flags.String("service-url", "http://localhost:8090", "event-store HTTP address; the import refuses to run while it answers")
+	serviceStopped := flags.Bool("service-stopped", false, "confirm that the event-store service is stopped when -service-url cannot be reached")
+	flags.Parse(args)
+
+	if err := checkServiceStopped(*serviceURL, *serviceStopped); err != nil {
+		return err
+	}
+
+	store, closeStore, err := openStore(*backend)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	r, closeInput, err := openInput(*input)
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	log.Println("importing; keep the event-store service stopped until the import finishes")
+	result, err := transfer.Import(ctx, store, r, transfer.ImportOptions{BatchSize: *batch, AllowGaps: *allowGaps})
+	printJSON(result)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("imported %d events (%d already present); start the event-store service now", result.Imported, result.Skipped)
+	return nil
+}
+
+// checkServiceStopped - Import position'ları servisin dışında atar; çalışan servis son position'ı
+// bellekte tuttuğu için import edilenleri tekrar kullanır. Servis cevap veriyorsa import başlamaz.
+// Cevap gelmemesi servisin durduğunu kanıtlamaz (yanlış adres, ağ hatası); bu durumda kullanıcı
+// -service-stopped ile onaylamadıkça import başlamaz
+func checkServiceStopped(serviceURL string, confirmed bool) error {
+	if serviceURL != "" {
+		client := &http.Client{Timeout: 2 * time.Second}
+		resp, err := client.Get(strings.TrimRight(serviceURL, "/") + "/health")
+		if err == nil {
+			resp.Body.Close()
+			return fmt.Errorf("event-store service at %s is running; stop it before importing and start it again afterwards", serviceURL)
+		}
+		if !confirmed {
+			return fmt.Errorf("could not reach event-store service at %s (%v); if every event-store instance writing to this store is stopped, run again with -service-stopped", serviceURL, err)
+		}
+		return nil
+	}
+
+	if !confirmed {
+		return fmt.Errorf("-service-url is empty; confirm that the event-store service is stopped with -service-stopped")
+	}
+	return nil
+}
+
+func runVerify(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	backend := flags.String("backend", "", "event store backend: clickhouse | postgres (default: EVENT_STORE_BACKEND)")
+	input := flags.String("i", "", "input file, gzip compressed if it ends with .gz (default: stdin)")
+	flags.Parse(args)
+
+	store, closeStore, err := openStore(*backend)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	r, closeInput, err := openInput(*input)
+	if err != nil {
+		return err
+	}
+	defer closeInput()
+
+	report, err := transfer.Verify(ctx, store, r)
+	if err != nil {
+		return err
+	}
+	printJSON(report)
+
+	if !report.OK() {
+		return fmt.Errorf("verify failed: %d of %d aggregates differ (file %d events, store %d events)",
+			len(report.Mismatches), report.Aggregates, report.FileEvents, report.StoreEvents)
+	}
+
+	log.Printf("verified %d events of %d aggregates", report.FileEvents, report.Aggregates)
+	return nil
+}
+
+// openStore - Servisle aynı backend'e ve veri anahtarlarına bağlanır; ARCHIVE_DIR varsa arşivlenmiş
+// partition'lar da okunur. PII alanları privacy store üzerinden taşınır: export düz metin yazar,
+// import hedefin anahtarlarıyla şifreler (anahtarlar ortamlar arasında taşınmaz).
+// Upcast decorator'ı kullanılmaz: payload'lar kayıtlı schema_version'larıyla taşınır
+func openStore(backend string) (repository.EventStore, func(), error) {
+	config.LoadEnv()
+	if backend == "" {
+		backend = config.GetEnv("EVENT_STORE_BACKEND")
+	}
+
+	var store repository.EventStore
+	var keys repository.KeyStore
+	var closers []func()
+	closeStore := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+	switch backend {
+	case "", "clickhouse":
+		conn := config.InitClickHouse()
+		closers = append(closers, func() { conn.Close() })
+		store = repository.NewEventRepository(conn)
+		keyRepo := repository.NewKeyRepository(conn)
+		if err := keyRepo.CreateTable(); err != nil {
+			closeStore()
+			return nil, nil, fmt.Errorf("failed to create data key table: %w", err)
+		}
+		keys = keyRepo
+	case "postgres":
+		db := config.InitPostgres()
+		closers = append(closers, func() { db.Close() })
+		store = repository.NewPostgresEventRepository(db)
+		keyRepo := repository.NewPostgresKeyRepository(db)
+		if err := keyRepo.CreateTable(); err != nil {
+			closeStore()
+			return nil, nil, fmt.Errorf("failed to create data key table: %w", err)
+		}
+		keys = keyRepo
+	default:
+		return nil, nil, fmt.Errorf("unknown backend %q (expected clickhouse or postgres)", backend)
+	}
+
+	// Servisteki gibi KEY_STORE_BACKEND=postgres anahtarları ayrı veritabanından (KEY_DB_*) okur
+	switch keyBackend := config.GetEnv("KEY_STORE_BACKEND"); keyBackend {
+	case "":
+	case "postgres":
+		keyDB := config.InitKeyStorePostgres()
+		closers = append(closers, func() { keyDB.Close() })
+		keyRepo := repository.NewPostgresKeyRepository(keyDB)
+		if err := keyRepo.CreateTable(); err != nil {
+			closeStore()
+			return nil, nil, fmt.Errorf("failed to create data key table: %w", err)
+		}
+		keys = keyRepo
+	default:
+		closeStore()
+		return nil, nil, fmt.Errorf("unknown KEY_STORE_BACKEND %q (expected postgres)", keyBackend)
+	}
+
+	if archiveDir := config.GetEnv("ARCHIVE_DIR"); archiveDir != "" {
+		eventArchive, err := archive.Open(archiveDir)
+		if err != nil {
+			closeStore()
+			return nil, nil, err
+		}
+		store = archive.NewStore(store, eventArchive, archive.ReadFallback)
+	}
+
+	return privacy.NewStore(store, keys, privacy.DefaultFields()), closeStore, nil
+}
+
+func createOutput(path string) (io.Writer, func() error, error) {
+	if path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, file.Close, nil
+	}
+
+	gz := gzip.NewWriter(file)
+	return gz, func() error {
+		if err := gz.Close(); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}, nil
+}
+
+func openInput(path string) (io.Reader, func(), error) {
+	if path == "" {
+		return os.Stdin, func() {}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, func() { file.Close() }, nil
+	}
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return gz, func() { gz.Close(); file.Close() }, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func printJSON(value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(data))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eyupaydin41/event-store/service"
+)
+
+// runRebuildSnapshots - Çalışan servisin /snapshot-rebuilds endpoint'leri üzerinden toplu snapshot rebuild
+// Snapshot'lar servisin privacy anahtarlarıyla şifrelendiği için iş store'a doğrudan değil servis
+// içinde çalışır. Ctrl-C işi iptal eder ve devam için -resume ile verilecek iş ID'sini yazar
+func runRebuildSnapshots(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("rebuild-snapshots", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8090", "event-store HTTP address")
+	tenant := flags.String("tenant", "", "tenant whose streams are rebuilt (default: the default tenant)")
+	category := flags.String("category", "", "only streams of this category")
+	aggregateType := flags.String("aggregate-type", "", "only streams of this aggregate type")
+	activeSince := flags.String("active-since", "", "only streams with events at or after this time (RFC3339)")
+	after := flags.String("after", "", "start after this aggregate ID")
+	workers := flags.Int("workers", service.DefaultRebuildWorkers, "streams rebuilt concurrently")
+	resume := flags.String("resume", "", "continue a cancelled or failed rebuild job from its cursor")
+	poll := flags.Duration("poll", 2*time.Second, "progress report interval")
+	flags.Parse(args)
+
+	if _, err := parseTime(*activeSince); err != nil {
+		return fmt.Errorf("invalid -active-since: %w", err)
+	}
+
+	client := &rebuildClient{baseURL: strings.TrimRight(*baseURL, "/"), tenant: *tenant}
+
+	var rebuild service.SnapshotRebuild
+	var err error
+	if *resume != "" {
+		err = client.do(http.MethodPost, "/snapshot-rebuilds/"+url.PathEscape(*resume)+"/resume", nil, &rebuild)
+	} else {
+		query := url.Values{}
+		for key, value := range map[string]string{
+			"category":       *category,
+			"aggregate_type": *aggregateType,
+			"active_since":   *activeSince,
+			"after":          *after,
+			"workers":        strconv.Itoa(*workers),
+		} {
+			if value != "" {
+				query.Set(key, value)
+			}
+		}
+		err = client.do(http.MethodPost, "/snapshot-rebuilds", query, &rebuild)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("rebuild %s started", rebuild.ID)
+
+	ticker := time.NewTicker(*poll)
+	defer ticker.Stop()
+
+	for rebuild.State == service.RebuildRunning {
+		select {
+		case <-ctx.Done():
+			// Ctrl-C: servisteki işi de durdur
+			if err := client.do(http.MethodPost, "/snapshot-rebuilds/"+rebuild.ID+"/cancel", nil, &rebuild); err != nil {
+				return fmt.Errorf("failed to cancel rebuild %s: %w", rebuild.ID, err)
+			}
+		case <-ticker.C:
+			if err := client.do(http.MethodGet, "/snapshot-rebuilds/"+rebuild.ID, nil, &rebuild); err != nil {
+				return err
+			}
+			log.Printf("processed %d (rebuilt %d, skipped %d, failed %d), cursor %q",
+				rebuild.Processed, rebuild.Rebuilt, rebuild.Skipped, rebuild.Failed, rebuild.Cursor)
+		}
+	}
+
+	printJSON(rebuild)
+	switch rebuild.State {
+	case service.RebuildCancelled, service.RebuildFailed:
+		return fmt.Errorf("rebuild %s %s at cursor %q; continue with: eventctl rebuild-snapshots -resume %s",
+			rebuild.ID, rebuild.State, rebuild.Cursor, rebuild.ID)
+	}
+	if rebuild.Failed > 0 {
+		return fmt.Errorf("rebuild %s completed with %d failed streams", rebuild.ID, rebuild.Failed)
+	}
+
+	log.Printf("rebuilt %d snapshots (%d streams skipped)", rebuild.Rebuilt, rebuild.Skipped)
+	return nil
+}
+
+type rebuildClient struct {
+	baseURL string
+	tenant  string
+}
+
+func (c *rebuildClient) do(method, path string, query url.Values, out interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return err
+	}
+	if c.tenant != "" {
+		// api.TenantHeader
+		request.Header.Set("X-Tenant-ID", c.tenant)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if response.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return fmt.Errorf("%s %s: %s: %s", method, path, response.Status, apiErr.Error)
+	}
+
+	return json.Unmarshal(body, out)
+}
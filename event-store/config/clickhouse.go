@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -15,7 +16,6 @@ func InitClickHouse() driver.Conn {
 
 	host := GetEnv("CLICKHOUSE_HOST")
 	user := GetEnv("CLICKHOUSE_USER")
-	fmt.Print("user", user)
 	password := GetEnv("CLICKHOUSE_PASSWORD")
 	database := GetEnv("CLICKHOUSE_DB")
 
@@ -46,36 +46,266 @@ func InitClickHouse() driver.Conn {
 
 	log.Println("connected to ClickHouse successfully")
 
-	if err := createEventTable(conn); err != nil {
+	if err := CreateEventTable(conn); err != nil {
 		log.Fatalf("failed to create event table: %v", err)
 	}
 
+	if GetEnv("CLICKHOUSE_SKIP_EVENTS_MIGRATION") != "true" {
+		if err := MigrateEventTable(conn); err != nil {
+			log.Fatalf("failed to migrate event table: %v", err)
+		}
+	}
+
+	if err := AddEventColumns(conn); err != nil {
+		log.Fatalf("failed to add event columns: %v", err)
+	}
+
 	return conn
 }
 
-func createEventTable(conn driver.Conn) error {
+// eventTableDDL - events tablosu aggregate stream'lerini okumak için tasarlandı:
+//   - ORDER BY (aggregate_id, version): bir aggregate'in event'leri primary key üzerinde
+//     art arda durur, GetEventsAfterVersion partition taramak yerine birkaç granule okur
+//   - events_by_time projection'ı: zaman aralığı/replay sorguları timestamp sıralı kopyayı kullanır
+//   - bloom_filter index'leri: event_type ve id eşitlik filtreleri için (minmax string'lerde işe yaramaz)
+//   - PARTITION BY toYYYYMM(timestamp) korunur (arşivleme partition bazında yapılır)
+//   - position: append sırasında atanan global sıra; minmax index'i eski part'ları eler
+//   - schema_version: payload'un şema versiyonu (0 = registry'den önceki event'ler)
+//   - correlation_id/causation_id/actor_id/source_service: producer'ın envelope'taki metadata'sı;
+//     bloom_filter index'leri "bu istekten/kullanıcıdan doğan event'ler" sorgularını hızlandırır
+//   - tenant_id: her okuma sorgusunda filtre; tenant başına az sayıda değer olduğu için set index'i
+//   - aggregate_type/category: stream'in tipi ve kategorisi (eski satırlar 'user'); kategori listeleme/filtreleri için set index'i
+const eventTableDDL = `
+	CREATE TABLE IF NOT EXISTS %s (
+		id String,
+		event_type LowCardinality(String),
+		aggregate_id String,
+		payload String CODEC(ZSTD(3)),
+		timestamp DateTime64(3),
+		version UInt32,
+		position UInt64,
+		schema_version UInt16 DEFAULT 0,
+		correlation_id String DEFAULT '',
+		causation_id String DEFAULT '',
+		actor_id String DEFAULT '',
+		source_service LowCardinality(String) DEFAULT '',
+		tenant_id LowCardinality(String) DEFAULT 'default',
+		aggregate_type LowCardinality(String) DEFAULT 'user',
+		category LowCardinality(String) DEFAULT 'user',
+		INDEX idx_event_type event_type TYPE bloom_filter(0.01) GRANULARITY 4,
+		INDEX idx_id id TYPE bloom_filter(0.001) GRANULARITY 4,
+		INDEX idx_correlation_id correlation_id TYPE bloom_filter(0.01) GRANULARITY 4,
+		INDEX idx_causation_id causation_id TYPE bloom_filter(0.01) GRANULARITY 4,
+		INDEX idx_actor_id actor_id TYPE bloom_filter(0.01) GRANULARITY 4,
+		INDEX idx_tenant_id tenant_id TYPE set(100) GRANULARITY 4,
+		INDEX idx_category category TYPE set(100) GRANULARITY 4,
+		INDEX idx_timestamp timestamp TYPE minmax GRANULARITY 1,
+		INDEX idx_position position TYPE minmax GRANULARITY 1,
+		PROJECTION events_by_time (
+			SELECT * ORDER BY timestamp, id
+		)
+	) ENGINE = MergeTree()
+	PARTITION BY toYYYYMM(timestamp)
+	ORDER BY (aggregate_id, version)
+	SETTINGS index_granularity = 8192
+`
+
+// eventTableSortingKey - Yeni layout'un system.tables'daki sorting_key değeri
+const eventTableSortingKey = "aggregate_id, version"
+
+// CreateEventTable - events tablosu yoksa yeni layout ile oluşturur
+func CreateEventTable(conn driver.Conn) error {
 	ctx := context.Background()
-	query := `
-		CREATE TABLE IF NOT EXISTS events (
-			id String,
-			event_type String,
-			aggregate_id String,
-			payload String,
-			timestamp DateTime64(3),
-			version UInt32,
-			INDEX idx_event_type event_type TYPE minmax GRANULARITY 4,
-			INDEX idx_aggregate_id aggregate_id TYPE minmax GRANULARITY 4,
-			INDEX idx_timestamp timestamp TYPE minmax GRANULARITY 1
-		) ENGINE = MergeTree()
-		ORDER BY (timestamp, id)
-		PARTITION BY toYYYYMM(timestamp)
-		SETTINGS index_granularity = 8192
-	`
-
-	if err := conn.Exec(ctx, query); err != nil {
+
+	if err := conn.Exec(ctx, fmt.Sprintf(eventTableDDL, "events")); err != nil {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
 	log.Println("event table created or already exists")
 	return nil
 }
+
+// eventTableAddedColumns - Layout'u değiştirmeden sonradan eklenen kolonlar
+// Mevcut satırlar DEFAULT değeri alır; ADD COLUMN sadece metadata değişikliğidir, veri yeniden yazılmaz
+var eventTableAddedColumns = []string{
+	"schema_version UInt16 DEFAULT 0",
+	"correlation_id String DEFAULT ''",
+	"causation_id String DEFAULT ''",
+	"actor_id String DEFAULT ''",
+	"source_service LowCardinality(String) DEFAULT ''",
+	"tenant_id LowCardinality(String) DEFAULT 'default'",
+	"aggregate_type LowCardinality(String) DEFAULT 'user'",
+	"category LowCardinality(String) DEFAULT 'user'",
+}
+
+// eventTableAddedIndexes - Sonradan eklenen kolonların index'leri
+// Sadece yeni yazılan part'lara uygulanır; eski part'lar için MATERIALIZE INDEX elle çalıştırılabilir
+var eventTableAddedIndexes = []string{
+	"idx_correlation_id correlation_id TYPE bloom_filter(0.01) GRANULARITY 4",
+	"idx_causation_id causation_id TYPE bloom_filter(0.01) GRANULARITY 4",
+	"idx_actor_id actor_id TYPE bloom_filter(0.01) GRANULARITY 4",
+	"idx_tenant_id tenant_id TYPE set(100) GRANULARITY 4",
+	"idx_category category TYPE set(100) GRANULARITY 4",
+}
+
+// AddEventColumns - Eksik kolon ve index'leri events tablosuna ekler
+func AddEventColumns(conn driver.Conn) error {
+	ctx := context.Background()
+
+	for _, column := range eventTableAddedColumns {
+		if err := conn.Exec(ctx, "ALTER TABLE events ADD COLUMN IF NOT EXISTS "+column); err != nil {
+			return fmt.Errorf("failed to add column %q: %w", column, err)
+		}
+	}
+
+	for _, index := range eventTableAddedIndexes {
+		if err := conn.Exec(ctx, "ALTER TABLE events ADD INDEX IF NOT EXISTS "+index); err != nil {
+			return fmt.Errorf("failed to add index %q: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+// eventTableColumns - events tablosunun mevcut kolon isimleri
+func eventTableColumns(ctx context.Context, conn driver.Conn) (map[string]bool, error) {
+	rows, err := conn.Query(ctx, `
+		SELECT name FROM system.columns
+		WHERE database = currentDatabase() AND table = 'events'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events table columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		columns[name] = true
+	}
+
+	return columns, rows.Err()
+}
+
+// MigrateEventTable - Eski layout'taki (ORDER BY (timestamp, id) ya da position kolonu olmayan)
+// events tablosunu güncel layout'a taşır
+//  1. events_v2 güncel layout ile oluşturulur
+//  2. Veri partition partition kopyalanır (bellek kullanımını sınırlamak için);
+//     position yoksa (timestamp, id) sırasıyla 1'den başlayarak boşluksuz atanır
+//  3. Satır sayıları karşılaştırılır
+//  4. Tablolar atomik olarak yeniden adlandırılır: events -> events_legacy_<unix>, events_v2 -> events
+//
+// events_legacy_<unix> silinmez; doğrulamadan sonra elle DROP edilebilir.
+// Consumer başlamadan önce çalıştığı için migration sırasında yeni event yazılmaz.
+func MigrateEventTable(conn driver.Conn) error {
+	// Büyük partition'ların kopyalanması bağlantının max_execution_time limitini aşabilir
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithSettings(clickhouse.Settings{
+		"max_execution_time": 0,
+	}))
+
+	var sortingKey string
+	err := conn.QueryRow(ctx, `
+		SELECT sorting_key FROM system.tables
+		WHERE database = currentDatabase() AND name = 'events'
+	`).Scan(&sortingKey)
+	if err != nil {
+		return fmt.Errorf("failed to read events table layout: %w", err)
+	}
+
+	columns, err := eventTableColumns(ctx, conn)
+	if err != nil {
+		return err
+	}
+	hasPosition := columns["position"]
+
+	if sortingKey == eventTableSortingKey && hasPosition {
+		return nil
+	}
+
+	log.Printf("events table uses legacy layout (ORDER BY %s, position column: %t), migrating", sortingKey, hasPosition)
+	started := time.Now()
+
+	if err := conn.Exec(ctx, "DROP TABLE IF EXISTS events_v2"); err != nil {
+		return fmt.Errorf("failed to drop stale events_v2: %w", err)
+	}
+	if err := conn.Exec(ctx, fmt.Sprintf(eventTableDDL, "events_v2")); err != nil {
+		return fmt.Errorf("failed to create events_v2: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, `
+		SELECT DISTINCT partition FROM system.parts
+		WHERE database = currentDatabase() AND table = 'events' AND active
+		ORDER BY partition
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	var partitions []string
+	for rows.Next() {
+		var partition string
+		if err := rows.Scan(&partition); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan partition: %w", err)
+		}
+		partitions = append(partitions, partition)
+	}
+	rows.Close()
+
+	// Sonradan eklenen kolonlar eski tabloda varsa aynen kopyalanır, yoksa DEFAULT değerini alır
+	addedColumns := ""
+	for _, column := range eventTableAddedColumns {
+		name := strings.Fields(column)[0]
+		if columns[name] {
+			addedColumns += ", " + name
+		}
+	}
+
+	// Partition'lar ay sırasıyla kopyalanır, position önceki partition'lardaki satır sayısından devam eder
+	var copied uint64
+	for _, partition := range partitions {
+		positionExpr := "position"
+		if !hasPosition {
+			positionExpr = fmt.Sprintf("%d + row_number() OVER (ORDER BY timestamp, id)", copied)
+		}
+
+		query := fmt.Sprintf(`
+			INSERT INTO events_v2 (id, event_type, aggregate_id, payload, timestamp, version, position%s)
+			SELECT id, event_type, aggregate_id, payload, timestamp, version, %s%s
+			FROM events
+			WHERE toString(toYYYYMM(timestamp)) = ?
+		`, addedColumns, positionExpr, addedColumns)
+		if err := conn.Exec(ctx, query, partition); err != nil {
+			return fmt.Errorf("failed to copy partition %s: %w", partition, err)
+		}
+
+		var partitionRows uint64
+		if err := conn.QueryRow(ctx, "SELECT count() FROM events WHERE toString(toYYYYMM(timestamp)) = ?", partition).Scan(&partitionRows); err != nil {
+			return fmt.Errorf("failed to count partition %s: %w", partition, err)
+		}
+		copied += partitionRows
+		log.Printf("migrated events partition %s (%d rows)", partition, partitionRows)
+	}
+
+	var oldCount, newCount uint64
+	if err := conn.QueryRow(ctx, "SELECT count() FROM events").Scan(&oldCount); err != nil {
+		return fmt.Errorf("failed to count events: %w", err)
+	}
+	if err := conn.QueryRow(ctx, "SELECT count() FROM events_v2").Scan(&newCount); err != nil {
+		return fmt.Errorf("failed to count events_v2: %w", err)
+	}
+	if oldCount != newCount {
+		return fmt.Errorf("row count mismatch after copy: events=%d events_v2=%d", oldCount, newCount)
+	}
+
+	legacyTable := fmt.Sprintf("events_legacy_%d", started.Unix())
+	if err := conn.Exec(ctx, fmt.Sprintf("RENAME TABLE events TO %s, events_v2 TO events", legacyTable)); err != nil {
+		return fmt.Errorf("failed to swap tables: %w", err)
+	}
+
+	log.Printf("events table migrated: %d rows in %s (old table kept as %s)", newCount, time.Since(started), legacyTable)
+	return nil
+}
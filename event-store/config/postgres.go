@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// InitPostgres - ClickHouse çalıştıramayan ortamlar için Postgres event store bağlantısı
+func InitPostgres() *sql.DB {
+	db := openPostgres("EVENT_DB")
+
+	log.Println("connected to Postgres event store successfully")
+
+	if err := createPostgresEventTable(db); err != nil {
+		log.Fatalf("failed to create event table: %v", err)
+	}
+
+	return db
+}
+
+// InitKeyStorePostgres - Crypto-shredding anahtarları için ayrı Postgres bağlantısı (KEY_DB_*)
+// Anahtarlar event'lerden ayrı bir veritabanında tutulur; event yedekleri anahtar içermez
+func InitKeyStorePostgres() *sql.DB {
+	db := openPostgres("KEY_DB")
+
+	log.Println("connected to Postgres key store successfully")
+	return db
+}
+
+// openPostgres - <prefix>_HOST, _USER, _PASSWORD, _NAME, _PORT, _SSLMODE değişkenleriyle bağlanır
+func openPostgres(prefix string) *sql.DB {
+	LoadEnv()
+
+	host := GetEnv(prefix + "_HOST")
+	user := GetEnv(prefix + "_USER")
+	password := GetEnv(prefix + "_PASSWORD")
+	dbname := GetEnv(prefix + "_NAME")
+	port := GetEnv(prefix + "_PORT")
+	sslmode := GetEnv(prefix + "_SSLMODE")
+	if port == "" {
+		port = "5432"
+	}
+	if sslmode == "" {
+		sslmode = "disable"
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		host, user, password, dbname, port, sslmode,
+	)
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		log.Fatalf("failed to connect to Postgres (%s): %v", prefix, err)
+	}
+
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		log.Fatalf("failed to ping Postgres (%s): %v", prefix, err)
+	}
+
+	return db
+}
+
+// createPostgresEventTable - Event sourcing'e uygun şema:
+// global_position ile tüm stream'ler arasında sıra (event service boşluksuz atar, sequence
+// kullanılmaz çünkü rollback olan transaction'lar sequence'ta boşluk bırakır), (aggregate_id, version)
+// unique olduğu için aynı version'a iki event yazılamaz.
+// tenant_id her okumada filtrelenir; eski satırlar 'default' tenant'ına düşer.
+// aggregate_type/category stream'in tipi ve kategorisidir; eski satırlar 'user'
+func createPostgresEventTable(db *sql.DB) error {
+	ctx := context.Background()
+	query := `
+		CREATE TABLE IF NOT EXISTS events (
+			global_position BIGINT PRIMARY KEY,
+			id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			aggregate_id TEXT NOT NULL,
+			payload JSONB NOT NULL,
+			timestamp TIMESTAMPTZ NOT NULL,
+			version INTEGER NOT NULL CHECK (version > 0),
+			schema_version SMALLINT NOT NULL DEFAULT 0,
+			correlation_id TEXT NOT NULL DEFAULT '',
+			causation_id TEXT NOT NULL DEFAULT '',
+			actor_id TEXT NOT NULL DEFAULT '',
+			source_service TEXT NOT NULL DEFAULT '',
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			aggregate_type TEXT NOT NULL DEFAULT 'user',
+			category TEXT NOT NULL DEFAULT 'user',
+			CONSTRAINT events_id_key UNIQUE (id),
+			CONSTRAINT events_aggregate_version_key UNIQUE (aggregate_id, version)
+		);
+		CREATE INDEX IF NOT EXISTS idx_events_event_type ON events (event_type);
+		CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events (timestamp);
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS schema_version SMALLINT NOT NULL DEFAULT 0;
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS correlation_id TEXT NOT NULL DEFAULT '';
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS causation_id TEXT NOT NULL DEFAULT '';
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS actor_id TEXT NOT NULL DEFAULT '';
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS source_service TEXT NOT NULL DEFAULT '';
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS aggregate_type TEXT NOT NULL DEFAULT 'user';
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT 'user';
+		CREATE INDEX IF NOT EXISTS idx_events_correlation_id ON events (correlation_id);
+		CREATE INDEX IF NOT EXISTS idx_events_actor_id ON events (actor_id);
+		CREATE INDEX IF NOT EXISTS idx_events_tenant_position ON events (tenant_id, global_position);
+		CREATE INDEX IF NOT EXISTS idx_events_tenant_category ON events (tenant_id, category, aggregate_id);
+	`
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	log.Println("event table created or already exists")
+	return nil
+}
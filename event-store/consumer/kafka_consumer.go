@@ -2,27 +2,53 @@ package consumer
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/schema"
 	"github.com/eyupaydin41/event-store/service"
 	"github.com/google/uuid"
 )
 
+// TenantHeader - Mesajın tenant'ını taşıyan Kafka header'ı
+const TenantHeader = "tenant-id"
+
+const (
+	// retryBackoff ve maxRetryBackoff - İşlenemeyen mesajın tekrar denenme aralığı (her hatada iki katı)
+	retryBackoff    = time.Second
+	maxRetryBackoff = 30 * time.Second
+	seekTimeoutMs   = 5000
+)
+
 type EventStoreConsumer struct {
 	consumer        *kafka.Consumer
 	topic           string
 	service         *service.EventService
 	snapshotService *service.SnapshotService
+	registry        *schema.Registry
+	schemaMode      SchemaMode
+	// quarantine - Sadece SchemaModeQuarantine'de kullanılır
+	quarantine *Quarantine
 }
 
-func NewEventStoreConsumer(broker, group, topic string, service *service.EventService, snapshotService *service.SnapshotService) *EventStoreConsumer {
+func NewEventStoreConsumer(
+	broker, group, topic string,
+	service *service.EventService,
+	snapshotService *service.SnapshotService,
+	registry *schema.Registry,
+	schemaMode SchemaMode,
+	quarantine *Quarantine,
+) *EventStoreConsumer {
 	c, err := kafka.NewConsumer(&kafka.ConfigMap{
 		"bootstrap.servers": broker,
 		"group.id":          group,
 		"auto.offset.reset": "earliest",
+		// Offset sadece mesaj kaydedildikten ya da karantinaya alındıktan sonra commit edilir
+		"enable.auto.commit": false,
 	})
 	if err != nil {
 		log.Fatalf("failed to create consumer: %v", err)
@@ -32,57 +58,98 @@ func NewEventStoreConsumer(broker, group, topic string, service *service.EventSe
 		log.Fatalf("failed to subscribe topic: %v", err)
 	}
 
-	log.Printf("event store consumer subscribed to topic: %s", topic)
+	if schemaMode == SchemaModeQuarantine && quarantine == nil {
+		log.Fatalf("schema mode %s requires a quarantine producer", schemaMode)
+	}
+
+	log.Printf("event store consumer subscribed to topic: %s (schema mode: %s)", topic, schemaMode)
 
 	return &EventStoreConsumer{
 		consumer:        c,
 		topic:           topic,
 		service:         service,
 		snapshotService: snapshotService,
+		registry:        registry,
+		schemaMode:      schemaMode,
+		quarantine:      quarantine,
 	}
 }
 
+// Start - Mesajları sırayla işler; offset ancak mesaj kaydedildikten, duplicate olarak atlandıktan
+// ya da reddedildikten (karantina onaylandıktan) sonra commit edilir
+// İşlenemeyen mesajda partition o mesaja geri sarılır ve backoff ile tekrar denenir; sonraki
+// mesajlara geçilmez. Commit edilemeyen offset'ler yeniden okunursa event ID ile ayıklanır
 func (c *EventStoreConsumer) Start() {
 	log.Println("event store consumer started")
+	backoff := retryBackoff
+	var retry *kafka.Message
 	for {
-		msg, err := c.consumer.ReadMessage(-1)
-		if err != nil {
-			log.Printf("consumer error: %v", err)
+		msg := retry
+		retry = nil
+		if msg == nil {
+			var err error
+			if msg, err = c.consumer.ReadMessage(-1); err != nil {
+				log.Printf("consumer error: %v", err)
+				continue
+			}
+		}
+
+		if err := c.handleEvent(msg); err != nil {
+			log.Printf("failed to handle event %s/%d/%d, retrying in %s: %v",
+				topicName(msg), msg.TopicPartition.Partition, msg.TopicPartition.Offset, backoff, err)
+			if err := c.consumer.Seek(msg.TopicPartition, seekTimeoutMs); err != nil {
+				// Geri sarılamazsa sonraki okuma mesajı atlar; mesaj okunmadan burada tekrar denenir
+				log.Printf("failed to seek back to %s/%d/%d: %v",
+					topicName(msg), msg.TopicPartition.Partition, msg.TopicPartition.Offset, err)
+				retry = msg
+			}
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > maxRetryBackoff {
+				backoff = maxRetryBackoff
+			}
 			continue
 		}
+		backoff = retryBackoff
 
-		if err := c.handleEvent(msg.Value); err != nil {
-			log.Printf("failed to handle event: %v", err)
+		if _, err := c.consumer.CommitMessage(msg); err != nil {
+			log.Printf("failed to commit offset %s/%d/%d: %v",
+				topicName(msg), msg.TopicPartition.Partition, msg.TopicPartition.Offset, err)
 		}
 	}
 }
 
-func (c *EventStoreConsumer) handleEvent(eventData []byte) error {
+func (c *EventStoreConsumer) handleEvent(msg *kafka.Message) error {
 	var envelope map[string]interface{}
-	if err := json.Unmarshal(eventData, &envelope); err != nil {
-		log.Printf("Failed to unmarshal envelope: %v", err)
-		return err
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+		return c.reject(msg, "", 0, fmt.Sprintf("invalid envelope: %v", err))
 	}
 
-	// "type" field'ını al (producer format: {"type": "...", "data": {...}})
+	// "type" field'ını al (producer format: {"type": "...", "schema_version": N, "aggregate_type": "...", "metadata": {...}, "data": {...}})
 	eventType, ok := envelope["type"].(string)
-	if !ok {
-		log.Println("missing type field in message")
-		return nil
+	if !ok || eventType == "" {
+		return c.reject(msg, "", 0, "missing type field in message")
+	}
+
+	// schema_version göndermeyen (eski) producer'lar ilk versiyonu kullanıyor kabul edilir
+	schemaVersion := schema.DefaultVersion
+	if v, ok := envelope["schema_version"].(float64); ok {
+		schemaVersion = int(v)
 	}
 
 	// "data" field'ından event bilgilerini al
 	dataMap, ok := envelope["data"].(map[string]interface{})
 	if !ok {
-		log.Println("missing or invalid data field in message")
-		return nil
+		return c.reject(msg, eventType, schemaVersion, "missing or invalid data field in message")
+	}
+
+	if err := c.validate(eventType, schemaVersion, dataMap); err != nil {
+		return c.reject(msg, eventType, schemaVersion, err.Error())
 	}
 
 	// AggregateID'yi data içinden al
 	aggregateID, _ := dataMap["aggregate_id"].(string)
 	if aggregateID == "" {
-		log.Println("missing aggregate_id in data")
-		return nil
+		return c.reject(msg, eventType, schemaVersion, "missing aggregate_id in data")
 	}
 
 	// Timestamp'i data içinden al
@@ -96,6 +163,26 @@ func (c *EventStoreConsumer) handleEvent(eventData []byte) error {
 		timestamp = time.Now()
 	}
 
+	// Tenant Kafka header'ında taşınır; göndermeyen (eski) producer'lar default tenant'a yazar
+	tenantID := headerValue(msg.Headers, TenantHeader)
+	if !model.ValidTenantID(model.NormalizeTenantID(tenantID)) {
+		return c.reject(msg, eventType, schemaVersion, fmt.Sprintf("invalid tenant_id %q", tenantID))
+	}
+
+	// Aggregate tipi envelope'ta opsiyonel; yoksa stream'in tipi ya da event tipinin öneki kullanılır
+	aggregateType, _ := envelope["aggregate_type"].(string)
+
+	// Producer'ın atadığı event ID'yi al (envelope veya data içinde)
+	eventID, _ := envelope["event_id"].(string)
+	if eventID == "" {
+		eventID, _ = dataMap["event_id"].(string)
+	}
+	if eventID == "" {
+		// Eski producer'lar ID göndermiyor; Kafka koordinatlarından deterministik ID üret
+		// böylece aynı mesajın redelivery'si yine aynı ID'yi alır
+		eventID = legacyEventID(msg)
+	}
+
 	// Version'ı data içinden al
 	version := uint32(0)
 	if v, ok := dataMap["version"].(float64); ok {
@@ -110,33 +197,95 @@ func (c *EventStoreConsumer) handleEvent(eventData []byte) error {
 	}
 
 	event := &model.Event{
-		ID:          uuid.New().String(),
-		EventType:   eventType,
-		AggregateID: aggregateID,
-		Payload:     string(payloadBytes),
-		Timestamp:   timestamp,
-		Version:     version,
+		ID:            eventID,
+		EventType:     eventType,
+		AggregateID:   aggregateID,
+		Payload:       string(payloadBytes),
+		Timestamp:     timestamp,
+		Version:       version,
+		SchemaVersion: uint16(schemaVersion),
+		Metadata:      envelopeMetadata(envelope),
+		TenantID:      model.NormalizeTenantID(tenantID),
+		AggregateType: aggregateType,
 	}
 
 	log.Printf("Event Store: Saving event %s for aggregate %s (version %d)", eventType, aggregateID, version)
 
 	if err := c.service.SaveEvent(event); err != nil {
+		if errors.Is(err, service.ErrDuplicateEvent) {
+			// Event zaten kaydedilmiş (AppendEvents veya redelivery) - no-op
+			log.Printf("Event Store: Duplicate event %s (%s) for aggregate %s, skipping", eventID, eventType, aggregateID)
+			return nil
+		}
+		if errors.Is(err, service.ErrTenantMismatch) {
+			return c.reject(msg, eventType, schemaVersion, fmt.Sprintf("aggregate %s belongs to another tenant than %s", aggregateID, event.TenantID))
+		}
+		if errors.Is(err, service.ErrAggregateTypeMismatch) || errors.Is(err, service.ErrInvalidAggregateType) {
+			return c.reject(msg, eventType, schemaVersion, err.Error())
+		}
 		log.Printf("Failed to save event: %v", err)
 		return err
 	}
 
 	log.Printf("Event Store: Successfully saved event %s", eventType)
 
-	// Otomatik snapshot oluştur (her 50 event'te bir)
+	// Snapshot politikası snapshot worker'ında değerlendirilir; ingestion snapshot maliyetini beklemez
 	if c.snapshotService != nil {
-		if err := c.snapshotService.AutoCreateSnapshots(aggregateID, 50); err != nil {
-			log.Printf("Warning: Failed to auto-create snapshot for aggregate %s: %v", aggregateID, err)
-		}
+		c.snapshotService.QueueSnapshot(event)
 	}
 
 	return nil
 }
 
+// envelopeMetadata - {"metadata": {...}} alanını okur; eski producer'larda alan yoktur
+func envelopeMetadata(envelope map[string]interface{}) model.EventMetadata {
+	metadata, _ := envelope["metadata"].(map[string]interface{})
+
+	value := func(key string) string {
+		v, _ := metadata[key].(string)
+		return v
+	}
+
+	return model.EventMetadata{
+		CorrelationID: value("correlation_id"),
+		CausationID:   value("causation_id"),
+		ActorID:       value("actor_id"),
+		SourceService: value("source_service"),
+	}
+}
+
+// validate - data kısmını registry'deki tip/versiyon şemasına göre doğrular
+func (c *EventStoreConsumer) validate(eventType string, schemaVersion int, data map[string]interface{}) error {
+	if c.schemaMode == SchemaModeOff || c.registry == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	return c.registry.Validate(eventType, schemaVersion, payload)
+}
+
+// reject - Geçersiz mesajı event store'a yazmadan ayırır
+// Quarantine modunda mesaj karantina topic'ine gider; gönderilemezse hata döner
+func (c *EventStoreConsumer) reject(msg *kafka.Message, eventType string, schemaVersion int, reason string) error {
+	if c.schemaMode == SchemaModeQuarantine {
+		return c.quarantine.Send(msg, eventType, schemaVersion, reason)
+	}
+
+	log.Printf("Event Store: Rejected message %s/%d/%d: %s",
+		topicName(msg), msg.TopicPartition.Partition, msg.TopicPartition.Offset, reason)
+	return nil
+}
+
+// legacyEventID - event_id taşımayan mesajlar için topic/partition/offset'ten UUID üretir
+func legacyEventID(msg *kafka.Message) string {
+	key := fmt.Sprintf("%s/%d/%d", topicName(msg), msg.TopicPartition.Partition, msg.TopicPartition.Offset)
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(key)).String()
+}
+
 func (c *EventStoreConsumer) Close() {
 	c.consumer.Close()
 }
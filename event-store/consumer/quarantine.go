@@ -0,0 +1,106 @@
+package consumer
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// SchemaMode - Şemaya uymayan mesajlara ne yapılacağı
+type SchemaMode string
+
+const (
+	// SchemaModeQuarantine - Mesaj karantina topic'ine aynen yazılır, event store'a kaydedilmez
+	SchemaModeQuarantine SchemaMode = "quarantine"
+	// SchemaModeReject - Mesaj loglanıp atlanır
+	SchemaModeReject SchemaMode = "reject"
+	// SchemaModeOff - Doğrulama yapılmaz
+	SchemaModeOff SchemaMode = "off"
+)
+
+// ParseSchemaMode - Boş değer quarantine kabul edilir
+func ParseSchemaMode(value string) (SchemaMode, error) {
+	switch mode := SchemaMode(value); mode {
+	case "":
+		return SchemaModeQuarantine, nil
+	case SchemaModeQuarantine, SchemaModeReject, SchemaModeOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown schema mode %q (expected quarantine, reject or off)", value)
+	}
+}
+
+// Quarantine - Geçersiz mesajları sebebiyle birlikte ayrı bir topic'e yazar
+// Orijinal mesaj değişmeden saklanır; düzeltildikten sonra ana topic'e tekrar gönderilebilir
+type Quarantine struct {
+	producer *kafka.Producer
+	topic    string
+}
+
+func NewQuarantine(broker, topic string) (*Quarantine, error) {
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": broker})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quarantine producer: %w", err)
+	}
+
+	return &Quarantine{producer: p, topic: topic}, nil
+}
+
+// Send - Mesajı karantinaya yazar ve broker onayını bekler
+// Hata dönerse consumer mesajın offset'ini commit etmez ve mesajı tekrar dener
+func (q *Quarantine) Send(msg *kafka.Message, eventType string, schemaVersion int, reason string) error {
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: "quarantine-reason", Value: []byte(reason)},
+		kafka.Header{Key: "quarantine-event-type", Value: []byte(eventType)},
+		kafka.Header{Key: "quarantine-schema-version", Value: []byte(strconv.Itoa(schemaVersion))},
+		kafka.Header{Key: "quarantine-source", Value: []byte(fmt.Sprintf("%s/%d/%d", topicName(msg), msg.TopicPartition.Partition, msg.TopicPartition.Offset))},
+	)
+
+	delivery := make(chan kafka.Event, 1)
+	err := q.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &q.topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}, delivery)
+	if err != nil {
+		return fmt.Errorf("failed to produce quarantine message: %w", err)
+	}
+
+	select {
+	case e := <-delivery:
+		if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+			return fmt.Errorf("quarantine delivery failed: %w", m.TopicPartition.Error)
+		}
+	case <-time.After(10 * time.Second):
+		return fmt.Errorf("quarantine delivery timed out")
+	}
+
+	log.Printf("message %s quarantined to %s: %s", headerValue(headers, "quarantine-source"), q.topic, reason)
+	return nil
+}
+
+func (q *Quarantine) Close() {
+	q.producer.Flush(5000)
+	q.producer.Close()
+}
+
+func topicName(msg *kafka.Message) string {
+	if msg.TopicPartition.Topic == nil {
+		return ""
+	}
+	return *msg.TopicPartition.Topic
+}
+
+func headerValue(headers []kafka.Header, key string) string {
+	for _, header := range headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
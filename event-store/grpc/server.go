@@ -3,27 +3,43 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net"
+	"strconv"
+	"time"
 
+	"github.com/eyupaydin41/event-store/model"
 	pb "github.com/eyupaydin41/event-store/proto"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+	"github.com/eyupaydin41/event-store/schema"
 	"github.com/eyupaydin41/event-store/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// ConflictReason - Concurrency conflict durumunda ErrorInfo.Reason değeri
+const ConflictReason = "WRONG_EXPECTED_VERSION"
+
 // EventStoreServer - gRPC server implementation
 // HTTP'deki Gin handler'ların karşılığı
 type EventStoreServer struct {
 	pb.UnimplementedEventStoreServiceServer // Forward compatibility için gerekli
 	eventService                            *service.EventService
 	snapshotService                         *service.SnapshotService
+	// registry - nil ise AppendEvents payload'ları doğrulamaz
+	registry *schema.Registry
 }
 
 // NewEventStoreServer - Constructor
-func NewEventStoreServer(eventService *service.EventService, snapshotService *service.SnapshotService) *EventStoreServer {
+func NewEventStoreServer(eventService *service.EventService, snapshotService *service.SnapshotService, registry *schema.Registry) *EventStoreServer {
 	return &EventStoreServer{
 		eventService:    eventService,
 		snapshotService: snapshotService,
+		registry:        registry,
 	}
 }
 
@@ -38,32 +54,151 @@ func (s *EventStoreServer) GetAggregateEvents(
 	// HTTP'de: aggregateID := c.Param("id")
 	aggregateID := req.AggregateId
 
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Mevcut service'i kullan
 	// fromVersion = 0 tüm event'leri getir
-	events, err := s.eventService.GetEventsByAggregateID(aggregateID, 0)
+	events, err := s.eventService.GetEventsByAggregateID(tenantID, aggregateID, 0)
 	if err != nil {
 		log.Printf("gRPC: Error fetching events: %v", err)
 		// HTTP'de: c.JSON(500, ...)
 		return nil, err
 	}
 
-	// Domain event'leri protobuf message'a dönüştür
 	// HTTP'de: c.JSON(200, events)
+	return &pb.GetAggregateEventsResponse{
+		Events: toProtoEvents(events),
+	}, nil
+}
+
+// ReadAll - Tüm stream'lerdeki event'leri global position sırasıyla okur
+// HTTP karşılığı: GET /events/replay?from_position=
+func (s *EventStoreServer) ReadAll(
+	ctx context.Context,
+	req *pb.ReadAllRequest,
+) (*pb.ReadAllResponse, error) {
+	log.Printf("gRPC: ReadAll called from position %d", req.FromPosition)
+
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maxCount := int(req.MaxCount)
+	if maxCount == 0 {
+		maxCount = 1000
+	}
+
+	events, err := s.eventService.GetEventsFromPosition(tenantID, req.FromPosition, req.EventType, req.Category, maxCount)
+	if err != nil {
+		log.Printf("gRPC: Error reading events: %v", err)
+		return nil, readError(err)
+	}
+
+	nextPosition := req.FromPosition
+	if nextPosition == 0 {
+		nextPosition = 1
+	}
+	if len(events) > 0 {
+		nextPosition = events[len(events)-1].Position + 1
+	}
+
+	return &pb.ReadAllResponse{
+		Events:       toProtoEvents(events),
+		NextPosition: nextPosition,
+	}, nil
+}
+
+// ReadStream - Aggregate stream'ini event event gönderir, event sayısında sınır yoktur
+// HTTP karşılığı: GET /events/aggregate/:id (tüm event'leri tek response'ta döner)
+func (s *EventStoreServer) ReadStream(
+	req *pb.ReadStreamRequest,
+	stream grpc.ServerStreamingServer[pb.Event],
+) error {
+	log.Printf("gRPC: ReadStream called for aggregate_id: %s (versions %d-%d, direction: %s)",
+		req.AggregateId, req.FromVersion, req.ToVersion, req.Direction)
+
+	if req.AggregateId == "" {
+		return status.Error(codes.InvalidArgument, "aggregate_id is required")
+	}
+	if req.ToVersion > 0 && req.FromVersion > req.ToVersion {
+		return status.Error(codes.InvalidArgument, "from_version must not be greater than to_version")
+	}
+
+	tenantID, err := tenantFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	query := model.StreamQuery{
+		TenantID:    tenantID,
+		AggregateID: req.AggregateId,
+		FromVersion: req.FromVersion,
+		ToVersion:   req.ToVersion,
+		EventTypes:  req.EventTypes,
+		MaxCount:    int(req.MaxCount),
+	}
+	if req.Direction == pb.ReadDirection_READ_DIRECTION_BACKWARD {
+		query.Direction = model.ReadBackward
+	}
+
+	err = s.eventService.ReadStream(stream.Context(), query, func(event *model.Event) error {
+		return stream.Send(toProtoEvent(event))
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		log.Printf("gRPC: Error reading stream: %v", err)
+		return readError(err)
+	}
+
+	return nil
+}
+
+// toProtoEvents - Domain event'leri protobuf message'a dönüştürür
+func toProtoEvents(events []*model.Event) []*pb.Event {
 	pbEvents := make([]*pb.Event, len(events))
 	for i, event := range events {
-		pbEvents[i] = &pb.Event{
-			Id:          event.ID,
-			EventType:   event.EventType,
-			AggregateId: event.AggregateID,
-			Version:     int32(event.Version),
-			Timestamp:   event.Timestamp.Format("2006-01-02T15:04:05.999999999Z07:00"),
-			DataJson:    event.Payload,
-		}
+		pbEvents[i] = toProtoEvent(event)
 	}
+	return pbEvents
+}
 
-	return &pb.GetAggregateEventsResponse{
-		Events: pbEvents,
-	}, nil
+func toProtoEvent(event *model.Event) *pb.Event {
+	return &pb.Event{
+		Id:            event.ID,
+		EventType:     event.EventType,
+		AggregateId:   event.AggregateID,
+		Version:       int32(event.Version),
+		Timestamp:     event.Timestamp.Format("2006-01-02T15:04:05.999999999Z07:00"),
+		DataJson:      event.Payload,
+		Position:      event.Position,
+		TenantId:      event.TenantID,
+		AggregateType: model.NormalizeAggregateType(event.AggregateType),
+		Category:      model.NormalizeCategory(event.Category, event.AggregateType),
+		// Okuma yolundaki store event'leri güncel şekle getirdiği için hep tipin son versiyonu
+		SchemaVersion: uint32(event.SchemaVersion),
+		Metadata: &pb.EventMetadata{
+			CorrelationId: event.Metadata.CorrelationID,
+			CausationId:   event.Metadata.CausationID,
+			ActorId:       event.Metadata.ActorID,
+			SourceService: event.Metadata.SourceService,
+		},
+	}
+}
+
+// metadataFromProto - Eksik metadata boş değerlerle kaydedilir
+func metadataFromProto(metadata *pb.EventMetadata) model.EventMetadata {
+	return model.EventMetadata{
+		CorrelationID: metadata.GetCorrelationId(),
+		CausationID:   metadata.GetCausationId(),
+		ActorID:       metadata.GetActorId(),
+		SourceService: metadata.GetSourceService(),
+	}
 }
 
 // GetAggregateWithSnapshot - Snapshot kullanarak aggregate state'ini getir
@@ -75,49 +210,184 @@ func (s *EventStoreServer) GetAggregateWithSnapshot(
 
 	aggregateID := req.AggregateId
 
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Snapshot service ile aggregate'i yükle
 	// Bu otomatik olarak:
 	// 1. Snapshot varsa: snapshot + sonraki eventleri kullanır
 	// 2. Snapshot yoksa: tüm eventleri kullanır
-	aggregate, err := s.snapshotService.LoadAggregateWithSnapshot(aggregateID)
+	loaded, err := s.snapshotService.LoadAggregateWithSnapshot(tenantID, aggregateID)
 	if err != nil {
 		log.Printf("gRPC: Error loading aggregate with snapshot: %v", err)
-		return nil, err
+		return nil, loadError(err)
 	}
 
 	// Aggregate'in state'ini JSON'a serialize et
-	stateJSON, err := json.Marshal(aggregate)
+	stateJSON, err := json.Marshal(loaded.State)
 	if err != nil {
 		log.Printf("gRPC: Error marshaling aggregate state: %v", err)
-		return nil, err
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	// Snapshot'tan mı yüklendi kontrol et
-	hasSnapshot, _ := s.snapshotService.HasSnapshot(aggregateID)
-
-	// EventCount: Toplam kaç event replay edildi
-	eventsReplayed := aggregate.EventCount
-
-	log.Printf("gRPC: Aggregate loaded - Version: %d, EventsReplayed: %d, FromSnapshot: %v",
-		aggregate.Version, eventsReplayed, hasSnapshot)
+	log.Printf("gRPC: %s aggregate loaded - Version: %d, EventsReplayed: %d, FromSnapshot: %v",
+		loaded.AggregateType, loaded.State.StreamVersion(), loaded.EventsReplayed, loaded.FromSnapshot)
 
 	return &pb.GetAggregateWithSnapshotResponse{
 		AggregateId:    aggregateID,
-		Version:        aggregate.Version,
+		Version:        loaded.State.StreamVersion(),
 		StateJson:      string(stateJSON),
-		FromSnapshot:   hasSnapshot,
-		EventsReplayed: uint32(eventsReplayed),
+		FromSnapshot:   loaded.FromSnapshot,
+		EventsReplayed: uint32(loaded.EventsReplayed),
 	}, nil
 }
 
+// loadError - Aggregate yükleme hatasının gRPC status'u
+// Stream yoksa NotFound; reducer'ı olmayan tip ya da işlenmeyen event FailedPrecondition
+func loadError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrAggregateNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, reducer.ErrUnknownAggregateType), errors.Is(err, reducer.ErrUnknownEventType):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return readError(err)
+}
+
+// AppendEvents - Optimistic concurrency ile event ekler
+// Stream beklenen versiyonda değilse ABORTED + ErrorInfo detayı döner
+func (s *EventStoreServer) AppendEvents(
+	ctx context.Context,
+	req *pb.AppendEventsRequest,
+) (*pb.AppendEventsResponse, error) {
+	log.Printf("gRPC: AppendEvents called for aggregate_id: %s (%d events)", req.AggregateId, len(req.Events))
+
+	if req.AggregateId == "" {
+		return nil, status.Error(codes.InvalidArgument, "aggregate_id is required")
+	}
+	if len(req.Events) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one event is required")
+	}
+
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := expectedVersionFromProto(req.ExpectedVersion)
+
+	events := make([]*model.Event, len(req.Events))
+	for i, pbEvent := range req.Events {
+		if pbEvent.EventType == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "event %d: event_type is required", i)
+		}
+
+		var timestamp time.Time
+		if pbEvent.Timestamp != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, pbEvent.Timestamp)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "event %d: invalid timestamp: %v", i, err)
+			}
+			timestamp = parsed
+		}
+
+		schemaVersion := pbEvent.SchemaVersion
+		if schemaVersion == 0 {
+			schemaVersion = schema.DefaultVersion
+		}
+
+		if s.registry != nil {
+			if err := s.registry.Validate(pbEvent.EventType, int(schemaVersion), []byte(pbEvent.DataJson)); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "event %d: %v", i, err)
+			}
+		}
+
+		events[i] = &model.Event{
+			ID:            pbEvent.Id,
+			EventType:     pbEvent.EventType,
+			Payload:       pbEvent.DataJson,
+			Timestamp:     timestamp,
+			SchemaVersion: uint16(schemaVersion),
+			Metadata:      metadataFromProto(pbEvent.Metadata),
+			AggregateType: req.AggregateType,
+		}
+	}
+
+	lastVersion, err := s.eventService.AppendEvents(tenantID, req.AggregateId, expected, events)
+	if err != nil {
+		var conflict *service.ConcurrencyConflictError
+		if errors.As(err, &conflict) {
+			log.Printf("gRPC: AppendEvents conflict: %v", conflict)
+			return nil, conflictStatus(conflict)
+		}
+		if errors.Is(err, service.ErrTenantMismatch) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, service.ErrAggregateTypeMismatch) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, service.ErrInvalidAggregateType) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Printf("gRPC: Error appending events: %v", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.AppendEventsResponse{
+		AggregateId:  req.AggregateId,
+		FirstVersion: lastVersion - uint32(len(events)) + 1,
+		LastVersion:  lastVersion,
+	}, nil
+}
+
+// expectedVersionFromProto - Proto ExpectedVersion'ı domain modeline çevirir
+// expected_version gönderilmezse ANY kabul edilir
+func expectedVersionFromProto(ev *pb.ExpectedVersion) model.ExpectedVersion {
+	if ev == nil {
+		return model.ExpectedVersion{Kind: model.ExpectedVersionAny}
+	}
+
+	switch ev.Kind {
+	case pb.ExpectedVersionKind_EXPECTED_VERSION_NO_STREAM:
+		return model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}
+	case pb.ExpectedVersionKind_EXPECTED_VERSION_EXACT:
+		return model.ExpectedVersion{Kind: model.ExpectedVersionExact, Version: ev.Version}
+	default:
+		return model.ExpectedVersion{Kind: model.ExpectedVersionAny}
+	}
+}
+
+// conflictStatus - Conflict hatasını client'ın parse edebileceği gRPC status'a çevirir
+func conflictStatus(conflict *service.ConcurrencyConflictError) error {
+	st := status.New(codes.Aborted, conflict.Error())
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: ConflictReason,
+		Domain: "eventstore",
+		Metadata: map[string]string{
+			"aggregate_id":          conflict.AggregateID,
+			"expected_version_kind": conflict.ExpectedVersion.String(),
+			"expected_version":      strconv.FormatUint(uint64(conflict.ExpectedVersion.Version), 10),
+			"actual_version":        strconv.FormatUint(uint64(conflict.ActualVersion), 10),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
 // StartGRPCServer - gRPC server'ı başlat
 // HTTP'de: router.Run(":8090")
-func StartGRPCServer(port string, eventService *service.EventService, snapshotService *service.SnapshotService) error {
+func StartGRPCServer(port string, eventService *service.EventService, snapshotService *service.SnapshotService, registry *schema.Registry) error {
 	// gRPC server oluştur
 	grpcServer := grpc.NewServer()
 
 	// Service'i register et
-	pb.RegisterEventStoreServiceServer(grpcServer, NewEventStoreServer(eventService, snapshotService))
+	pb.RegisterEventStoreServiceServer(grpcServer, NewEventStoreServer(eventService, snapshotService, registry))
 
 	// Listen
 	listener, err := net.Listen("tcp", port)
@@ -128,3 +398,12 @@ func StartGRPCServer(port string, eventService *service.EventService, snapshotSe
 	log.Printf("gRPC server starting on %s", port)
 	return grpcServer.Serve(listener)
 }
+
+// readError - Okuma hatasını gRPC status'una çevirir
+// Arşivlenmiş aralık (ARCHIVE_READ_MODE=report) sunucu hatası değildir: FailedPrecondition
+func readError(err error) error {
+	if errors.Is(err, repository.ErrArchived) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
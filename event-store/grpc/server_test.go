@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eyupaydin41/event-store/catalog"
+	"github.com/eyupaydin41/event-store/model"
+	pb "github.com/eyupaydin41/event-store/proto"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+	"github.com/eyupaydin41/event-store/schema"
+	"github.com/eyupaydin41/event-store/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestServer() *EventStoreServer {
+	eventRepo := repository.NewMemoryEventRepository()
+	snapshotRepo := repository.NewMemorySnapshotRepository()
+	return NewEventStoreServer(
+		service.NewEventService(eventRepo, catalog.NewCatalog()),
+		service.NewSnapshotService(snapshotRepo, eventRepo, catalog.NewCatalog(), reducer.NewDefaultRegistry()),
+		nil,
+	)
+}
+
+func TestAppendEventsReturnsAbortedOnConflict(t *testing.T) {
+	server := newTestServer()
+	ctx := context.Background()
+
+	req := &pb.AppendEventsRequest{
+		AggregateId:     "user-1",
+		ExpectedVersion: &pb.ExpectedVersion{Kind: pb.ExpectedVersionKind_EXPECTED_VERSION_NO_STREAM},
+		Events:          []*pb.NewEvent{{EventType: "user.created", DataJson: `{"email":"a@example.com"}`}},
+	}
+
+	resp, err := server.AppendEvents(ctx, req)
+	if err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+	if resp.FirstVersion != 1 || resp.LastVersion != 1 {
+		t.Fatalf("expected versions 1..1, got %d..%d", resp.FirstVersion, resp.LastVersion)
+	}
+
+	_, err = server.AppendEvents(ctx, &pb.AppendEventsRequest{
+		AggregateId:     "user-1",
+		ExpectedVersion: &pb.ExpectedVersion{Kind: pb.ExpectedVersionKind_EXPECTED_VERSION_EXACT, Version: 0},
+		Events:          []*pb.NewEvent{{EventType: "user.email.changed", DataJson: `{}`}},
+	})
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Aborted {
+		t.Fatalf("expected ABORTED, got %v", err)
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = d
+		}
+	}
+	if info == nil || info.Reason != ConflictReason {
+		t.Fatalf("expected ErrorInfo with reason %s, got %v", ConflictReason, st.Details())
+	}
+	if info.Metadata["actual_version"] != "1" {
+		t.Errorf("expected actual_version 1, got %s", info.Metadata["actual_version"])
+	}
+}
+
+func TestGetAggregateWithSnapshotFromMemoryStore(t *testing.T) {
+	server := newTestServer()
+	ctx := context.Background()
+
+	_, err := server.AppendEvents(ctx, &pb.AppendEventsRequest{
+		AggregateId: "user-1",
+		Events: []*pb.NewEvent{
+			{EventType: "user.created", DataJson: `{"aggregate_id":"user-1","email":"a@example.com"}`},
+			{EventType: "user.email.changed", DataJson: `{"new_email":"b@example.com"}`},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	resp, err := server.GetAggregateWithSnapshot(ctx, &pb.GetAggregateWithSnapshotRequest{AggregateId: "user-1"})
+	if err != nil {
+		t.Fatalf("GetAggregateWithSnapshot: %v", err)
+	}
+	if resp.Version != 2 {
+		t.Errorf("expected version 2, got %d", resp.Version)
+	}
+	if resp.FromSnapshot {
+		t.Errorf("expected aggregate to be replayed from events")
+	}
+}
+
+func TestAppendEventsRejectsPayloadsThatDoNotMatchSchema(t *testing.T) {
+	registry, err := schema.LoadRegistry("")
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+
+	eventRepo := repository.NewMemoryEventRepository()
+	server := NewEventStoreServer(
+		service.NewEventService(eventRepo, catalog.NewCatalog()),
+		service.NewSnapshotService(repository.NewMemorySnapshotRepository(), eventRepo, catalog.NewCatalog(), reducer.NewDefaultRegistry()),
+		registry,
+	)
+
+	_, err = server.AppendEvents(context.Background(), &pb.AppendEventsRequest{
+		AggregateId: "user-1",
+		Events: []*pb.NewEvent{
+			{EventType: "user.created", SchemaVersion: 1, DataJson: `{"aggregate_id":"user-1","email":"a@example.com"}`},
+		},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	count, err := eventRepo.CountEvents(model.DefaultTenantID)
+	if err != nil {
+		t.Fatalf("CountEvents: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected nothing to be stored, got %d events", count)
+	}
+}
+
+func TestAppendEventsStoresMetadata(t *testing.T) {
+	server := newTestServer()
+	ctx := context.Background()
+
+	metadata := &pb.EventMetadata{
+		CorrelationId: "corr-1",
+		CausationId:   "req-1",
+		ActorId:       "user-1",
+		SourceService: "auth-service",
+	}
+	_, err := server.AppendEvents(ctx, &pb.AppendEventsRequest{
+		AggregateId: "user-1",
+		Events: []*pb.NewEvent{
+			{EventType: "user.created", DataJson: `{"aggregate_id":"user-1"}`, Metadata: metadata},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	resp, err := server.GetAggregateEvents(ctx, &pb.GetAggregateEventsRequest{AggregateId: "user-1"})
+	if err != nil {
+		t.Fatalf("GetAggregateEvents: %v", err)
+	}
+	if len(resp.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(resp.Events))
+	}
+
+	got := resp.Events[0].Metadata
+	if got.GetCorrelationId() != "corr-1" || got.GetCausationId() != "req-1" ||
+		got.GetActorId() != "user-1" || got.GetSourceService() != "auth-service" {
+		t.Errorf("unexpected metadata: %v", got)
+	}
+	if resp.Events[0].SchemaVersion != 1 {
+		t.Errorf("expected schema version 1, got %d", resp.Events[0].SchemaVersion)
+	}
+}
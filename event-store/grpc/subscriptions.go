@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/eyupaydin41/event-store/model"
+	pb "github.com/eyupaydin41/event-store/proto"
+	"github.com/eyupaydin41/event-store/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SubscribeAll - Tüm event'leri from_position'dan itibaren stream eder
+// Client bağlantıyı kapatana kadar açık kalır
+func (s *EventStoreServer) SubscribeAll(
+	req *pb.SubscribeAllRequest,
+	stream grpc.ServerStreamingServer[pb.SubscriptionMessage],
+) error {
+	log.Printf("gRPC: SubscribeAll called from position %d (event types: %v, category: %q)", req.FromPosition, req.EventTypes, req.Category)
+
+	tenantID, err := tenantFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	err = s.eventService.SubscribeAll(
+		stream.Context(),
+		req.FromPosition,
+		subscriptionOptions(tenantID, req.EventTypes, req.Category, stream),
+		sendEvent(stream),
+	)
+	return subscriptionStatus(err)
+}
+
+// SubscribeToStream - Tek aggregate'in event'lerini from_version'dan itibaren stream eder
+func (s *EventStoreServer) SubscribeToStream(
+	req *pb.SubscribeToStreamRequest,
+	stream grpc.ServerStreamingServer[pb.SubscriptionMessage],
+) error {
+	log.Printf("gRPC: SubscribeToStream called for aggregate_id: %s from version %d", req.AggregateId, req.FromVersion)
+
+	if req.AggregateId == "" {
+		return status.Error(codes.InvalidArgument, "aggregate_id is required")
+	}
+
+	tenantID, err := tenantFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	err = s.eventService.SubscribeToStream(
+		stream.Context(),
+		req.AggregateId,
+		req.FromVersion,
+		subscriptionOptions(tenantID, req.EventTypes, "", stream),
+		sendEvent(stream),
+	)
+	return subscriptionStatus(err)
+}
+
+func subscriptionOptions(tenantID string, eventTypes []string, category string, stream grpc.ServerStreamingServer[pb.SubscriptionMessage]) service.SubscriptionOptions {
+	return service.SubscriptionOptions{
+		TenantID:   tenantID,
+		EventTypes: eventTypes,
+		Category:   category,
+		OnCaughtUp: func() error {
+			return stream.Send(&pb.SubscriptionMessage{
+				Content: &pb.SubscriptionMessage_CaughtUp{CaughtUp: true},
+			})
+		},
+	}
+}
+
+func sendEvent(stream grpc.ServerStreamingServer[pb.SubscriptionMessage]) func(*model.Event) error {
+	return func(event *model.Event) error {
+		return stream.Send(&pb.SubscriptionMessage{
+			Content: &pb.SubscriptionMessage_Event{Event: toProtoEvent(event)},
+		})
+	}
+}
+
+// subscriptionStatus - Client'ın bağlantıyı kapatması normal sonlanmadır
+func subscriptionStatus(err error) error {
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	log.Printf("gRPC: subscription ended with error: %v", err)
+	return status.Error(codes.Internal, err.Error())
+}
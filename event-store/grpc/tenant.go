@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/eyupaydin41/event-store/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TenantMetadataKey - İsteğin tenant'ını taşıyan gRPC metadata anahtarı (HTTP'deki X-Tenant-ID)
+const TenantMetadataKey = "x-tenant-id"
+
+// tenantFromContext - Metadata'da tenant yoksa default tenant; geçersiz değer InvalidArgument
+func tenantFromContext(ctx context.Context) (string, error) {
+	var tenantID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(TenantMetadataKey); len(values) > 0 {
+			tenantID = values[0]
+		}
+	}
+
+	tenantID = model.NormalizeTenantID(tenantID)
+	if !model.ValidTenantID(tenantID) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid %s metadata", TenantMetadataKey)
+	}
+	return tenantID, nil
+}
@@ -1,51 +1,219 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/eyupaydin41/event-store/api"
+	"github.com/eyupaydin41/event-store/archive"
+	"github.com/eyupaydin41/event-store/catalog"
 	. "github.com/eyupaydin41/event-store/config"
 	"github.com/eyupaydin41/event-store/consumer"
 	grpcserver "github.com/eyupaydin41/event-store/grpc"
+	"github.com/eyupaydin41/event-store/privacy"
+	"github.com/eyupaydin41/event-store/reducer"
 	"github.com/eyupaydin41/event-store/repository"
+	"github.com/eyupaydin41/event-store/schema"
 	"github.com/eyupaydin41/event-store/service"
+	"github.com/eyupaydin41/event-store/upcast"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	LoadEnv()
 
-	conn := InitClickHouse()
-	defer conn.Close()
+	// Repositories - storage backend EVENT_STORE_BACKEND ile seçilir
+	var eventRepo repository.EventStore
+	var snapshotRepo repository.SnapshotStore
+	var keyRepo repository.KeyStore
+	// statsRepo - Sadece ClickHouse'ta (materialized view'lar); diğer backend'lerde /stats 501 döner
+	var statsRepo repository.StatsStore
 
-	// Repositories
-	eventRepo := repository.NewEventRepository(conn)
-	snapshotRepo := repository.NewSnapshotRepository(conn)
+	switch backend := GetEnv("EVENT_STORE_BACKEND"); backend {
+	case "", "clickhouse":
+		conn := InitClickHouse()
+		defer conn.Close()
 
-	// Snapshot tablosunu oluştur
-	if err := snapshotRepo.CreateTable(); err != nil {
-		log.Printf("Warning: Failed to create snapshot table: %v", err)
+		eventRepo = repository.NewEventRepository(conn)
+		chSnapshotRepo := repository.NewSnapshotRepository(conn)
+
+		// Snapshot tablosunu oluştur
+		if err := chSnapshotRepo.CreateTable(); err != nil {
+			log.Printf("Warning: Failed to create snapshot table: %v", err)
+		}
+		snapshotRepo = chSnapshotRepo
+
+		// İstatistik materialized view'ları consumer başlamadan oluşturulur (ilk seferde backfill edilir)
+		chStatsRepo := repository.NewStatsRepository(conn)
+		if err := chStatsRepo.CreateViews(); err != nil {
+			log.Printf("Warning: Failed to create stats views, /stats is disabled: %v", err)
+		} else {
+			statsRepo = chStatsRepo
+		}
+
+		chKeyRepo := repository.NewKeyRepository(conn)
+		if err := chKeyRepo.CreateTable(); err != nil {
+			log.Fatalf("Failed to create data key table: %v", err)
+		}
+		keyRepo = chKeyRepo
+	case "postgres":
+		db := InitPostgres()
+		defer db.Close()
+
+		eventRepo = repository.NewPostgresEventRepository(db)
+		pgSnapshotRepo := repository.NewPostgresSnapshotRepository(db)
+
+		if err := pgSnapshotRepo.CreateTable(); err != nil {
+			log.Fatalf("Failed to create snapshot table: %v", err)
+		}
+		snapshotRepo = pgSnapshotRepo
+		keyRepo = postgresKeyRepository(db)
+	case "memory":
+		// ClickHouse olmadan local çalışma için (process kapanınca veriler kaybolur)
+		log.Println("Warning: using in-memory event store, events will not be persisted")
+		eventRepo = repository.NewMemoryEventRepository()
+		snapshotRepo = repository.NewMemorySnapshotRepository()
+		keyRepo = repository.NewMemoryKeyRepository()
+	default:
+		log.Fatalf("unknown EVENT_STORE_BACKEND: %s (expected clickhouse, postgres or memory)", backend)
+	}
+
+	// Soğuk depolama - ARCHIVE_DIR verilirse kapanmış aylık partition'lar dosyalara taşınır
+	// ve okumalar arşive düşer (ARCHIVE_READ_MODE=report ise 410 döner)
+	var eventArchive *archive.Archive
+	var archiver *archive.Archiver
+	if archiveDir := GetEnv("ARCHIVE_DIR"); archiveDir != "" {
+		var err error
+		eventArchive, err = archive.Open(archiveDir)
+		if err != nil {
+			log.Fatalf("failed to open event archive: %v", err)
+		}
+
+		readMode, err := archive.ParseReadMode(GetEnv("ARCHIVE_READ_MODE"))
+		if err != nil {
+			log.Fatalf("invalid ARCHIVE_READ_MODE: %v", err)
+		}
+
+		if partitions, ok := eventRepo.(repository.PartitionStore); ok {
+			archiver = archive.NewArchiver(partitions, eventArchive, envInt("ARCHIVE_AFTER_MONTHS", 12))
+			go archiver.Start(context.Background(), envDuration("ARCHIVE_INTERVAL", 24*time.Hour))
+		} else {
+			log.Println("Warning: event store backend has no partitions, archival job is disabled")
+		}
+
+		// Arşivdeki satırlar tablodaki haliyle (şifreli) saklanır; decorator privacy'nin altında durur
+		eventRepo = archive.NewStore(eventRepo, eventArchive, readMode)
+	}
+
+	// Veri anahtarları varsayılan olarak event backend'inde tutulur;
+	// KEY_STORE_BACKEND=postgres ile ayrı bir veritabanına (KEY_DB_*) alınabilir
+	switch keyBackend := GetEnv("KEY_STORE_BACKEND"); keyBackend {
+	case "":
+	case "postgres":
+		keyDB := InitKeyStorePostgres()
+		defer keyDB.Close()
+		keyRepo = postgresKeyRepository(keyDB)
+	case "memory":
+		log.Println("Warning: using in-memory key store, encrypted fields will be unreadable after restart")
+		keyRepo = repository.NewMemoryKeyRepository()
+	default:
+		log.Fatalf("unknown KEY_STORE_BACKEND: %s (expected postgres or memory)", keyBackend)
+	}
+
+	// PII alanları aggregate başına anahtarla şifrelenir (crypto-shredding)
+	privacyRepo := privacy.NewStore(eventRepo, keyRepo, privacy.DefaultFields())
+	snapshotRepo = privacy.NewSnapshotStore(snapshotRepo, keyRepo)
+
+	// Okuma yolundaki tüm servisler event'leri güncel şema şekliyle görür (kayıtlı satırlar değişmez)
+	eventRepo = upcast.NewStore(privacyRepo, upcast.NewDefaultChain())
+
+	// Stream catalog - gömülü aggregate tipleri/kategoriler + STREAM_CATALOG_FILE'daki ek/override tanımlar
+	streamCatalog, err := catalog.LoadCatalog(GetEnv("STREAM_CATALOG_FILE"))
+	if err != nil {
+		log.Fatalf("failed to load stream catalog: %v", err)
 	}
 
 	// Services
-	eventService := service.NewEventService(eventRepo)
-	replayService := service.NewReplayService(eventRepo)
-	snapshotService := service.NewSnapshotService(snapshotRepo, eventRepo)
+	eventService := service.NewEventService(eventRepo, streamCatalog)
+	// Aggregate tipine göre state kuran reducer'lar (replay, snapshot, gRPC yükleme)
+	reducers := reducer.NewDefaultRegistry()
+
+	replayService := service.NewReplayService(eventRepo, reducers)
+	snapshotService := service.NewSnapshotService(snapshotRepo, eventRepo, streamCatalog, reducers)
+	privacyService := service.NewPrivacyService(keyRepo, snapshotRepo, eventRepo)
 
 	kafkaBroker := GetEnv("KAFKA_BROKER")
 	kafkaTopic := GetEnv("KAFKA_TOPIC")
 	kafkaGroup := GetEnv("KAFKA_GROUP")
 
-	eventConsumer := consumer.NewEventStoreConsumer(kafkaBroker, kafkaGroup, kafkaTopic, eventService, snapshotService)
+	// Schema registry - gömülü şemalar + EVENT_SCHEMA_DIR'deki ek/override şemalar
+	registry, err := schema.LoadRegistry(GetEnv("EVENT_SCHEMA_DIR"))
+	if err != nil {
+		log.Fatalf("failed to load event schemas: %v", err)
+	}
+
+	schemaMode, err := consumer.ParseSchemaMode(GetEnv("EVENT_SCHEMA_MODE"))
+	if err != nil {
+		log.Fatalf("invalid EVENT_SCHEMA_MODE: %v", err)
+	}
+
+	var quarantine *consumer.Quarantine
+	if schemaMode == consumer.SchemaModeQuarantine {
+		quarantineTopic := GetEnv("KAFKA_QUARANTINE_TOPIC")
+		if quarantineTopic == "" {
+			quarantineTopic = kafkaTopic + "-quarantine"
+		}
+		quarantine, err = consumer.NewQuarantine(kafkaBroker, quarantineTopic)
+		if err != nil {
+			log.Fatalf("failed to create quarantine: %v", err)
+		}
+		defer quarantine.Close()
+	}
+
+	// Doğrulama kapalıysa gRPC AppendEvents de payload'ları kontrol etmez
+	appendRegistry := registry
+	if schemaMode == consumer.SchemaModeOff {
+		appendRegistry = nil
+	}
+
+	eventConsumer := consumer.NewEventStoreConsumer(kafkaBroker, kafkaGroup, kafkaTopic, eventService, snapshotService, registry, schemaMode, quarantine)
 	go eventConsumer.Start()
 
+	// Consumer'ın kuyruğa verdiği stream'lerin snapshot politikasını değerlendiren worker'lar
+	for i := 0; i < envInt("SNAPSHOT_WORKERS", 2); i++ {
+		go snapshotService.RunSnapshotWorker(context.Background())
+	}
+
+	// Reducer'ın eski sürümüyle alınmış, yüklenirken yok sayılan snapshot'ları yeniden oluştur
+	go snapshotService.RebuildStaleSnapshots(context.Background())
+
+	// Snapshot retention: stream başına son SNAPSHOT_KEEP_LAST snapshot kalır (0 = kapalı)
+	if keepLast := envInt("SNAPSHOT_KEEP_LAST", 0); keepLast > 0 {
+		retention := service.NewSnapshotRetention(snapshotRepo, keepLast)
+		go retention.Start(context.Background(), envDuration("SNAPSHOT_RETENTION_INTERVAL", time.Hour))
+	}
+
 	// Handlers
 	handler := api.NewEventHandler(eventService)
 	replayHandler := api.NewReplayHandler(replayService)
 	snapshotHandler := api.NewSnapshotHandler(snapshotService)
+	schemaHandler := api.NewSchemaHandler(registry)
+	privacyHandler := api.NewPrivacyHandler(privacyService)
+	streamHandler := api.NewStreamHandler(eventService)
+	rebuildHandler := api.NewRebuildHandler(service.NewSnapshotRebuilder(snapshotService))
+
+	var statsService *service.StatsService
+	if statsRepo != nil {
+		statsService = service.NewStatsService(statsRepo)
+	}
+	statsHandler := api.NewStatsHandler(statsService)
 
 	router := gin.Default()
+	router.Use(api.TenantScope())
 
 	router.GET("/health", handler.HealthCheck)
 
@@ -55,16 +223,58 @@ func main() {
 	router.GET("/events/replay", handler.ReplayEvents)
 	router.GET("/events/count", handler.GetEventCount)
 
+	// Event istatistikleri (ClickHouse materialized view'ları)
+	router.GET("/stats/events", statsHandler.GetEventCounts)
+	router.GET("/stats/signups", statsHandler.GetSignups)
+	router.GET("/stats/password-changes", statsHandler.GetPasswordChanges)
+	router.GET("/stats/logins/user-agents", statsHandler.GetLoginsByUserAgent)
+	router.GET("/stats/aggregates/top", statsHandler.GetTopAggregates)
+
+	// Stream kategorileri
+	router.GET("/categories", streamHandler.ListCategories)
+	router.GET("/categories/:category/streams", streamHandler.ListStreams)
+
 	// Snapshot endpoints
 	router.POST("/snapshots/:aggregate_id", snapshotHandler.CreateSnapshot)
 	router.GET("/snapshots/:aggregate_id", snapshotHandler.GetLatestSnapshot)
+	router.GET("/snapshot-queue", snapshotHandler.GetQueueStats)
 	router.GET("/snapshots/:aggregate_id/state", snapshotHandler.GetAggregateState)
+	router.GET("/snapshots/:aggregate_id/policy", snapshotHandler.GetPolicy)
+	router.GET("/snapshots/:aggregate_id/versions", snapshotHandler.ListSnapshots)
+	router.GET("/snapshots/:aggregate_id/verify", snapshotHandler.VerifySnapshot)
+	router.DELETE("/snapshots/:aggregate_id", snapshotHandler.DeleteSnapshots)
+	router.DELETE("/snapshots/:aggregate_id/versions/:version", snapshotHandler.DeleteSnapshot)
+
+	// Toplu snapshot rebuild (eventctl rebuild-snapshots da bunları kullanır)
+	router.POST("/snapshot-rebuilds", rebuildHandler.StartRebuild)
+	router.GET("/snapshot-rebuilds", rebuildHandler.ListRebuilds)
+	router.GET("/snapshot-rebuilds/:id", rebuildHandler.GetRebuild)
+	router.POST("/snapshot-rebuilds/:id/cancel", rebuildHandler.CancelRebuild)
+	router.POST("/snapshot-rebuilds/:id/resume", rebuildHandler.ResumeRebuild)
+
+	// Schema registry endpoints
+	router.GET("/schemas", schemaHandler.ListSchemas)
+	router.GET("/schemas/:event_type", schemaHandler.GetLatestSchema)
+	router.GET("/schemas/:event_type/versions/:version", schemaHandler.GetSchema)
+	router.POST("/schemas/:event_type/versions/:version/validate", schemaHandler.ValidatePayload)
+
+	// Privacy endpoints (crypto-shredding)
+	router.POST("/privacy/forget/:aggregate_id", privacyHandler.ForgetUser)
+	router.GET("/privacy/:aggregate_id", privacyHandler.GetPrivacyStatus)
+
+	// Archive endpoints (ARCHIVE_DIR verildiyse)
+	if eventArchive != nil {
+		archiveHandler := api.NewArchiveHandler(eventArchive, archiver)
+		router.GET("/archive", archiveHandler.GetManifest)
+		router.POST("/archive/run", archiveHandler.RunArchive)
+		router.GET("/archive/verify", archiveHandler.VerifyArchive)
+	}
 
 	// Time Travel endpoints
-	router.GET("/replay/user/:id/state", replayHandler.GetUserState)
-	router.GET("/replay/user/:id/state-at", replayHandler.GetUserStateAt)
-	router.GET("/replay/user/:id/history", replayHandler.GetUserHistory)
-	router.GET("/replay/user/:id/compare", replayHandler.CompareStates)
+	router.GET("/replay/:aggregate_type/:id/state", replayHandler.GetState)
+	router.GET("/replay/:aggregate_type/:id/state-at", replayHandler.GetStateAt)
+	router.GET("/replay/:aggregate_type/:id/history", replayHandler.GetHistory)
+	router.GET("/replay/:aggregate_type/:id/compare", replayHandler.CompareStates)
 
 	// HTTP Server port
 	httpPort := os.Getenv("PORT")
@@ -82,7 +292,7 @@ func main() {
 	// HTTP'den fark: Ayrı bir goroutine'de çalışır
 	go func() {
 		log.Printf("🚀 gRPC server starting on port %s", grpcPort)
-		if err := grpcserver.StartGRPCServer(":"+grpcPort, eventService, snapshotService); err != nil {
+		if err := grpcserver.StartGRPCServer(":"+grpcPort, eventService, snapshotService, appendRegistry); err != nil {
 			log.Fatalf("failed to start gRPC server: %v", err)
 		}
 	}()
@@ -93,3 +303,30 @@ func main() {
 		log.Fatalf("failed to start HTTP server: %v", err)
 	}
 }
+
+// postgresKeyRepository - data_keys tablosunu oluşturup Postgres key store'u döner
+func postgresKeyRepository(db *sql.DB) *repository.PostgresKeyRepository {
+	keyRepo := repository.NewPostgresKeyRepository(db)
+	if err := keyRepo.CreateTable(); err != nil {
+		log.Fatalf("Failed to create data key table: %v", err)
+	}
+	return keyRepo
+}
+
+// envInt - Tam sayı env değişkeni (boş ya da geçersizse fallback)
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(GetEnv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// envDuration - time.ParseDuration formatında env değişkeni (boş ya da geçersizse fallback)
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(GetEnv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
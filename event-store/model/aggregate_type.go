@@ -0,0 +1,43 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultAggregateType - Aggregate tipi taşımayan event'lerin tipi
+// Tip kolonundan önce yazılmış satırlar da bu tiptedir (kolon DEFAULT'u); tek tip "user" idi
+const DefaultAggregateType = "user"
+
+var streamNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+// AggregateTypeOf - Event tipinin ilk bölümü aggregate tipidir ("order.created" -> "order")
+// Noktasız event tiplerinden tip çıkarılamaz (boş döner)
+func AggregateTypeOf(eventType string) string {
+	aggregateType, _, found := strings.Cut(eventType, ".")
+	if !found {
+		return ""
+	}
+	return aggregateType
+}
+
+// NormalizeAggregateType - Boş tipi DefaultAggregateType'a çevirir
+func NormalizeAggregateType(aggregateType string) string {
+	if aggregateType == "" {
+		return DefaultAggregateType
+	}
+	return aggregateType
+}
+
+// NormalizeCategory - Kategorisi verilmemiş stream'in kategorisi aggregate tipidir
+func NormalizeCategory(category, aggregateType string) string {
+	if category == "" {
+		return NormalizeAggregateType(aggregateType)
+	}
+	return category
+}
+
+// ValidStreamName - Aggregate tipi/kategori adı geçerli mi? (küçük harf, rakam, '-', '_'; en fazla 64 karakter)
+func ValidStreamName(name string) bool {
+	return streamNamePattern.MatchString(name)
+}
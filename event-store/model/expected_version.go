@@ -0,0 +1,42 @@
+package model
+
+// ExpectedVersionKind - Append sırasında stream versiyonunun nasıl kontrol edileceği
+type ExpectedVersionKind int
+
+const (
+	// ExpectedVersionAny - Kontrol yok, event'ler her zaman sona eklenir
+	ExpectedVersionAny ExpectedVersionKind = iota
+	// ExpectedVersionNoStream - Stream henüz hiç event içermemeli
+	ExpectedVersionNoStream
+	// ExpectedVersionExact - Stream tam olarak Version'da olmalı
+	ExpectedVersionExact
+)
+
+// ExpectedVersion - Optimistic concurrency kontrolü için beklenen stream versiyonu
+type ExpectedVersion struct {
+	Kind    ExpectedVersionKind `json:"kind"`
+	Version uint32              `json:"version,omitempty"`
+}
+
+// Matches - Stream'in mevcut versiyonu beklentiyi karşılıyor mu?
+func (e ExpectedVersion) Matches(currentVersion uint32) bool {
+	switch e.Kind {
+	case ExpectedVersionNoStream:
+		return currentVersion == 0
+	case ExpectedVersionExact:
+		return currentVersion == e.Version
+	default:
+		return true
+	}
+}
+
+func (e ExpectedVersion) String() string {
+	switch e.Kind {
+	case ExpectedVersionNoStream:
+		return "no-stream"
+	case ExpectedVersionExact:
+		return "exact"
+	default:
+		return "any"
+	}
+}
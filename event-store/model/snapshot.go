@@ -5,8 +5,24 @@ import "time"
 // Snapshot - Aggregate'in belirli bir andaki state'ini tutar
 type Snapshot struct {
 	ID          string    `json:"id" ch:"id"`
+	TenantID    string    `json:"tenant_id" ch:"tenant_id"`
 	AggregateID string    `json:"aggregate_id" ch:"aggregate_id"`
 	Version     uint32    `json:"version" ch:"version"`
 	State       string    `json:"state" ch:"state"` // JSON olarak serialize edilmiş state
 	CreatedAt   time.Time `json:"created_at" ch:"created_at"`
+	// AggregateType ve StateVersion - State'i üreten reducer ve state şeklinin sürümü
+	// Reducer'ın şu anki sürümüyle uyuşmayan snapshot yüklenmez (tag'siz eski snapshot'lar: "" / 0)
+	AggregateType string `json:"aggregate_type" ch:"aggregate_type"`
+	StateVersion  uint32 `json:"state_version" ch:"state_version"`
+}
+
+// SnapshotInfo - State'i olmadan bir snapshot'ın özeti (listeleme)
+type SnapshotInfo struct {
+	ID            string `json:"id"`
+	Version       uint32 `json:"version"`
+	AggregateType string `json:"aggregate_type"`
+	StateVersion  uint32 `json:"state_version"`
+	// SizeBytes - Storage'daki state'in boyutu (şifreli ise şifreli hali)
+	SizeBytes uint64    `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
 }
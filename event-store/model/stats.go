@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Stats bucket'ları; hepsi UTC, hafta pazartesi başlar
+const (
+	StatsBucketHour  = "hour"
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+)
+
+// StatsQuery - Tenant'ın [From, To) aralığındaki istatistikleri, Bucket'lara bölünerek
+type StatsQuery struct {
+	TenantID string
+	From     time.Time
+	To       time.Time
+	Bucket   string
+	// EventTypes - Sadece bu event tipleri (boş = hepsi)
+	EventTypes []string
+	// AggregateType - Top aggregate'ler için tip filtresi (boş = hepsi)
+	AggregateType string
+	Limit         int
+}
+
+// StatsPoint - Bir bucket'taki sayı; Key event tipi ya da user agent ailesi gibi gruplama değeri
+type StatsPoint struct {
+	Bucket time.Time `json:"bucket"`
+	Key    string    `json:"key,omitempty"`
+	Count  uint64    `json:"count"`
+}
+
+// AggregateActivity - Aralıktaki event sayısına göre bir aggregate'in aktivitesi
+type AggregateActivity struct {
+	AggregateID   string    `json:"aggregate_id"`
+	AggregateType string    `json:"aggregate_type"`
+	Events        uint64    `json:"events"`
+	LastActiveDay time.Time `json:"last_active_day"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// StreamInfo - Bir aggregate stream'inin özeti (stream listeleme)
+type StreamInfo struct {
+	AggregateID   string    `json:"aggregate_id"`
+	AggregateType string    `json:"aggregate_type"`
+	Category      string    `json:"category"`
+	Version       uint32    `json:"version"`
+	EventCount    uint64    `json:"event_count"`
+	LastEventAt   time.Time `json:"last_event_at"`
+}
+
+// StreamListQuery - Tenant'ın stream'lerini aggregate ID sırasıyla listeler
+// Sayfalama AfterAggregateID ile yapılır (son dönen ID verilir); yeni stream'ler gelse de sayfalar kaymaz
+type StreamListQuery struct {
+	TenantID      string
+	Category      string
+	AggregateType string
+	// ActiveSince - Sadece son event'i bu andan sonra olan stream'ler (sıfır = filtre yok)
+	ActiveSince      time.Time
+	AfterAggregateID string
+	Limit            int
+}
+
+// CategoryInfo - Kategori başına stream ve event sayıları
+type CategoryInfo struct {
+	Category       string   `json:"category"`
+	AggregateTypes []string `json:"aggregate_types"`
+	Streams        uint64   `json:"streams"`
+	Events         uint64   `json:"events"`
+}
+
+// StreamTail - Stream'in bir version'dan sonraki kısmının özeti (snapshot politikası)
+// Bytes storage'daki payload boyutudur (şifreli alanlar şifreli haliyle sayılır)
+type StreamTail struct {
+	Events  uint64
+	Bytes   uint64
+	FirstAt time.Time
+}
@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// ReadDirection - Stream'in hangi sırayla okunacağı
+type ReadDirection int
+
+const (
+	ReadForward  ReadDirection = iota // version ASC
+	ReadBackward                      // version DESC
+)
+
+// StreamQuery - Tek bir aggregate stream'ini okuma kriterleri
+// Tüm filtreler sorguya (storage'a) iletilir; satır sayısında üst sınır yoktur
+type StreamQuery struct {
+	// TenantID - Stream'in ait olduğu tenant (boş = DefaultTenantID); başka tenant'ın stream'i boş görünür
+	TenantID    string
+	AggregateID string
+	// FromVersion / ToVersion - Dahil version aralığı (0 = sınır yok); yön sadece sırayı değiştirir
+	FromVersion uint32
+	ToVersion   uint32
+	// EventTypes - Boş değilse sadece bu tipteki event'ler
+	EventTypes []string
+	// EndTime - Sıfır değilse sadece bu zamana kadar (dahil) olan event'ler (time travel)
+	EndTime   time.Time
+	Direction ReadDirection
+	// MaxCount - 0 = sınırsız
+	MaxCount int
+}
+
+// Matches - Event bu sorgunun filtrelerine uyuyor mu? (sorgu dili olmayan backend'ler için)
+func (q StreamQuery) Matches(event *Event) bool {
+	if event.AggregateID != q.AggregateID || NormalizeTenantID(event.TenantID) != NormalizeTenantID(q.TenantID) {
+		return false
+	}
+	if q.FromVersion > 0 && event.Version < q.FromVersion {
+		return false
+	}
+	if q.ToVersion > 0 && event.Version > q.ToVersion {
+		return false
+	}
+	if !q.EndTime.IsZero() && event.Timestamp.After(q.EndTime) {
+		return false
+	}
+	if len(q.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range q.EventTypes {
+		if event.EventType == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package model
+
+import "regexp"
+
+// DefaultTenantID - Tenant bilgisi taşımayan istek/event'lerin tenant'ı
+// Multi-tenancy'den önce yazılmış satırlar da bu tenant'a aittir (kolon DEFAULT'u)
+const DefaultTenantID = "default"
+
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// NormalizeTenantID - Boş tenant'ı DefaultTenantID'ye çevirir
+// Repository'ler her okumada bunu kullanır; tenant filtresi hiçbir zaman atlanmaz
+func NormalizeTenantID(tenantID string) string {
+	if tenantID == "" {
+		return DefaultTenantID
+	}
+	return tenantID
+}
+
+// ValidTenantID - Header/metadata'dan gelen tenant ID'si geçerli mi? (harf, rakam, '-', '_'; en fazla 64 karakter)
+func ValidTenantID(tenantID string) bool {
+	return tenantIDPattern.MatchString(tenantID)
+}
@@ -7,13 +7,16 @@ import (
 )
 
 // UserAggregate - User'ın anlık durumunu temsil eder
+// auth-service'in domain.UserAggregate'i ile aynı state'i kurar; gRPC GetAggregateWithSnapshot
+// bu JSON'u auth-service'e döner
 type UserAggregate struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Version   uint32    `json:"version"`
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"password_hash"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Version      uint32    `json:"version"`
 
 	// Event history (debugging için)
 	EventCount int `json:"event_count"`
@@ -26,69 +29,74 @@ func NewUserAggregate() *UserAggregate {
 	}
 }
 
+// StreamVersion - Uygulanan son event'in version'ı
+func (u *UserAggregate) StreamVersion() uint32 {
+	return u.Version
+}
+
 // ApplyEvent - Bir event'i state'e uygular
+// Bilinmeyen event tipleri hata döner (reducer registry de bunları önceden reddeder)
 func (u *UserAggregate) ApplyEvent(event *Event) error {
 	var eventData map[string]interface{}
 	if err := json.Unmarshal([]byte(event.Payload), &eventData); err != nil {
 		return fmt.Errorf("failed to unmarshal event payload: %w", err)
 	}
 
-	u.Version = event.Version
-	u.EventCount++
-
 	// Event type'a göre state'i güncelle
 	switch event.EventType {
 	case "user.created":
-		return u.applyUserCreated(eventData, event.Timestamp)
-	case "user.updated":
-		return u.applyUserUpdated(eventData, event.Timestamp)
-	case "user.deleted":
-		return u.applyUserDeleted(eventData, event.Timestamp)
+		u.applyUserCreated(eventData, event.Timestamp)
+	case "user.password.changed":
+		u.applyPasswordChanged(eventData, event.Timestamp)
 	case "user.email.changed":
-		return u.applyEmailChanged(eventData, event.Timestamp)
+		u.applyEmailChanged(eventData, event.Timestamp)
+	case "user.deactivated":
+		u.applyDeactivated(event.Timestamp)
+	case "user.login.recorded":
+		// Login kaydı state'i değiştirmez, sadece stream'i ilerletir
 	default:
-		// Unknown event - skip
-		return nil
+		return fmt.Errorf("user aggregate cannot apply %s", event.EventType)
 	}
+
+	u.Version = event.Version
+	u.EventCount++
+	return nil
 }
 
-func (u *UserAggregate) applyUserCreated(data map[string]interface{}, timestamp time.Time) error {
+func (u *UserAggregate) applyUserCreated(data map[string]interface{}, timestamp time.Time) {
+	// Eski şekilli event'ler ("id" alanı) okunurken upcast edildiği için sadece aggregate_id okunur
 	if id, ok := data["aggregate_id"].(string); ok {
 		u.ID = id
-	} else if id, ok := data["id"].(string); ok {
-		u.ID = id
 	}
 	if email, ok := data["email"].(string); ok {
 		u.Email = email
 	}
+	if hash, ok := data["password_hash"].(string); ok {
+		u.PasswordHash = hash
+	}
 
 	u.Status = "active"
 	u.CreatedAt = timestamp
 	u.UpdatedAt = timestamp
-
-	return nil
 }
 
-func (u *UserAggregate) applyUserUpdated(data map[string]interface{}, timestamp time.Time) error {
-	if email, ok := data["email"].(string); ok && email != "" {
-		u.Email = email
+func (u *UserAggregate) applyPasswordChanged(data map[string]interface{}, timestamp time.Time) {
+	if hash, ok := data["new_password_hash"].(string); ok {
+		u.PasswordHash = hash
 	}
 
 	u.UpdatedAt = timestamp
-	return nil
 }
 
-func (u *UserAggregate) applyUserDeleted(data map[string]interface{}, timestamp time.Time) error {
-	u.Status = "deleted"
-	u.UpdatedAt = timestamp
-	return nil
-}
-
-func (u *UserAggregate) applyEmailChanged(data map[string]interface{}, timestamp time.Time) error {
+func (u *UserAggregate) applyEmailChanged(data map[string]interface{}, timestamp time.Time) {
 	if newEmail, ok := data["new_email"].(string); ok {
 		u.Email = newEmail
 	}
 
 	u.UpdatedAt = timestamp
-	return nil
+}
+
+func (u *UserAggregate) applyDeactivated(timestamp time.Time) {
+	u.Status = "deactivated"
+	u.UpdatedAt = timestamp
 }
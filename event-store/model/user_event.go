@@ -9,13 +9,105 @@ type Event struct {
 	Payload     string    `json:"payload"`
 	Timestamp   time.Time `json:"timestamp"`
 	Version     uint32    `json:"version"`
+	// Position - Tüm stream'ler genelinde append sırasında atanan, boşluksuz artan sıra numarası (1'den başlar)
+	Position uint64 `json:"position"`
+	// SchemaVersion - Payload'un şema versiyonu; okunurken upcaster'lar güncel versiyona çevirir
+	// 0 = schema registry'den önce yazılmış (versiyonsuz) event
+	SchemaVersion uint16 `json:"schema_version"`
+	// Metadata - Event'i hangi istek, kullanıcı ve servisin ürettiği
+	Metadata EventMetadata `json:"metadata"`
+	// TenantID - Event'in ait olduğu tenant; bir aggregate'in tüm event'leri aynı tenant'tadır
+	TenantID string `json:"tenant_id"`
+	// AggregateType - Stream'in aggregate tipi (user, order ...); bir stream'in tüm event'leri aynı tiptedir
+	AggregateType string `json:"aggregate_type"`
+	// Category - Stream kategorisi; catalog'dan gelir, tanımlı değilse aggregate tipi
+	Category string `json:"category"`
+}
+
+// EventMetadata - Producer'ların envelope'ta gönderdiği bağlam bilgisi (ayrı kolonlarda saklanır)
+type EventMetadata struct {
+	// CorrelationID - Aynı kullanıcı isteğinden doğan tüm event'lerde ortak (X-Correlation-ID)
+	CorrelationID string `json:"correlation_id"`
+	// CausationID - Event'e doğrudan sebep olan istek/command ya da event'in ID'si
+	CausationID string `json:"causation_id"`
+	// ActorID - İşlemi yapan kullanıcı (sistem işlemlerinde boş)
+	ActorID string `json:"actor_id"`
+	// SourceService - Event'i üreten servis (auth-service, query-service ...)
+	SourceService string `json:"source_service"`
 }
 
 type EventFilter struct {
+	// TenantID - Sadece bu tenant'ın event'leri (boş = DefaultTenantID, filtre hiçbir zaman atlanmaz)
+	TenantID    string    `json:"tenant_id,omitempty"`
 	EventType   string    `json:"event_type,omitempty"`
 	AggregateID string    `json:"aggregate_id,omitempty"`
 	StartTime   time.Time `json:"start_time,omitempty"`
 	EndTime     time.Time `json:"end_time,omitempty"`
 	Limit       int       `json:"limit,omitempty"`
 	Offset      int       `json:"offset,omitempty"`
+	// FromPosition - Sadece position >= FromPosition olan event'ler (0 = filtre yok)
+	// Verildiğinde sonuçlar position sırasıyla döner; kaldığı yerden devam etmek için son position + 1 verilir
+	FromPosition uint64 `json:"from_position,omitempty"`
+
+	// Metadata filtreleri - boş olanlar uygulanmaz
+	CorrelationID string `json:"correlation_id,omitempty"`
+	CausationID   string `json:"causation_id,omitempty"`
+	ActorID       string `json:"actor_id,omitempty"`
+	SourceService string `json:"source_service,omitempty"`
+	// SchemaVersion - Sadece bu şema versiyonuyla yazılmış event'ler (nil = filtre yok)
+	// Kayıtlı versiyona göre filtreler; dönen event'ler okunurken güncel versiyona upcast edilir
+	SchemaVersion *uint16 `json:"schema_version,omitempty"`
+
+	// Stream filtreleri - boş olanlar uygulanmaz
+	AggregateType string `json:"aggregate_type,omitempty"`
+	Category      string `json:"category,omitempty"`
+}
+
+// Matches - Event filtrenin tüm kriterlerine uyuyor mu? (Limit/Offset hariç)
+func (f EventFilter) Matches(event *Event) bool {
+	if NormalizeTenantID(event.TenantID) != NormalizeTenantID(f.TenantID) {
+		return false
+	}
+	if f.EventType != "" && event.EventType != f.EventType {
+		return false
+	}
+	if f.AggregateID != "" && event.AggregateID != f.AggregateID {
+		return false
+	}
+	if f.AggregateType != "" && NormalizeAggregateType(event.AggregateType) != f.AggregateType {
+		return false
+	}
+	if f.Category != "" && NormalizeCategory(event.Category, event.AggregateType) != f.Category {
+		return false
+	}
+	if !f.StartTime.IsZero() && event.Timestamp.Before(f.StartTime) {
+		return false
+	}
+	if !f.EndTime.IsZero() && event.Timestamp.After(f.EndTime) {
+		return false
+	}
+	if event.Position < f.FromPosition {
+		return false
+	}
+	return f.MatchesMetadata(event)
+}
+
+// MatchesMetadata - Event metadata/şema filtrelerine uyuyor mu? (sorgu dili olmayan backend'ler için)
+func (f EventFilter) MatchesMetadata(event *Event) bool {
+	if f.CorrelationID != "" && event.Metadata.CorrelationID != f.CorrelationID {
+		return false
+	}
+	if f.CausationID != "" && event.Metadata.CausationID != f.CausationID {
+		return false
+	}
+	if f.ActorID != "" && event.Metadata.ActorID != f.ActorID {
+		return false
+	}
+	if f.SourceService != "" && event.Metadata.SourceService != f.SourceService {
+		return false
+	}
+	if f.SchemaVersion != nil && event.SchemaVersion != *f.SchemaVersion {
+		return false
+	}
+	return true
 }
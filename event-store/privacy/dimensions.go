@@ -0,0 +1,57 @@
+package privacy
+
+import "strings"
+
+// Dimension - Şifrelenen bir alandan yazarken türetilen, kişiyi tanımlamayan düz alan
+// Şifreli değerler gruplanamadığı için istatistik view'ları (login'lerin user agent dağılımı) bunları okur
+type Dimension struct {
+	// Field - Kaynak PII alanı, As - payload'a düz yazılan türetilmiş alan
+	Field  string
+	As     string
+	Derive func(value string) string
+}
+
+// DefaultDimensions - Bilinen event tiplerinin türetilmiş alanları
+func DefaultDimensions() map[string][]Dimension {
+	return map[string][]Dimension{
+		"user.login.recorded": {{Field: "user_agent", As: "user_agent_family", Derive: UserAgentFamily}},
+	}
+}
+
+// userAgentFamilies - Sırası önemli: Edge ve Opera "Chrome/", Chrome da "Safari/" içerir
+var userAgentFamilies = []struct {
+	token  string
+	family string
+}{
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"safari/", "Safari"},
+	{"curl/", "curl"},
+	{"postmanruntime/", "Postman"},
+	{"go-http-client/", "Go"},
+	{"python", "Python"},
+	{"okhttp/", "OkHttp"},
+	{"bot", "Bot"},
+	{"spider", "Bot"},
+	{"crawl", "Bot"},
+}
+
+// UserAgentFamily - User agent'ın tarayıcı/istemci ailesi ("Chrome", "curl", ...); tanınmayanlar "other"
+// Sürüm ve işletim sistemi atılır, böylece değer kullanıcıyı ayırt etmez
+func UserAgentFamily(userAgent string) string {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return "unknown"
+	}
+	for _, f := range userAgentFamilies {
+		if strings.Contains(ua, f.token) {
+			return f.family
+		}
+	}
+	return "other"
+}
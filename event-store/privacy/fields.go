@@ -0,0 +1,15 @@
+package privacy
+
+// Fields - Event tipine göre şifrelenen (PII) payload alanları
+// Alanlar payload'un üst seviyesindeki string değerler olmalıdır
+type Fields map[string][]string
+
+// DefaultFields - Bilinen event tiplerinin PII alanları (schema/schemas ile uyumlu)
+func DefaultFields() Fields {
+	return Fields{
+		"user.created":          {"email", "password_hash"},
+		"user.email.changed":    {"old_email", "new_email"},
+		"user.password.changed": {"new_password_hash"},
+		"user.login.recorded":   {"ip_address", "user_agent"},
+	}
+}
@@ -0,0 +1,74 @@
+package privacy
+
+import (
+	"errors"
+
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// keyring - Tek bir okuma/yazma çağrısı boyunca aggregate anahtarlarını önbellekler
+// Aynı aggregate'in yüzlerce event'i için key store'a tekrar tekrar gidilmez
+type keyring struct {
+	keys repository.KeyStore
+	// nil anahtar + forgotten: anahtar yok edilmiş; nil anahtar tek başına: henüz oluşturulmamış
+	cache     map[string][]byte
+	forgotten map[string]bool
+}
+
+func newKeyring(keys repository.KeyStore) *keyring {
+	return &keyring{
+		keys:      keys,
+		cache:     make(map[string][]byte),
+		forgotten: make(map[string]bool),
+	}
+}
+
+// readKey - Okuma için anahtar; anahtar hiç yoksa nil döner
+func (k *keyring) readKey(aggregateID string) (key []byte, forgotten bool, err error) {
+	if k.forgotten[aggregateID] {
+		return nil, true, nil
+	}
+	if key, ok := k.cache[aggregateID]; ok {
+		return key, false, nil
+	}
+
+	key, err = k.keys.GetKey(aggregateID)
+	switch {
+	case errors.Is(err, repository.ErrKeyDestroyed):
+		k.forgotten[aggregateID] = true
+		return nil, true, nil
+	case errors.Is(err, repository.ErrKeyNotFound):
+		k.cache[aggregateID] = nil
+		return nil, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+
+	k.cache[aggregateID] = key
+	return key, false, nil
+}
+
+// writeKey - Yazma için anahtar; yoksa oluşturulur
+func (k *keyring) writeKey(aggregateID string) (key []byte, forgotten bool, err error) {
+	key, forgotten, err = k.readKey(aggregateID)
+	if err != nil || forgotten || key != nil {
+		return key, forgotten, err
+	}
+
+	newKey, err := NewKey()
+	if err != nil {
+		return nil, false, err
+	}
+
+	key, err = k.keys.GetOrCreateKey(aggregateID, newKey)
+	if errors.Is(err, repository.ErrKeyDestroyed) {
+		k.forgotten[aggregateID] = true
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	k.cache[aggregateID] = key
+	return key, false, nil
+}
@@ -0,0 +1,82 @@
+package privacy
+
+import (
+	"fmt"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// SnapshotStore - Snapshot state'ini aggregate'in veri anahtarıyla şifreleyen SnapshotStore decorator'ı
+// State PII içerdiği (email vb.) için bütün olarak şifrelenir. Anahtarı yok edilmiş aggregate'in
+// snapshot'ı bulunamamış gibi davranılır; servis state'i event'lerden (maskelenmiş) yeniden kurar
+type SnapshotStore struct {
+	repository.SnapshotStore
+	keys repository.KeyStore
+}
+
+func NewSnapshotStore(store repository.SnapshotStore, keys repository.KeyStore) *SnapshotStore {
+	return &SnapshotStore{SnapshotStore: store, keys: keys}
+}
+
+func (s *SnapshotStore) SaveSnapshot(snapshot *model.Snapshot) error {
+	key, forgotten, err := newKeyring(s.keys).writeKey(snapshot.AggregateID)
+	if err != nil {
+		return fmt.Errorf("failed to get data key of %s: %w", snapshot.AggregateID, err)
+	}
+	if forgotten {
+		// Maskelenmiş state'in şifrelenmesine gerek yok
+		return s.SnapshotStore.SaveSnapshot(snapshot)
+	}
+
+	state, err := Encrypt(key, snapshot.AggregateID, snapshot.State)
+	if err != nil {
+		return err
+	}
+
+	encrypted := *snapshot
+	encrypted.State = state
+	return s.SnapshotStore.SaveSnapshot(&encrypted)
+}
+
+func (s *SnapshotStore) GetLatestSnapshot(tenantID, aggregateID string) (*model.Snapshot, error) {
+	snapshot, err := s.SnapshotStore.GetLatestSnapshot(tenantID, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(snapshot)
+}
+
+func (s *SnapshotStore) GetSnapshotAtVersion(tenantID, aggregateID string, version uint32) (*model.Snapshot, error) {
+	snapshot, err := s.SnapshotStore.GetSnapshotAtVersion(tenantID, aggregateID, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(snapshot)
+}
+
+func (s *SnapshotStore) decrypt(snapshot *model.Snapshot) (*model.Snapshot, error) {
+	key, forgotten, err := newKeyring(s.keys).readKey(snapshot.AggregateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data key of %s: %w", snapshot.AggregateID, err)
+	}
+	if !IsEncrypted(snapshot.State) {
+		if forgotten {
+			// Unutulmadan önce düz yazılmış snapshot: içindeki PII kullanılmamalı
+			return nil, fmt.Errorf("snapshot not found for aggregate %s: %w", snapshot.AggregateID, repository.ErrKeyDestroyed)
+		}
+		return snapshot, nil
+	}
+	if key == nil {
+		return nil, fmt.Errorf("snapshot not found for aggregate %s: %w", snapshot.AggregateID, repository.ErrKeyNotFound)
+	}
+
+	state, err := Decrypt(key, snapshot.AggregateID, snapshot.State)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", snapshot.ID, err)
+	}
+
+	decrypted := *snapshot
+	decrypted.State = state
+	return &decrypted, nil
+}
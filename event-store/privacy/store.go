@@ -0,0 +1,256 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// Store - PII alanlarını aggregate başına anahtarla şifreleyen EventStore decorator'ı (crypto-shredding)
+// Yazarken event'in bir kopyası şifrelenir (servisin elindeki event ve canlı yayın düz kalır),
+// okurken çözülür. Anahtarı yok edilmiş aggregate'lerin PII alanları Redacted olarak döner;
+// şifreleme açılmadan önce düz yazılmış satırlar da okurken maskelenir
+type Store struct {
+	repository.EventStore
+	keys   repository.KeyStore
+	fields Fields
+	// dimensions - Şifrelenmeden önce PII alanlarından türetilip düz yazılan alanlar
+	dimensions map[string][]Dimension
+}
+
+func NewStore(store repository.EventStore, keys repository.KeyStore, fields Fields) *Store {
+	return &Store{EventStore: store, keys: keys, fields: fields, dimensions: DefaultDimensions()}
+}
+
+// Forget - Aggregate'in anahtarını yok eder; şifreli alanları bir daha çözülemez
+func (s *Store) Forget(aggregateID string) error {
+	if err := s.keys.DestroyKey(aggregateID); err != nil {
+		return fmt.Errorf("failed to destroy data key of %s: %w", aggregateID, err)
+	}
+	return nil
+}
+
+func (s *Store) SaveEvent(event *model.Event) error {
+	encrypted, err := s.encrypt(event, newKeyring(s.keys))
+	if err != nil {
+		return err
+	}
+	return s.EventStore.SaveEvent(encrypted)
+}
+
+func (s *Store) SaveEvents(events []*model.Event) error {
+	ring := newKeyring(s.keys)
+	encrypted := make([]*model.Event, len(events))
+	for i, event := range events {
+		e, err := s.encrypt(event, ring)
+		if err != nil {
+			return err
+		}
+		encrypted[i] = e
+	}
+	return s.EventStore.SaveEvents(encrypted)
+}
+
+func (s *Store) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
+	events, err := s.EventStore.GetEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decryptAll(events, newKeyring(s.keys)); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Store) GetEventsAfterVersion(tenantID, aggregateID string, afterVersion uint32) ([]*model.Event, error) {
+	events, err := s.EventStore.GetEventsAfterVersion(tenantID, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decryptAll(events, newKeyring(s.keys)); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Store) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	ring := newKeyring(s.keys)
+	return s.EventStore.ReadStream(ctx, query, func(event *model.Event) error {
+		decrypted, err := s.decrypt(event, ring)
+		if err != nil {
+			return err
+		}
+		return fn(decrypted)
+	})
+}
+
+// encrypt - PII alanları şifrelenmiş bir kopya döner; unutulmuş aggregate'e gelen yeni
+// event'lerin PII alanları hiç yazılmaz (Redacted)
+func (s *Store) encrypt(event *model.Event, ring *keyring) (*model.Event, error) {
+	event, err := s.derive(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transform(event, func(value string) (string, bool, error) {
+		if IsEncrypted(value) || value == Redacted {
+			// Zaten şifreli gelen değerler ve export'taki maskelenmiş (anahtarı yok edilmiş) değerler
+			return value, false, nil
+		}
+
+		key, forgotten, err := ring.writeKey(event.AggregateID)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get data key of %s: %w", event.AggregateID, err)
+		}
+		if forgotten {
+			return Redacted, true, nil
+		}
+
+		encrypted, err := Encrypt(key, event.AggregateID, value)
+		if err != nil {
+			return "", false, err
+		}
+		return encrypted, true, nil
+	})
+}
+
+// decrypt - PII alanları çözülmüş (ya da maskelenmiş) bir kopya döner
+func (s *Store) decrypt(event *model.Event, ring *keyring) (*model.Event, error) {
+	return s.transform(event, func(value string) (string, bool, error) {
+		key, forgotten, err := ring.readKey(event.AggregateID)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get data key of %s: %w", event.AggregateID, err)
+		}
+		if forgotten {
+			return Redacted, value != Redacted, nil
+		}
+		if !IsEncrypted(value) {
+			return value, false, nil
+		}
+		if key == nil {
+			// Şifreli değer var ama anahtar kaydı yok: anahtar kaybolmuş, veri kurtarılamaz
+			return Redacted, true, nil
+		}
+
+		plaintext, err := Decrypt(key, event.AggregateID, value)
+		if err != nil {
+			return "", false, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		return plaintext, true, nil
+	})
+}
+
+// derive - Event tipinin türetilmiş alanlarını düz PII değerlerinden hesaplayıp payload'a ekler
+// Alan zaten varsa ya da kaynak değer şifreli gelmişse (import) dokunulmaz
+func (s *Store) derive(event *model.Event) (*model.Event, error) {
+	dimensions := s.dimensions[event.EventType]
+	if len(dimensions) == 0 {
+		return event, nil
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload of event %s: %w", event.ID, err)
+	}
+
+	changed := false
+	for _, dimension := range dimensions {
+		if _, ok := payload[dimension.As]; ok {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(payload[dimension.Field], &value); err != nil || IsEncrypted(value) || value == Redacted {
+			continue
+		}
+
+		encoded, err := json.Marshal(dimension.Derive(value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal field %s of event %s: %w", dimension.As, event.ID, err)
+		}
+		payload[dimension.As] = encoded
+		changed = true
+	}
+
+	if !changed {
+		return event, nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload of event %s: %w", event.ID, err)
+	}
+
+	derived := *event
+	derived.Payload = string(data)
+	return &derived, nil
+}
+
+func (s *Store) decryptAll(events []*model.Event, ring *keyring) error {
+	for i, event := range events {
+		decrypted, err := s.decrypt(event, ring)
+		if err != nil {
+			return err
+		}
+		events[i] = decrypted
+	}
+	return nil
+}
+
+// transform - Event tipinin PII alanlarına fn'i uygular
+// Hiçbir alan değişmezse aynı pointer, aksi halde payload'u güncellenmiş kopya döner;
+// diğer alanlar json.RawMessage olarak olduğu gibi korunur
+func (s *Store) transform(event *model.Event, fn func(value string) (string, bool, error)) (*model.Event, error) {
+	fields := s.fields[event.EventType]
+	if len(fields) == 0 {
+		return event, nil
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload of event %s: %w", event.ID, err)
+	}
+
+	changed := false
+	for _, field := range fields {
+		raw, ok := payload[field]
+		if !ok {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			// String olmayan (null vb.) değerlere dokunulmaz
+			continue
+		}
+
+		next, ok, err := fn(value)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		encoded, err := json.Marshal(next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal field %s of event %s: %w", field, event.ID, err)
+		}
+		payload[field] = encoded
+		changed = true
+	}
+
+	if !changed {
+		return event, nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload of event %s: %w", event.ID, err)
+	}
+
+	transformed := *event
+	transformed.Payload = string(data)
+	return &transformed, nil
+}
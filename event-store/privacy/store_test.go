@@ -0,0 +1,198 @@
+package privacy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/catalog"
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+	"github.com/eyupaydin41/event-store/service"
+)
+
+func userCreated(aggregateID, email string) *model.Event {
+	return &model.Event{
+		ID:          aggregateID + "-created",
+		EventType:   "user.created",
+		AggregateID: aggregateID,
+		Payload:     `{"aggregate_id":"` + aggregateID + `","email":"` + email + `","password_hash":"hash"}`,
+		Timestamp:   time.Now(),
+		Version:     1,
+		Position:    1,
+	}
+}
+
+func payloadField(t *testing.T, event *model.Event, field string) string {
+	t.Helper()
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		t.Fatal(err)
+	}
+	value, _ := payload[field].(string)
+	return value
+}
+
+func TestPIIIsEncryptedAtRestAndDecryptedOnRead(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	store := NewStore(repo, repository.NewMemoryKeyRepository(), DefaultFields())
+
+	event := userCreated("user-1", "a@example.com")
+	if err := store.SaveEvents([]*model.Event{event}); err != nil {
+		t.Fatalf("SaveEvents: %v", err)
+	}
+	if payloadField(t, event, "email") != "a@example.com" {
+		t.Error("caller's event was modified")
+	}
+
+	stored, err := repo.GetEvents(model.EventFilter{AggregateID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stored[0].Payload, "a@example.com") || !IsEncrypted(payloadField(t, stored[0], "email")) {
+		t.Errorf("expected email to be encrypted at rest, got %s", stored[0].Payload)
+	}
+	if payloadField(t, stored[0], "aggregate_id") != "user-1" {
+		t.Errorf("non PII fields must stay readable, got %s", stored[0].Payload)
+	}
+
+	read, err := store.GetEvents(model.EventFilter{AggregateID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payloadField(t, read[0], "email") != "a@example.com" || payloadField(t, read[0], "password_hash") != "hash" {
+		t.Errorf("expected decrypted payload, got %s", read[0].Payload)
+	}
+}
+
+func TestForgottenUserIsRedactedInReplayAndSnapshots(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	keys := repository.NewMemoryKeyRepository()
+	store := NewStore(repo, keys, DefaultFields())
+	snapshots := NewSnapshotStore(repository.NewMemorySnapshotRepository(), keys)
+
+	// Şifreleme açılmadan önce düz yazılmış event ve şifreli yazılan event
+	if err := repo.SaveEvent(userCreated("user-1", "old@example.com")); err != nil {
+		t.Fatal(err)
+	}
+	changed := &model.Event{
+		ID:          "user-1-email",
+		EventType:   "user.email.changed",
+		AggregateID: "user-1",
+		Payload:     `{"aggregate_id":"user-1","old_email":"old@example.com","new_email":"new@example.com"}`,
+		Timestamp:   time.Now(),
+		Version:     2,
+		Position:    2,
+	}
+	if err := store.SaveEvent(changed); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotService := service.NewSnapshotService(snapshots, store, catalog.NewCatalog(), reducer.NewDefaultRegistry())
+	if err := snapshotService.CreateSnapshot(model.DefaultTenantID, "user-1"); err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+	loaded, err := snapshotService.LoadAggregateWithSnapshot(model.DefaultTenantID, "user-1")
+	if err != nil || loaded.State.(*model.UserAggregate).Email != "new@example.com" {
+		t.Fatalf("expected decrypted snapshot state, got %+v (%v)", loaded, err)
+	}
+
+	privacyService := service.NewPrivacyService(keys, snapshots, store)
+	if err := privacyService.ForgetUser(model.DefaultTenantID, "user-1"); err != nil {
+		t.Fatalf("ForgetUser: %v", err)
+	}
+	if has, _ := snapshots.HasSnapshot(model.DefaultTenantID, "user-1"); has {
+		t.Error("expected snapshots to be deleted")
+	}
+
+	events, err := store.GetEvents(model.EventFilter{AggregateID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		for _, field := range []string{"email", "old_email", "new_email"} {
+			if value := payloadField(t, event, field); value != "" && value != Redacted {
+				t.Errorf("expected %s of %s to be redacted, got %q", field, event.ID, value)
+			}
+		}
+	}
+
+	state, err := service.NewReplayService(store, reducer.NewDefaultRegistry()).ReplayState(model.DefaultTenantID, "user", "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed := state.(*model.UserAggregate); replayed.Email != Redacted || replayed.Version != 2 {
+		t.Errorf("expected redacted replay, got %+v", replayed)
+	}
+
+	// Unutulan kullanıcıya gelen yeni event'lerin PII'ı hiç yazılmaz
+	late := &model.Event{
+		ID:          "user-1-email-2",
+		EventType:   "user.email.changed",
+		AggregateID: "user-1",
+		Payload:     `{"aggregate_id":"user-1","old_email":"x","new_email":"late@example.com"}`,
+		Timestamp:   time.Now(),
+		Version:     3,
+		Position:    3,
+	}
+	if err := store.SaveEvent(late); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := repo.GetEvents(model.EventFilter{AggregateID: "user-1"})
+	if strings.Contains(stored[2].Payload, "late@example.com") {
+		t.Errorf("expected PII of forgotten user not to be stored, got %s", stored[2].Payload)
+	}
+
+	if err := snapshotService.CreateSnapshot(model.DefaultTenantID, "user-1"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = snapshotService.LoadAggregateWithSnapshot(model.DefaultTenantID, "user-1")
+	if err != nil || loaded.State.(*model.UserAggregate).Email != Redacted {
+		t.Errorf("expected redacted snapshot state, got %+v (%v)", loaded, err)
+	}
+
+	if forgotten, _ := privacyService.IsForgotten(model.DefaultTenantID, "user-1"); !forgotten {
+		t.Error("expected user-1 to be reported as forgotten")
+	}
+}
+
+func TestLoginKeepsUserAgentFamilyInPlainText(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	store := NewStore(repo, repository.NewMemoryKeyRepository(), DefaultFields())
+
+	userAgent := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"
+	login := &model.Event{
+		ID:          "user-1-login",
+		EventType:   "user.login.recorded",
+		AggregateID: "user-1",
+		Payload:     `{"aggregate_id":"user-1","ip_address":"10.0.0.1","user_agent":"` + userAgent + `"}`,
+		Timestamp:   time.Now(),
+		Version:     2,
+		Position:    2,
+	}
+	if err := store.SaveEvent(login); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+
+	stored, err := repo.GetEvents(model.EventFilter{AggregateID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(payloadField(t, stored[0], "user_agent")) || payloadField(t, stored[0], "user_agent_family") != "Chrome" {
+		t.Errorf("expected encrypted user_agent with plain family, got %s", stored[0].Payload)
+	}
+
+	for userAgent, family := range map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0) Chrome/124.0 Safari/537.36 Edg/124.0": "Edge",
+		"Mozilla/5.0 (iPhone) Version/17.0 Mobile/15E148 Safari/604.1":       "Safari",
+		"curl/8.4.0": "curl",
+		"":           "unknown",
+		"my-client":  "other",
+	} {
+		if got := UserAgentFamily(userAgent); got != family {
+			t.Errorf("UserAgentFamily(%q) = %q, want %q", userAgent, got, family)
+		}
+	}
+}
@@ -26,6 +26,103 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Append sırasında stream versiyonunun nasıl kontrol edileceği
+type ExpectedVersionKind int32
+
+const (
+	ExpectedVersionKind_EXPECTED_VERSION_ANY       ExpectedVersionKind = 0 // Kontrol yok, her zaman sona ekle
+	ExpectedVersionKind_EXPECTED_VERSION_NO_STREAM ExpectedVersionKind = 1 // Stream henüz hiç event içermemeli
+	ExpectedVersionKind_EXPECTED_VERSION_EXACT     ExpectedVersionKind = 2 // Stream tam olarak `version`'da olmalı
+)
+
+// Enum value maps for ExpectedVersionKind.
+var (
+	ExpectedVersionKind_name = map[int32]string{
+		0: "EXPECTED_VERSION_ANY",
+		1: "EXPECTED_VERSION_NO_STREAM",
+		2: "EXPECTED_VERSION_EXACT",
+	}
+	ExpectedVersionKind_value = map[string]int32{
+		"EXPECTED_VERSION_ANY":       0,
+		"EXPECTED_VERSION_NO_STREAM": 1,
+		"EXPECTED_VERSION_EXACT":     2,
+	}
+)
+
+func (x ExpectedVersionKind) Enum() *ExpectedVersionKind {
+	p := new(ExpectedVersionKind)
+	*p = x
+	return p
+}
+
+func (x ExpectedVersionKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExpectedVersionKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_event_store_proto_enumTypes[0].Descriptor()
+}
+
+func (ExpectedVersionKind) Type() protoreflect.EnumType {
+	return &file_proto_event_store_proto_enumTypes[0]
+}
+
+func (x ExpectedVersionKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExpectedVersionKind.Descriptor instead.
+func (ExpectedVersionKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{0}
+}
+
+// Stream okuma yönü
+type ReadDirection int32
+
+const (
+	ReadDirection_READ_DIRECTION_FORWARD  ReadDirection = 0 // version ASC
+	ReadDirection_READ_DIRECTION_BACKWARD ReadDirection = 1 // version DESC
+)
+
+// Enum value maps for ReadDirection.
+var (
+	ReadDirection_name = map[int32]string{
+		0: "READ_DIRECTION_FORWARD",
+		1: "READ_DIRECTION_BACKWARD",
+	}
+	ReadDirection_value = map[string]int32{
+		"READ_DIRECTION_FORWARD":  0,
+		"READ_DIRECTION_BACKWARD": 1,
+	}
+)
+
+func (x ReadDirection) Enum() *ReadDirection {
+	p := new(ReadDirection)
+	*p = x
+	return p
+}
+
+func (x ReadDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReadDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_event_store_proto_enumTypes[1].Descriptor()
+}
+
+func (ReadDirection) Type() protoreflect.EnumType {
+	return &file_proto_event_store_proto_enumTypes[1]
+}
+
+func (x ReadDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReadDirection.Descriptor instead.
+func (ReadDirection) EnumDescriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{1}
+}
+
 // HTTP'de: type GetEventsRequest struct { AggregateID string }
 type GetAggregateEventsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -124,7 +221,13 @@ type Event struct {
 	AggregateId   string                 `protobuf:"bytes,3,opt,name=aggregate_id,json=aggregateId,proto3" json:"aggregate_id,omitempty"`
 	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
 	Timestamp     string                 `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	DataJson      string                 `protobuf:"bytes,6,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"` // Event data JSON olarak string
+	DataJson      string                 `protobuf:"bytes,6,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`                 // Event data JSON olarak string
+	Position      uint64                 `protobuf:"varint,7,opt,name=position,proto3" json:"position,omitempty"`                                // Tüm stream'ler genelinde boşluksuz artan sıra
+	SchemaVersion uint32                 `protobuf:"varint,8,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"` // data_json'ın şema versiyonu (okunurken güncel versiyona upcast edilir)
+	Metadata      *EventMetadata         `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	TenantId      string                 `protobuf:"bytes,10,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`                // Stream'in ait olduğu tenant (istekte x-tenant-id metadata'sıyla seçilir)
+	AggregateType string                 `protobuf:"bytes,11,opt,name=aggregate_type,json=aggregateType,proto3" json:"aggregate_type,omitempty"` // Stream'in aggregate tipi (user, order ...)
+	Category      string                 `protobuf:"bytes,12,opt,name=category,proto3" json:"category,omitempty"`                                // Stream kategorisi (catalog'dan, tanımlı değilse aggregate tipi)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -201,6 +304,117 @@ func (x *Event) GetDataJson() string {
 	return ""
 }
 
+func (x *Event) GetPosition() uint64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Event) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Event) GetMetadata() *EventMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Event) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Event) GetAggregateType() string {
+	if x != nil {
+		return x.AggregateType
+	}
+	return ""
+}
+
+func (x *Event) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+// Event'i hangi istek, kullanıcı ve servisin ürettiği
+type EventMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"` // Aynı kullanıcı isteğinden doğan tüm event'lerde ortak
+	CausationId   string                 `protobuf:"bytes,2,opt,name=causation_id,json=causationId,proto3" json:"causation_id,omitempty"`       // Event'e doğrudan sebep olan istek/command ya da event
+	ActorId       string                 `protobuf:"bytes,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`                   // İşlemi yapan kullanıcı
+	SourceService string                 `protobuf:"bytes,4,opt,name=source_service,json=sourceService,proto3" json:"source_service,omitempty"` // Event'i üreten servis
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventMetadata) Reset() {
+	*x = EventMetadata{}
+	mi := &file_proto_event_store_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventMetadata) ProtoMessage() {}
+
+func (x *EventMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventMetadata.ProtoReflect.Descriptor instead.
+func (*EventMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EventMetadata) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *EventMetadata) GetCausationId() string {
+	if x != nil {
+		return x.CausationId
+	}
+	return ""
+}
+
+func (x *EventMetadata) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *EventMetadata) GetSourceService() string {
+	if x != nil {
+		return x.SourceService
+	}
+	return ""
+}
+
 // Snapshot ile aggregate getirme request
 type GetAggregateWithSnapshotRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -211,7 +425,7 @@ type GetAggregateWithSnapshotRequest struct {
 
 func (x *GetAggregateWithSnapshotRequest) Reset() {
 	*x = GetAggregateWithSnapshotRequest{}
-	mi := &file_proto_event_store_proto_msgTypes[3]
+	mi := &file_proto_event_store_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -223,7 +437,7 @@ func (x *GetAggregateWithSnapshotRequest) String() string {
 func (*GetAggregateWithSnapshotRequest) ProtoMessage() {}
 
 func (x *GetAggregateWithSnapshotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_event_store_proto_msgTypes[3]
+	mi := &file_proto_event_store_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -236,7 +450,7 @@ func (x *GetAggregateWithSnapshotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAggregateWithSnapshotRequest.ProtoReflect.Descriptor instead.
 func (*GetAggregateWithSnapshotRequest) Descriptor() ([]byte, []int) {
-	return file_proto_event_store_proto_rawDescGZIP(), []int{3}
+	return file_proto_event_store_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetAggregateWithSnapshotRequest) GetAggregateId() string {
@@ -260,7 +474,7 @@ type GetAggregateWithSnapshotResponse struct {
 
 func (x *GetAggregateWithSnapshotResponse) Reset() {
 	*x = GetAggregateWithSnapshotResponse{}
-	mi := &file_proto_event_store_proto_msgTypes[4]
+	mi := &file_proto_event_store_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -272,7 +486,7 @@ func (x *GetAggregateWithSnapshotResponse) String() string {
 func (*GetAggregateWithSnapshotResponse) ProtoMessage() {}
 
 func (x *GetAggregateWithSnapshotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_event_store_proto_msgTypes[4]
+	mi := &file_proto_event_store_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -285,7 +499,7 @@ func (x *GetAggregateWithSnapshotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAggregateWithSnapshotResponse.ProtoReflect.Descriptor instead.
 func (*GetAggregateWithSnapshotResponse) Descriptor() ([]byte, []int) {
-	return file_proto_event_store_proto_rawDescGZIP(), []int{4}
+	return file_proto_event_store_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetAggregateWithSnapshotResponse) GetAggregateId() string {
@@ -323,87 +537,880 @@ func (x *GetAggregateWithSnapshotResponse) GetEventsReplayed() uint32 {
 	return 0
 }
 
-var File_proto_event_store_proto protoreflect.FileDescriptor
+type ExpectedVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          ExpectedVersionKind    `protobuf:"varint,1,opt,name=kind,proto3,enum=eventstore.ExpectedVersionKind" json:"kind,omitempty"`
+	Version       uint32                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // Sadece EXACT için kullanılır
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_event_store_proto_rawDesc = "" +
-	"\n" +
-	"\x17proto/event_store.proto\x12\n" +
-	"eventstore\">\n" +
-	"\x19GetAggregateEventsRequest\x12!\n" +
-	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\"G\n" +
-	"\x1aGetAggregateEventsResponse\x12)\n" +
-	"\x06events\x18\x01 \x03(\v2\x11.eventstore.EventR\x06events\"\xae\x01\n" +
-	"\x05Event\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
-	"\n" +
-	"event_type\x18\x02 \x01(\tR\teventType\x12!\n" +
-	"\faggregate_id\x18\x03 \x01(\tR\vaggregateId\x12\x18\n" +
-	"\aversion\x18\x04 \x01(\x05R\aversion\x12\x1c\n" +
-	"\ttimestamp\x18\x05 \x01(\tR\ttimestamp\x12\x1b\n" +
-	"\tdata_json\x18\x06 \x01(\tR\bdataJson\"D\n" +
-	"\x1fGetAggregateWithSnapshotRequest\x12!\n" +
-	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\"\xcc\x01\n" +
-	" GetAggregateWithSnapshotResponse\x12!\n" +
-	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\rR\aversion\x12\x1d\n" +
-	"\n" +
-	"state_json\x18\x03 \x01(\tR\tstateJson\x12#\n" +
-	"\rfrom_snapshot\x18\x04 \x01(\bR\ffromSnapshot\x12'\n" +
-	"\x0fevents_replayed\x18\x05 \x01(\rR\x0eeventsReplayed2\xef\x01\n" +
-	"\x11EventStoreService\x12c\n" +
-	"\x12GetAggregateEvents\x12%.eventstore.GetAggregateEventsRequest\x1a&.eventstore.GetAggregateEventsResponse\x12u\n" +
-	"\x18GetAggregateWithSnapshot\x12+.eventstore.GetAggregateWithSnapshotRequest\x1a,.eventstore.GetAggregateWithSnapshotResponseB)Z'github.com/eyupaydin41/proto/eventstoreb\x06proto3"
+func (x *ExpectedVersion) Reset() {
+	*x = ExpectedVersion{}
+	mi := &file_proto_event_store_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-var (
-	file_proto_event_store_proto_rawDescOnce sync.Once
-	file_proto_event_store_proto_rawDescData []byte
-)
+func (x *ExpectedVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func file_proto_event_store_proto_rawDescGZIP() []byte {
-	file_proto_event_store_proto_rawDescOnce.Do(func() {
-		file_proto_event_store_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_event_store_proto_rawDesc), len(file_proto_event_store_proto_rawDesc)))
-	})
-	return file_proto_event_store_proto_rawDescData
+func (*ExpectedVersion) ProtoMessage() {}
+
+func (x *ExpectedVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_proto_event_store_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_proto_event_store_proto_goTypes = []any{
-	(*GetAggregateEventsRequest)(nil),        // 0: eventstore.GetAggregateEventsRequest
-	(*GetAggregateEventsResponse)(nil),       // 1: eventstore.GetAggregateEventsResponse
-	(*Event)(nil),                            // 2: eventstore.Event
-	(*GetAggregateWithSnapshotRequest)(nil),  // 3: eventstore.GetAggregateWithSnapshotRequest
-	(*GetAggregateWithSnapshotResponse)(nil), // 4: eventstore.GetAggregateWithSnapshotResponse
+// Deprecated: Use ExpectedVersion.ProtoReflect.Descriptor instead.
+func (*ExpectedVersion) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{6}
 }
-var file_proto_event_store_proto_depIdxs = []int32{
-	2, // 0: eventstore.GetAggregateEventsResponse.events:type_name -> eventstore.Event
-	0, // 1: eventstore.EventStoreService.GetAggregateEvents:input_type -> eventstore.GetAggregateEventsRequest
-	3, // 2: eventstore.EventStoreService.GetAggregateWithSnapshot:input_type -> eventstore.GetAggregateWithSnapshotRequest
-	1, // 3: eventstore.EventStoreService.GetAggregateEvents:output_type -> eventstore.GetAggregateEventsResponse
-	4, // 4: eventstore.EventStoreService.GetAggregateWithSnapshot:output_type -> eventstore.GetAggregateWithSnapshotResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+
+func (x *ExpectedVersion) GetKind() ExpectedVersionKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ExpectedVersionKind_EXPECTED_VERSION_ANY
 }
 
-func init() { file_proto_event_store_proto_init() }
-func file_proto_event_store_proto_init() {
-	if File_proto_event_store_proto != nil {
-		return
+func (x *ExpectedVersion) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Eklenecek event (version'ı event-store atar)
+type NewEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	DataJson      string                 `protobuf:"bytes,3,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`
+	Id            string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`                                             // Producer'ın atadığı event ID (idempotency için, boşsa event-store üretir)
+	SchemaVersion uint32                 `protobuf:"varint,5,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"` // data_json'ın şema versiyonu (0 = 1)
+	Metadata      *EventMetadata         `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewEvent) Reset() {
+	*x = NewEvent{}
+	mi := &file_proto_event_store_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewEvent) ProtoMessage() {}
+
+func (x *NewEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewEvent.ProtoReflect.Descriptor instead.
+func (*NewEvent) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NewEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *NewEvent) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *NewEvent) GetDataJson() string {
+	if x != nil {
+		return x.DataJson
+	}
+	return ""
+}
+
+func (x *NewEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NewEvent) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *NewEvent) GetMetadata() *EventMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Aggregate'e toplu event ekleme request
+type AppendEventsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AggregateId     string                 `protobuf:"bytes,1,opt,name=aggregate_id,json=aggregateId,proto3" json:"aggregate_id,omitempty"`
+	ExpectedVersion *ExpectedVersion       `protobuf:"bytes,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	Events          []*NewEvent            `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	AggregateType   string                 `protobuf:"bytes,4,opt,name=aggregate_type,json=aggregateType,proto3" json:"aggregate_type,omitempty"` // Boş = stream'in tipi, yeni stream'de event tipinin öneki
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AppendEventsRequest) Reset() {
+	*x = AppendEventsRequest{}
+	mi := &file_proto_event_store_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendEventsRequest) ProtoMessage() {}
+
+func (x *AppendEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendEventsRequest.ProtoReflect.Descriptor instead.
+func (*AppendEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AppendEventsRequest) GetAggregateId() string {
+	if x != nil {
+		return x.AggregateId
+	}
+	return ""
+}
+
+func (x *AppendEventsRequest) GetExpectedVersion() *ExpectedVersion {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return nil
+}
+
+func (x *AppendEventsRequest) GetEvents() []*NewEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *AppendEventsRequest) GetAggregateType() string {
+	if x != nil {
+		return x.AggregateType
+	}
+	return ""
+}
+
+// Aggregate'e toplu event ekleme response
+type AppendEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AggregateId   string                 `protobuf:"bytes,1,opt,name=aggregate_id,json=aggregateId,proto3" json:"aggregate_id,omitempty"`
+	FirstVersion  uint32                 `protobuf:"varint,2,opt,name=first_version,json=firstVersion,proto3" json:"first_version,omitempty"` // Eklenen ilk event'in version'ı
+	LastVersion   uint32                 `protobuf:"varint,3,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`    // Stream'in yeni versiyonu
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendEventsResponse) Reset() {
+	*x = AppendEventsResponse{}
+	mi := &file_proto_event_store_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendEventsResponse) ProtoMessage() {}
+
+func (x *AppendEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendEventsResponse.ProtoReflect.Descriptor instead.
+func (*AppendEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AppendEventsResponse) GetAggregateId() string {
+	if x != nil {
+		return x.AggregateId
+	}
+	return ""
+}
+
+func (x *AppendEventsResponse) GetFirstVersion() uint32 {
+	if x != nil {
+		return x.FirstVersion
+	}
+	return 0
+}
+
+func (x *AppendEventsResponse) GetLastVersion() uint32 {
+	if x != nil {
+		return x.LastVersion
+	}
+	return 0
+}
+
+// Global position'dan okuma request
+type ReadAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromPosition  uint64                 `protobuf:"varint,1,opt,name=from_position,json=fromPosition,proto3" json:"from_position,omitempty"` // Bu position dahil (0 = baştan)
+	MaxCount      uint32                 `protobuf:"varint,2,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`             // 0 = varsayılan (1000)
+	EventType     string                 `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`           // Opsiyonel event tipi filtresi
+	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`                              // Opsiyonel stream kategorisi filtresi
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadAllRequest) Reset() {
+	*x = ReadAllRequest{}
+	mi := &file_proto_event_store_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadAllRequest) ProtoMessage() {}
+
+func (x *ReadAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadAllRequest.ProtoReflect.Descriptor instead.
+func (*ReadAllRequest) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReadAllRequest) GetFromPosition() uint64 {
+	if x != nil {
+		return x.FromPosition
+	}
+	return 0
+}
+
+func (x *ReadAllRequest) GetMaxCount() uint32 {
+	if x != nil {
+		return x.MaxCount
+	}
+	return 0
+}
+
+func (x *ReadAllRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *ReadAllRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+// Global position'dan okuma response
+type ReadAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPosition  uint64                 `protobuf:"varint,2,opt,name=next_position,json=nextPosition,proto3" json:"next_position,omitempty"` // Kaldığı yerden devam etmek için bir sonraki from_position
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadAllResponse) Reset() {
+	*x = ReadAllResponse{}
+	mi := &file_proto_event_store_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadAllResponse) ProtoMessage() {}
+
+func (x *ReadAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadAllResponse.ProtoReflect.Descriptor instead.
+func (*ReadAllResponse) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReadAllResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ReadAllResponse) GetNextPosition() uint64 {
+	if x != nil {
+		return x.NextPosition
+	}
+	return 0
+}
+
+// Tüm event'lere abonelik request
+type SubscribeAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromPosition  uint64                 `protobuf:"varint,1,opt,name=from_position,json=fromPosition,proto3" json:"from_position,omitempty"` // Bu position dahil (0 = baştan)
+	EventTypes    []string               `protobuf:"bytes,2,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`        // Boş = tüm tipler
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`                              // Boş = tüm kategoriler
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeAllRequest) Reset() {
+	*x = SubscribeAllRequest{}
+	mi := &file_proto_event_store_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeAllRequest) ProtoMessage() {}
+
+func (x *SubscribeAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeAllRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeAllRequest) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SubscribeAllRequest) GetFromPosition() uint64 {
+	if x != nil {
+		return x.FromPosition
+	}
+	return 0
+}
+
+func (x *SubscribeAllRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *SubscribeAllRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+// Tek stream'e abonelik request
+type SubscribeToStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AggregateId   string                 `protobuf:"bytes,1,opt,name=aggregate_id,json=aggregateId,proto3" json:"aggregate_id,omitempty"`
+	FromVersion   uint32                 `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"` // Bu version dahil (0 = baştan)
+	EventTypes    []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`     // Boş = tüm tipler
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeToStreamRequest) Reset() {
+	*x = SubscribeToStreamRequest{}
+	mi := &file_proto_event_store_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeToStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToStreamRequest) ProtoMessage() {}
+
+func (x *SubscribeToStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToStreamRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubscribeToStreamRequest) GetAggregateId() string {
+	if x != nil {
+		return x.AggregateId
+	}
+	return ""
+}
+
+func (x *SubscribeToStreamRequest) GetFromVersion() uint32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *SubscribeToStreamRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+// Abonelik mesajı: ya bir event ya da catch-up'ın bittiğini bildiren işaret
+type SubscriptionMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*SubscriptionMessage_Event
+	//	*SubscriptionMessage_CaughtUp
+	Content       isSubscriptionMessage_Content `protobuf_oneof:"content"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionMessage) Reset() {
+	*x = SubscriptionMessage{}
+	mi := &file_proto_event_store_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionMessage) ProtoMessage() {}
+
+func (x *SubscriptionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionMessage.ProtoReflect.Descriptor instead.
+func (*SubscriptionMessage) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SubscriptionMessage) GetContent() isSubscriptionMessage_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *SubscriptionMessage) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Content.(*SubscriptionMessage_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *SubscriptionMessage) GetCaughtUp() bool {
+	if x != nil {
+		if x, ok := x.Content.(*SubscriptionMessage_CaughtUp); ok {
+			return x.CaughtUp
+		}
+	}
+	return false
+}
+
+type isSubscriptionMessage_Content interface {
+	isSubscriptionMessage_Content()
+}
+
+type SubscriptionMessage_Event struct {
+	Event *Event `protobuf:"bytes,1,opt,name=event,proto3,oneof"`
+}
+
+type SubscriptionMessage_CaughtUp struct {
+	CaughtUp bool `protobuf:"varint,2,opt,name=caught_up,json=caughtUp,proto3,oneof"` // Kayıtlı event'ler bitti, bundan sonrası canlı
+}
+
+func (*SubscriptionMessage_Event) isSubscriptionMessage_Content() {}
+
+func (*SubscriptionMessage_CaughtUp) isSubscriptionMessage_Content() {}
+
+// Aggregate stream'i okuma request
+type ReadStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AggregateId   string                 `protobuf:"bytes,1,opt,name=aggregate_id,json=aggregateId,proto3" json:"aggregate_id,omitempty"`
+	FromVersion   uint32                 `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"` // Dahil alt sınır (0 = sınır yok)
+	ToVersion     uint32                 `protobuf:"varint,3,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`       // Dahil üst sınır (0 = sınır yok)
+	EventTypes    []string               `protobuf:"bytes,4,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`     // Boş = tüm tipler
+	Direction     ReadDirection          `protobuf:"varint,5,opt,name=direction,proto3,enum=eventstore.ReadDirection" json:"direction,omitempty"`
+	MaxCount      uint32                 `protobuf:"varint,6,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"` // 0 = sınırsız
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadStreamRequest) Reset() {
+	*x = ReadStreamRequest{}
+	mi := &file_proto_event_store_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadStreamRequest) ProtoMessage() {}
+
+func (x *ReadStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_event_store_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadStreamRequest.ProtoReflect.Descriptor instead.
+func (*ReadStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_event_store_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReadStreamRequest) GetAggregateId() string {
+	if x != nil {
+		return x.AggregateId
+	}
+	return ""
+}
+
+func (x *ReadStreamRequest) GetFromVersion() uint32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *ReadStreamRequest) GetToVersion() uint32 {
+	if x != nil {
+		return x.ToVersion
+	}
+	return 0
+}
+
+func (x *ReadStreamRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *ReadStreamRequest) GetDirection() ReadDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return ReadDirection_READ_DIRECTION_FORWARD
+}
+
+func (x *ReadStreamRequest) GetMaxCount() uint32 {
+	if x != nil {
+		return x.MaxCount
+	}
+	return 0
+}
+
+var File_proto_event_store_proto protoreflect.FileDescriptor
+
+const file_proto_event_store_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/event_store.proto\x12\n" +
+	"eventstore\">\n" +
+	"\x19GetAggregateEventsRequest\x12!\n" +
+	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\"G\n" +
+	"\x1aGetAggregateEventsResponse\x12)\n" +
+	"\x06events\x18\x01 \x03(\v2\x11.eventstore.EventR\x06events\"\x88\x03\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12!\n" +
+	"\faggregate_id\x18\x03 \x01(\tR\vaggregateId\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\tR\ttimestamp\x12\x1b\n" +
+	"\tdata_json\x18\x06 \x01(\tR\bdataJson\x12\x1a\n" +
+	"\bposition\x18\a \x01(\x04R\bposition\x12%\n" +
+	"\x0eschema_version\x18\b \x01(\rR\rschemaVersion\x125\n" +
+	"\bmetadata\x18\t \x01(\v2\x19.eventstore.EventMetadataR\bmetadata\x12\x1b\n" +
+	"\ttenant_id\x18\n" +
+	" \x01(\tR\btenantId\x12%\n" +
+	"\x0eaggregate_type\x18\v \x01(\tR\raggregateType\x12\x1a\n" +
+	"\bcategory\x18\f \x01(\tR\bcategory\"\x9b\x01\n" +
+	"\rEventMetadata\x12%\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12!\n" +
+	"\fcausation_id\x18\x02 \x01(\tR\vcausationId\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\tR\aactorId\x12%\n" +
+	"\x0esource_service\x18\x04 \x01(\tR\rsourceService\"D\n" +
+	"\x1fGetAggregateWithSnapshotRequest\x12!\n" +
+	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\"\xcc\x01\n" +
+	" GetAggregateWithSnapshotResponse\x12!\n" +
+	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\rR\aversion\x12\x1d\n" +
+	"\n" +
+	"state_json\x18\x03 \x01(\tR\tstateJson\x12#\n" +
+	"\rfrom_snapshot\x18\x04 \x01(\bR\ffromSnapshot\x12'\n" +
+	"\x0fevents_replayed\x18\x05 \x01(\rR\x0eeventsReplayed\"`\n" +
+	"\x0fExpectedVersion\x123\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1f.eventstore.ExpectedVersionKindR\x04kind\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\rR\aversion\"\xd2\x01\n" +
+	"\bNewEvent\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\x12\x1b\n" +
+	"\tdata_json\x18\x03 \x01(\tR\bdataJson\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12%\n" +
+	"\x0eschema_version\x18\x05 \x01(\rR\rschemaVersion\x125\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x19.eventstore.EventMetadataR\bmetadata\"\xd5\x01\n" +
+	"\x13AppendEventsRequest\x12!\n" +
+	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\x12F\n" +
+	"\x10expected_version\x18\x02 \x01(\v2\x1b.eventstore.ExpectedVersionR\x0fexpectedVersion\x12,\n" +
+	"\x06events\x18\x03 \x03(\v2\x14.eventstore.NewEventR\x06events\x12%\n" +
+	"\x0eaggregate_type\x18\x04 \x01(\tR\raggregateType\"\x81\x01\n" +
+	"\x14AppendEventsResponse\x12!\n" +
+	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\x12#\n" +
+	"\rfirst_version\x18\x02 \x01(\rR\ffirstVersion\x12!\n" +
+	"\flast_version\x18\x03 \x01(\rR\vlastVersion\"\x8d\x01\n" +
+	"\x0eReadAllRequest\x12#\n" +
+	"\rfrom_position\x18\x01 \x01(\x04R\ffromPosition\x12\x1b\n" +
+	"\tmax_count\x18\x02 \x01(\rR\bmaxCount\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\"a\n" +
+	"\x0fReadAllResponse\x12)\n" +
+	"\x06events\x18\x01 \x03(\v2\x11.eventstore.EventR\x06events\x12#\n" +
+	"\rnext_position\x18\x02 \x01(\x04R\fnextPosition\"w\n" +
+	"\x13SubscribeAllRequest\x12#\n" +
+	"\rfrom_position\x18\x01 \x01(\x04R\ffromPosition\x12\x1f\n" +
+	"\vevent_types\x18\x02 \x03(\tR\n" +
+	"eventTypes\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\"\x81\x01\n" +
+	"\x18SubscribeToStreamRequest\x12!\n" +
+	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\x12!\n" +
+	"\ffrom_version\x18\x02 \x01(\rR\vfromVersion\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\"j\n" +
+	"\x13SubscriptionMessage\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x11.eventstore.EventH\x00R\x05event\x12\x1d\n" +
+	"\tcaught_up\x18\x02 \x01(\bH\x00R\bcaughtUpB\t\n" +
+	"\acontent\"\xef\x01\n" +
+	"\x11ReadStreamRequest\x12!\n" +
+	"\faggregate_id\x18\x01 \x01(\tR\vaggregateId\x12!\n" +
+	"\ffrom_version\x18\x02 \x01(\rR\vfromVersion\x12\x1d\n" +
+	"\n" +
+	"to_version\x18\x03 \x01(\rR\ttoVersion\x12\x1f\n" +
+	"\vevent_types\x18\x04 \x03(\tR\n" +
+	"eventTypes\x127\n" +
+	"\tdirection\x18\x05 \x01(\x0e2\x19.eventstore.ReadDirectionR\tdirection\x12\x1b\n" +
+	"\tmax_count\x18\x06 \x01(\rR\bmaxCount*k\n" +
+	"\x13ExpectedVersionKind\x12\x18\n" +
+	"\x14EXPECTED_VERSION_ANY\x10\x00\x12\x1e\n" +
+	"\x1aEXPECTED_VERSION_NO_STREAM\x10\x01\x12\x1a\n" +
+	"\x16EXPECTED_VERSION_EXACT\x10\x02*H\n" +
+	"\rReadDirection\x12\x1a\n" +
+	"\x16READ_DIRECTION_FORWARD\x10\x00\x12\x1b\n" +
+	"\x17READ_DIRECTION_BACKWARD\x10\x012\xfa\x04\n" +
+	"\x11EventStoreService\x12c\n" +
+	"\x12GetAggregateEvents\x12%.eventstore.GetAggregateEventsRequest\x1a&.eventstore.GetAggregateEventsResponse\x12u\n" +
+	"\x18GetAggregateWithSnapshot\x12+.eventstore.GetAggregateWithSnapshotRequest\x1a,.eventstore.GetAggregateWithSnapshotResponse\x12Q\n" +
+	"\fAppendEvents\x12\x1f.eventstore.AppendEventsRequest\x1a .eventstore.AppendEventsResponse\x12B\n" +
+	"\aReadAll\x12\x1a.eventstore.ReadAllRequest\x1a\x1b.eventstore.ReadAllResponse\x12R\n" +
+	"\fSubscribeAll\x12\x1f.eventstore.SubscribeAllRequest\x1a\x1f.eventstore.SubscriptionMessage0\x01\x12\\\n" +
+	"\x11SubscribeToStream\x12$.eventstore.SubscribeToStreamRequest\x1a\x1f.eventstore.SubscriptionMessage0\x01\x12@\n" +
+	"\n" +
+	"ReadStream\x12\x1d.eventstore.ReadStreamRequest\x1a\x11.eventstore.Event0\x01B)Z'github.com/eyupaydin41/proto/eventstoreb\x06proto3"
+
+var (
+	file_proto_event_store_proto_rawDescOnce sync.Once
+	file_proto_event_store_proto_rawDescData []byte
+)
+
+func file_proto_event_store_proto_rawDescGZIP() []byte {
+	file_proto_event_store_proto_rawDescOnce.Do(func() {
+		file_proto_event_store_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_event_store_proto_rawDesc), len(file_proto_event_store_proto_rawDesc)))
+	})
+	return file_proto_event_store_proto_rawDescData
+}
+
+var file_proto_event_store_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_event_store_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_proto_event_store_proto_goTypes = []any{
+	(ExpectedVersionKind)(0),                 // 0: eventstore.ExpectedVersionKind
+	(ReadDirection)(0),                       // 1: eventstore.ReadDirection
+	(*GetAggregateEventsRequest)(nil),        // 2: eventstore.GetAggregateEventsRequest
+	(*GetAggregateEventsResponse)(nil),       // 3: eventstore.GetAggregateEventsResponse
+	(*Event)(nil),                            // 4: eventstore.Event
+	(*EventMetadata)(nil),                    // 5: eventstore.EventMetadata
+	(*GetAggregateWithSnapshotRequest)(nil),  // 6: eventstore.GetAggregateWithSnapshotRequest
+	(*GetAggregateWithSnapshotResponse)(nil), // 7: eventstore.GetAggregateWithSnapshotResponse
+	(*ExpectedVersion)(nil),                  // 8: eventstore.ExpectedVersion
+	(*NewEvent)(nil),                         // 9: eventstore.NewEvent
+	(*AppendEventsRequest)(nil),              // 10: eventstore.AppendEventsRequest
+	(*AppendEventsResponse)(nil),             // 11: eventstore.AppendEventsResponse
+	(*ReadAllRequest)(nil),                   // 12: eventstore.ReadAllRequest
+	(*ReadAllResponse)(nil),                  // 13: eventstore.ReadAllResponse
+	(*SubscribeAllRequest)(nil),              // 14: eventstore.SubscribeAllRequest
+	(*SubscribeToStreamRequest)(nil),         // 15: eventstore.SubscribeToStreamRequest
+	(*SubscriptionMessage)(nil),              // 16: eventstore.SubscriptionMessage
+	(*ReadStreamRequest)(nil),                // 17: eventstore.ReadStreamRequest
+}
+var file_proto_event_store_proto_depIdxs = []int32{
+	4,  // 0: eventstore.GetAggregateEventsResponse.events:type_name -> eventstore.Event
+	5,  // 1: eventstore.Event.metadata:type_name -> eventstore.EventMetadata
+	0,  // 2: eventstore.ExpectedVersion.kind:type_name -> eventstore.ExpectedVersionKind
+	5,  // 3: eventstore.NewEvent.metadata:type_name -> eventstore.EventMetadata
+	8,  // 4: eventstore.AppendEventsRequest.expected_version:type_name -> eventstore.ExpectedVersion
+	9,  // 5: eventstore.AppendEventsRequest.events:type_name -> eventstore.NewEvent
+	4,  // 6: eventstore.ReadAllResponse.events:type_name -> eventstore.Event
+	4,  // 7: eventstore.SubscriptionMessage.event:type_name -> eventstore.Event
+	1,  // 8: eventstore.ReadStreamRequest.direction:type_name -> eventstore.ReadDirection
+	2,  // 9: eventstore.EventStoreService.GetAggregateEvents:input_type -> eventstore.GetAggregateEventsRequest
+	6,  // 10: eventstore.EventStoreService.GetAggregateWithSnapshot:input_type -> eventstore.GetAggregateWithSnapshotRequest
+	10, // 11: eventstore.EventStoreService.AppendEvents:input_type -> eventstore.AppendEventsRequest
+	12, // 12: eventstore.EventStoreService.ReadAll:input_type -> eventstore.ReadAllRequest
+	14, // 13: eventstore.EventStoreService.SubscribeAll:input_type -> eventstore.SubscribeAllRequest
+	15, // 14: eventstore.EventStoreService.SubscribeToStream:input_type -> eventstore.SubscribeToStreamRequest
+	17, // 15: eventstore.EventStoreService.ReadStream:input_type -> eventstore.ReadStreamRequest
+	3,  // 16: eventstore.EventStoreService.GetAggregateEvents:output_type -> eventstore.GetAggregateEventsResponse
+	7,  // 17: eventstore.EventStoreService.GetAggregateWithSnapshot:output_type -> eventstore.GetAggregateWithSnapshotResponse
+	11, // 18: eventstore.EventStoreService.AppendEvents:output_type -> eventstore.AppendEventsResponse
+	13, // 19: eventstore.EventStoreService.ReadAll:output_type -> eventstore.ReadAllResponse
+	16, // 20: eventstore.EventStoreService.SubscribeAll:output_type -> eventstore.SubscriptionMessage
+	16, // 21: eventstore.EventStoreService.SubscribeToStream:output_type -> eventstore.SubscriptionMessage
+	4,  // 22: eventstore.EventStoreService.ReadStream:output_type -> eventstore.Event
+	16, // [16:23] is the sub-list for method output_type
+	9,  // [9:16] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_proto_event_store_proto_init() }
+func file_proto_event_store_proto_init() {
+	if File_proto_event_store_proto != nil {
+		return
+	}
+	file_proto_event_store_proto_msgTypes[14].OneofWrappers = []any{
+		(*SubscriptionMessage_Event)(nil),
+		(*SubscriptionMessage_CaughtUp)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_event_store_proto_rawDesc), len(file_proto_event_store_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      2,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_event_store_proto_goTypes,
 		DependencyIndexes: file_proto_event_store_proto_depIdxs,
+		EnumInfos:         file_proto_event_store_proto_enumTypes,
 		MessageInfos:      file_proto_event_store_proto_msgTypes,
 	}.Build()
 	File_proto_event_store_proto = out.File
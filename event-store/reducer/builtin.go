@@ -0,0 +1,36 @@
+package reducer
+
+import "github.com/eyupaydin41/event-store/model"
+
+// NewDefaultRegistry - Store'a yazan servislerin aggregate'leri
+// Yeni bir aggregate tipi eklendiğinde state'i ve işlediği event tipleriyle buraya kaydedilir
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+
+	mustRegister(registry, Reducer{
+		AggregateType: "user",
+		// auth-service'in domain.UserAggregate'inin ürettiği ve query-service'in login kaydı
+		EventTypes: []string{
+			"user.created",
+			"user.email.changed",
+			"user.password.changed",
+			"user.deactivated",
+			"user.login.recorded",
+		},
+		New: func(aggregateID string) State {
+			aggregate := model.NewUserAggregate()
+			aggregate.ID = aggregateID
+			return aggregate
+		},
+		// 1 registry öncesi UserAggregate'tir (snapshot'ları tag'siz, 0 okunur); 2: password_hash ve user.deactivated
+		StateVersion: 2,
+	})
+
+	return registry
+}
+
+func mustRegister(registry *Registry, reducer Reducer) {
+	if err := registry.Register(reducer); err != nil {
+		panic(err)
+	}
+}
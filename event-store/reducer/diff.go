@@ -0,0 +1,60 @@
+package reducer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Change - Bir state alanının iki değeri
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff - İki serialize edilmiş state'in üst seviye alanlarından değişenler
+// State tipleri aggregate'e göre farklı olduğu için alanlar JSON üzerinden karşılaştırılır
+func Diff(before, after []byte) (map[string]Change, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for field, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[field], value) {
+			changes[field] = Change{Before: beforeFields[field], After: value}
+		}
+	}
+	for field, value := range beforeFields {
+		if _, ok := afterFields[field]; !ok {
+			changes[field] = Change{Before: value}
+		}
+	}
+	return changes, nil
+}
+
+// DiffStates - Diff'in state'ler üzerinden hali
+func DiffStates(before, after State) (map[string]Change, error) {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return Diff(beforeJSON, afterJSON)
+}
+
+func fields(data []byte) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("state is not a JSON object: %w", err)
+	}
+	return values, nil
+}
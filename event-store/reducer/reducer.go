@@ -0,0 +1,148 @@
+package reducer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// ErrUnknownAggregateType - Aggregate tipi için kayıtlı reducer yok
+var ErrUnknownAggregateType = errors.New("unknown aggregate type")
+
+// ErrUnknownEventType - Stream'de reducer'ın işlemediği bir event tipi var
+// Replay bu event'i atlamaz, durur; state'in eksik kurulması sessizce kabul edilmez
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// State - Bir aggregate'in event'lerden kurulan durumu
+// JSON olarak serialize edilir (snapshot state'i, HTTP/gRPC yanıtları)
+type State interface {
+	// ApplyEvent - Event'i state'e uygular
+	ApplyEvent(event *model.Event) error
+	// StreamVersion - Uygulanan son event'in version'ı
+	StreamVersion() uint32
+}
+
+// Reducer - Bir aggregate tipinin state'ini event'lerden kurar
+type Reducer struct {
+	// AggregateType - Reducer'ın tipi (model.ValidStreamName)
+	AggregateType string
+	// EventTypes - Reducer'ın işlediği event tipleri; stream'de başka bir tip varsa Apply hata döner
+	EventTypes []string
+	// New - aggregateID için boş state
+	New func(aggregateID string) State
+	// StateVersion - State'in şeklinin ya da ApplyEvent mantığının sürümü
+	// Biri değiştiğinde artırılır; eski sürümle alınmış snapshot'lar yüklenmez ve yeniden oluşturulur
+	StateVersion uint32
+}
+
+// Handles - Event tipi reducer'ın işlediği tiplerden mi?
+func (r Reducer) Handles(eventType string) bool {
+	for _, handled := range r.EventTypes {
+		if handled == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply - Event'i state'e uygular; işlenmeyen tipte ErrUnknownEventType döner
+func (r Reducer) Apply(state State, event *model.Event) error {
+	if !r.Handles(event.EventType) {
+		return fmt.Errorf("%w: %s (event %s, version %d) is not handled by the %s reducer",
+			ErrUnknownEventType, event.EventType, event.ID, event.Version, r.AggregateType)
+	}
+	return state.ApplyEvent(event)
+}
+
+// Current - Snapshot bu reducer'ın şu anki sürümüyle mi alınmış?
+func (r Reducer) Current(snapshot *model.Snapshot) bool {
+	return snapshot.AggregateType == r.AggregateType && snapshot.StateVersion == r.StateVersion
+}
+
+// Restore - Serialize edilmiş state'i (snapshot) geri yükler
+func (r Reducer) Restore(aggregateID string, data []byte) (State, error) {
+	state := r.New(aggregateID)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s state: %w", r.AggregateType, err)
+	}
+	return state, nil
+}
+
+// Copy - State'in bağımsız bir kopyası (history'de her adımın state'i saklanırken)
+func (r Reducer) Copy(aggregateID string, state State) (State, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s state: %w", r.AggregateType, err)
+	}
+	return r.Restore(aggregateID, data)
+}
+
+// Registry - Aggregate tipine göre kayıtlı reducer'lar
+type Registry struct {
+	mu       sync.RWMutex
+	reducers map[string]Reducer
+}
+
+func NewRegistry() *Registry {
+	return &Registry{reducers: make(map[string]Reducer)}
+}
+
+// Register - Reducer'ı tipine kaydeder; aynı tip iki kez kaydedilemez
+func (r *Registry) Register(reducer Reducer) error {
+	if !model.ValidStreamName(reducer.AggregateType) {
+		return fmt.Errorf("invalid aggregate type %q", reducer.AggregateType)
+	}
+	if reducer.New == nil || len(reducer.EventTypes) == 0 {
+		return fmt.Errorf("reducer for %s needs a state constructor and at least one event type", reducer.AggregateType)
+	}
+	if reducer.StateVersion == 0 {
+		// 0 tag'siz eski snapshot'ların sürümüdür
+		return fmt.Errorf("reducer for %s needs a state version greater than 0", reducer.AggregateType)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.reducers[reducer.AggregateType]; exists {
+		return fmt.Errorf("reducer for %s is already registered", reducer.AggregateType)
+	}
+	r.reducers[reducer.AggregateType] = reducer
+	return nil
+}
+
+// Get - Tipin reducer'ı (boş tip DefaultAggregateType)
+func (r *Registry) Get(aggregateType string) (Reducer, error) {
+	aggregateType = model.NormalizeAggregateType(aggregateType)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reducer, ok := r.reducers[aggregateType]
+	if !ok {
+		return Reducer{}, fmt.Errorf("%w: %s", ErrUnknownAggregateType, aggregateType)
+	}
+	return reducer, nil
+}
+
+// Has - Tip için reducer kayıtlı mı?
+func (r *Registry) Has(aggregateType string) bool {
+	_, err := r.Get(aggregateType)
+	return err == nil
+}
+
+// AggregateTypes - Reducer'ı olan tipler (alfabetik)
+func (r *Registry) AggregateTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.reducers))
+	for aggregateType := range r.reducers {
+		types = append(types, aggregateType)
+	}
+	sort.Strings(types)
+	return types
+}
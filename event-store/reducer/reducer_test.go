@@ -0,0 +1,98 @@
+package reducer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+func userEvent(version uint32, eventType, payload string) *model.Event {
+	return &model.Event{
+		ID:          "user-1-" + eventType,
+		EventType:   eventType,
+		AggregateID: "user-1",
+		Payload:     payload,
+		Timestamp:   time.Date(2024, 1, 1, 0, 0, int(version), 0, time.UTC),
+		Version:     version,
+	}
+}
+
+func TestUserReducerBuildsAuthServiceState(t *testing.T) {
+	r, err := NewDefaultRegistry().Get("")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	state := r.New("user-1")
+	events := []*model.Event{
+		userEvent(1, "user.created", `{"aggregate_id":"user-1","email":"a@example.com","password_hash":"hash-1"}`),
+		userEvent(2, "user.password.changed", `{"new_password_hash":"hash-2"}`),
+		userEvent(3, "user.login.recorded", `{}`),
+		userEvent(4, "user.email.changed", `{"new_email":"b@example.com"}`),
+		userEvent(5, "user.deactivated", `{}`),
+	}
+	for _, event := range events {
+		if err := r.Apply(state, event); err != nil {
+			t.Fatalf("Apply %s: %v", event.EventType, err)
+		}
+	}
+
+	user := state.(*model.UserAggregate)
+	if user.ID != "user-1" || user.Email != "b@example.com" || user.PasswordHash != "hash-2" ||
+		user.Status != "deactivated" || user.Version != 5 || user.EventCount != 5 {
+		t.Errorf("unexpected user state: %+v", user)
+	}
+
+	copied, err := r.Copy("user-1", state)
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if copied.(*model.UserAggregate).PasswordHash != "hash-2" || copied.StreamVersion() != 5 {
+		t.Errorf("expected copy to keep the state, got %+v", copied)
+	}
+}
+
+func TestApplyReportsUnhandledEventTypes(t *testing.T) {
+	r, err := NewDefaultRegistry().Get("user")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	state := r.New("user-1")
+	err = r.Apply(state, userEvent(1, "user.updated", `{}`))
+	if !errors.Is(err, ErrUnknownEventType) {
+		t.Fatalf("expected ErrUnknownEventType, got %v", err)
+	}
+	if state.StreamVersion() != 0 {
+		t.Errorf("expected state to stay at version 0, got %d", state.StreamVersion())
+	}
+}
+
+func TestRegistryRejectsDuplicatesAndUnknownTypes(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	duplicate := Reducer{
+		AggregateType: "user",
+		EventTypes:    []string{"user.created"},
+		New:           func(string) State { return model.NewUserAggregate() },
+		StateVersion:  1,
+	}
+	if err := registry.Register(duplicate); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+	if err := registry.Register(Reducer{AggregateType: "order", New: duplicate.New, StateVersion: 1}); err == nil {
+		t.Error("expected reducer without event types to fail")
+	}
+	if err := registry.Register(Reducer{AggregateType: "order", EventTypes: []string{"order.placed"}, New: duplicate.New}); err == nil {
+		t.Error("expected reducer without state version to fail")
+	}
+
+	if _, err := registry.Get("order"); !errors.Is(err, ErrUnknownAggregateType) {
+		t.Errorf("expected ErrUnknownAggregateType, got %v", err)
+	}
+	if types := registry.AggregateTypes(); len(types) != 1 || types[0] != "user" {
+		t.Errorf("expected only user reducer, got %v", types)
+	}
+}
@@ -0,0 +1,24 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrVersionConflict - Aggregate'in bu version'ında zaten bir event var
+	// (unique constraint destekleyen backend'ler döner)
+	ErrVersionConflict = errors.New("aggregate version already exists")
+
+	// ErrDuplicateEventID - Aynı ID ile bir event zaten kayıtlı
+	ErrDuplicateEventID = errors.New("event id already exists")
+
+	// ErrKeyNotFound - Aggregate için henüz veri anahtarı oluşturulmamış
+	ErrKeyNotFound = errors.New("data key not found")
+
+	// ErrKeyDestroyed - Aggregate unutuldu, veri anahtarı yok edildi (crypto-shredding)
+	ErrKeyDestroyed = errors.New("data key destroyed")
+
+	// ErrSnapshotNotFound - Aggregate'in istenen version'da snapshot'ı yok
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrArchived - İstenen aralık soğuk depolamaya (arşiv) taşındı ve okuma arşive düşmüyor
+	ErrArchived = errors.New("requested events are archived")
+)
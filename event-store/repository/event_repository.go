@@ -9,6 +9,84 @@ import (
 	"github.com/eyupaydin41/event-store/model"
 )
 
+// eventColumns - events tablosundan okunan/yazılan kolonlar (eventFields ile aynı sırada)
+const eventColumns = "id, event_type, aggregate_id, payload, timestamp, version, position, schema_version, " +
+	"correlation_id, causation_id, actor_id, source_service, tenant_id, aggregate_type, category"
+
+// eventFields - Event'in eventColumns sırasındaki alanları (Scan için pointer'lar)
+func eventFields(event *model.Event) []interface{} {
+	return []interface{}{
+		&event.ID,
+		&event.EventType,
+		&event.AggregateID,
+		&event.Payload,
+		&event.Timestamp,
+		&event.Version,
+		&event.Position,
+		&event.SchemaVersion,
+		&event.Metadata.CorrelationID,
+		&event.Metadata.CausationID,
+		&event.Metadata.ActorID,
+		&event.Metadata.SourceService,
+		&event.TenantID,
+		&event.AggregateType,
+		&event.Category,
+	}
+}
+
+// eventValues - Event'in eventColumns sırasındaki değerleri (INSERT için)
+func eventValues(event *model.Event) []interface{} {
+	return []interface{}{
+		event.ID,
+		event.EventType,
+		event.AggregateID,
+		event.Payload,
+		event.Timestamp,
+		event.Version,
+		event.Position,
+		event.SchemaVersion,
+		event.Metadata.CorrelationID,
+		event.Metadata.CausationID,
+		event.Metadata.ActorID,
+		event.Metadata.SourceService,
+		model.NormalizeTenantID(event.TenantID),
+		model.NormalizeAggregateType(event.AggregateType),
+		model.NormalizeCategory(event.Category, event.AggregateType),
+	}
+}
+
+type filterColumn struct {
+	name  string
+	value interface{}
+}
+
+// metadataConditions - EventFilter'daki dolu metadata/şema/stream filtreleri (kolon adı + değer)
+func metadataConditions(filter model.EventFilter) []filterColumn {
+	var columns []filterColumn
+	if filter.CorrelationID != "" {
+		columns = append(columns, filterColumn{"correlation_id", filter.CorrelationID})
+	}
+	if filter.CausationID != "" {
+		columns = append(columns, filterColumn{"causation_id", filter.CausationID})
+	}
+	if filter.ActorID != "" {
+		columns = append(columns, filterColumn{"actor_id", filter.ActorID})
+	}
+	if filter.SourceService != "" {
+		columns = append(columns, filterColumn{"source_service", filter.SourceService})
+	}
+	if filter.SchemaVersion != nil {
+		columns = append(columns, filterColumn{"schema_version", *filter.SchemaVersion})
+	}
+	if filter.AggregateType != "" {
+		columns = append(columns, filterColumn{"aggregate_type", filter.AggregateType})
+	}
+	if filter.Category != "" {
+		columns = append(columns, filterColumn{"category", filter.Category})
+	}
+	return columns
+}
+
 type EventRepository struct {
 	conn driver.Conn
 }
@@ -19,30 +97,42 @@ func NewEventRepository(conn driver.Conn) *EventRepository {
 
 func (r *EventRepository) SaveEvent(event *model.Event) error {
 	ctx := context.Background()
-	query := `
-		INSERT INTO events (id, event_type, aggregate_id, payload, timestamp, version)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`
+	query := "INSERT INTO events (" + eventColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
-	if err := r.conn.Exec(ctx, query,
-		event.ID,
-		event.EventType,
-		event.AggregateID,
-		event.Payload,
-		event.Timestamp,
-		event.Version,
-	); err != nil {
+	if err := r.conn.Exec(ctx, query, eventValues(event)...); err != nil {
 		return fmt.Errorf("failed to save event: %w", err)
 	}
 
 	return nil
 }
 
+// SaveEvents - Birden fazla event'i tek batch olarak kaydeder
+func (r *EventRepository) SaveEvents(events []*model.Event) error {
+	ctx := context.Background()
+
+	batch, err := r.conn.PrepareBatch(ctx, "INSERT INTO events ("+eventColumns+")")
+	if err != nil {
+		return fmt.Errorf("failed to prepare batch: %w", err)
+	}
+
+	for _, event := range events {
+		if err := batch.Append(eventValues(event)...); err != nil {
+			return fmt.Errorf("failed to append event to batch: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to save events: %w", err)
+	}
+
+	return nil
+}
+
 func (r *EventRepository) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
 	ctx := context.Background()
 
-	var conditions []string
-	var args []interface{}
+	conditions := []string{"tenant_id = ?"}
+	args := []interface{}{model.NormalizeTenantID(filter.TenantID)}
 
 	if filter.EventType != "" {
 		conditions = append(conditions, "event_type = ?")
@@ -64,11 +154,27 @@ func (r *EventRepository) GetEvents(filter model.EventFilter) ([]*model.Event, e
 		args = append(args, filter.EndTime)
 	}
 
-	query := "SELECT id, event_type, aggregate_id, payload, timestamp, version FROM events"
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if filter.FromPosition > 0 {
+		conditions = append(conditions, "position >= ?")
+		args = append(args, filter.FromPosition)
+	}
+
+	for _, column := range metadataConditions(filter) {
+		conditions = append(conditions, column.name+" = ?")
+		args = append(args, column.value)
+	}
+
+	query := "SELECT " + eventColumns + " FROM events WHERE " + strings.Join(conditions, " AND ")
+	// Tek aggregate okunurken primary key sırası (aggregate_id, version) kullanılır,
+	// position'dan okuma global sırayla, diğer sorgular events_by_time projection'ı ile timestamp sırasıyla
+	switch {
+	case filter.AggregateID != "":
+		query += " ORDER BY version ASC"
+	case filter.FromPosition > 0:
+		query += " ORDER BY position ASC"
+	default:
+		query += " ORDER BY timestamp ASC"
 	}
-	query += " ORDER BY timestamp ASC"
 
 	if filter.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
@@ -86,18 +192,9 @@ func (r *EventRepository) GetEvents(filter model.EventFilter) ([]*model.Event, e
 	var events []*model.Event
 	for rows.Next() {
 		var event model.Event
-		var version uint32
-		if err := rows.Scan(
-			&event.ID,
-			&event.EventType,
-			&event.AggregateID,
-			&event.Payload,
-			&event.Timestamp,
-			&version,
-		); err != nil {
+		if err := rows.Scan(eventFields(&event)...); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
-		event.Version = version
 		events = append(events, &event)
 	}
 
@@ -108,43 +205,116 @@ func (r *EventRepository) GetEvents(filter model.EventFilter) ([]*model.Event, e
 	return events, nil
 }
 
-func (r *EventRepository) CountEvents() (uint64, error) {
+func (r *EventRepository) CountEvents(tenantID string) (uint64, error) {
 	ctx := context.Background()
 	var count uint64
-	err := r.conn.QueryRow(ctx, "SELECT count() FROM events").Scan(&count)
+	err := r.conn.QueryRow(ctx, "SELECT count() FROM events WHERE tenant_id = ?", model.NormalizeTenantID(tenantID)).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count events: %w", err)
 	}
 	return count, nil
 }
 
+func (r *EventRepository) StreamTail(tenantID, aggregateID string, fromVersion uint32) (*model.StreamTail, error) {
+	ctx := context.Background()
+	query := `
+		SELECT count(), sum(length(payload)), min(timestamp)
+		FROM events
+		WHERE aggregate_id = ? AND tenant_id = ? AND version >= ?
+	`
+
+	var tail model.StreamTail
+	if err := r.conn.QueryRow(ctx, query, aggregateID, model.NormalizeTenantID(tenantID), fromVersion).Scan(&tail.Events, &tail.Bytes, &tail.FirstAt); err != nil {
+		return nil, fmt.Errorf("failed to measure stream tail: %w", err)
+	}
+	if tail.Events == 0 {
+		// Boş kümede min(timestamp) 1970 döner
+		return &model.StreamTail{}, nil
+	}
+	return &tail, nil
+}
+
+// GetLastPosition - Kaydedilmiş en büyük global position (boş store için 0)
+func (r *EventRepository) GetLastPosition() (uint64, error) {
+	ctx := context.Background()
+	var position uint64
+
+	if err := r.conn.QueryRow(ctx, "SELECT max(position) FROM events").Scan(&position); err != nil {
+		return 0, fmt.Errorf("failed to get last position: %w", err)
+	}
+
+	return position, nil
+}
+
+// GetLatestVersionForAggregate - Aggregate'in son version'ı; event'i yoksa 0
+// Hata 0 olarak yutulmaz: expected version kontrolü boş stream sanıp version 1'i tekrar yazar
 func (r *EventRepository) GetLatestVersionForAggregate(aggregateID string) (uint32, error) {
 	ctx := context.Background()
 	var version uint32
 
 	query := "SELECT max(version) FROM events WHERE aggregate_id = ?"
-	err := r.conn.QueryRow(ctx, query, aggregateID).Scan(&version)
+	if err := r.conn.QueryRow(ctx, query, aggregateID).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get latest version: %w", err)
+	}
 
+	return version, nil
+}
+
+// EventExists - Bu ID ile kaydedilmiş bir event var mı?
+// Kafka redelivery/producer retry durumunda tekrar gelen event'leri ayıklamak için kullanılır
+func (r *EventRepository) EventExists(eventID string) (bool, error) {
+	ctx := context.Background()
+	var count uint64
+
+	if err := r.conn.QueryRow(ctx, "SELECT count() FROM events WHERE id = ?", eventID).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check event existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// FindExistingEventIDs - Verilen ID'lerden zaten kaydedilmiş olanları döner
+func (r *EventRepository) FindExistingEventIDs(eventIDs []string) (map[string]bool, error) {
+	ctx := context.Background()
+	existing := make(map[string]bool)
+	if len(eventIDs) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.conn.Query(ctx, "SELECT DISTINCT id FROM events WHERE id IN ?", eventIDs)
 	if err != nil {
-		return 0, nil
+		return nil, fmt.Errorf("failed to query existing event ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan event id: %w", err)
+		}
+		existing[id] = true
 	}
 
-	return version, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return existing, nil
 }
 
 // GetEventsAfterVersion - Belirli bir version'dan sonraki event'leri getirir
 // Snapshot'tan sonra sadece gerekli event'leri yüklemek için kullanılır
-func (r *EventRepository) GetEventsAfterVersion(aggregateID string, afterVersion uint32) ([]*model.Event, error) {
+func (r *EventRepository) GetEventsAfterVersion(tenantID, aggregateID string, afterVersion uint32) ([]*model.Event, error) {
 	ctx := context.Background()
 
 	query := `
-		SELECT id, event_type, aggregate_id, payload, timestamp, version
+		SELECT ` + eventColumns + `
 		FROM events
-		WHERE aggregate_id = ? AND version > ?
+		WHERE aggregate_id = ? AND version > ? AND tenant_id = ?
 		ORDER BY version ASC
 	`
 
-	rows, err := r.conn.Query(ctx, query, aggregateID, afterVersion)
+	rows, err := r.conn.Query(ctx, query, aggregateID, afterVersion, model.NormalizeTenantID(tenantID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query events after version: %w", err)
 	}
@@ -153,18 +323,9 @@ func (r *EventRepository) GetEventsAfterVersion(aggregateID string, afterVersion
 	var events []*model.Event
 	for rows.Next() {
 		var event model.Event
-		var version uint32
-		if err := rows.Scan(
-			&event.ID,
-			&event.EventType,
-			&event.AggregateID,
-			&event.Payload,
-			&event.Timestamp,
-			&version,
-		); err != nil {
+		if err := rows.Scan(eventFields(&event)...); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
-		event.Version = version
 		events = append(events, &event)
 	}
 
@@ -174,3 +335,60 @@ func (r *EventRepository) GetEventsAfterVersion(aggregateID string, afterVersion
 
 	return events, nil
 }
+
+// ReadStream - Aggregate stream'ini satır satır okuyup fn'e iletir, tüm event'leri belleğe almaz
+// Version aralığı, event tipi, zaman ve yön filtreleri sorguya eklenir; fn hata dönerse okuma durur
+func (r *EventRepository) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	conditions := []string{"aggregate_id = ?", "tenant_id = ?"}
+	args := []interface{}{query.AggregateID, model.NormalizeTenantID(query.TenantID)}
+
+	if query.FromVersion > 0 {
+		conditions = append(conditions, "version >= ?")
+		args = append(args, query.FromVersion)
+	}
+	if query.ToVersion > 0 {
+		conditions = append(conditions, "version <= ?")
+		args = append(args, query.ToVersion)
+	}
+	if len(query.EventTypes) > 0 {
+		conditions = append(conditions, "event_type IN ?")
+		args = append(args, query.EventTypes)
+	}
+	if !query.EndTime.IsZero() {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, query.EndTime)
+	}
+
+	sqlQuery := "SELECT " + eventColumns + " FROM events WHERE " +
+		strings.Join(conditions, " AND ")
+	if query.Direction == model.ReadBackward {
+		sqlQuery += " ORDER BY version DESC"
+	} else {
+		sqlQuery += " ORDER BY version ASC"
+	}
+	if query.MaxCount > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT %d", query.MaxCount)
+	}
+
+	rows, err := r.conn.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query stream: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event model.Event
+		if err := rows.Scan(eventFields(&event)...); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/eyupaydin41/event-store/config"
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// BenchmarkLoadAggregate - Eski (timestamp, id) ve yeni (aggregate_id, version) layout'larında
+// tek bir aggregate'in tüm event'lerini okuma süresini karşılaştırır.
+//
+// ClickHouse gerektirir, CLICKHOUSE_BENCH_ADDR verilmezse atlanır:
+//
+//	CLICKHOUSE_BENCH_ADDR=localhost:9000 CLICKHOUSE_BENCH_EVENTS=5000000 \
+//	  go test ./repository -run '^$' -bench LoadAggregate -benchtime 2000x
+//
+// Veri sunucu tarafında numbers() ile üretilir ve sonraki çalıştırmalarda tekrar kullanılır.
+func BenchmarkLoadAggregate(b *testing.B) {
+	addr := os.Getenv("CLICKHOUSE_BENCH_ADDR")
+	if addr == "" {
+		b.Skip("CLICKHOUSE_BENCH_ADDR not set")
+	}
+
+	totalEvents := benchEnvInt(b, "CLICKHOUSE_BENCH_EVENTS", 2_000_000)
+	aggregates := benchEnvInt(b, "CLICKHOUSE_BENCH_AGGREGATES", 100_000)
+	eventsPerAggregate := totalEvents / aggregates
+
+	layouts := []struct {
+		name     string
+		database string
+		create   func(conn driver.Conn) error
+	}{
+		{"legacy", "bench_events_legacy", createLegacyEventTable},
+		{"aggregate_ordered", "bench_events_v2", config.CreateEventTable},
+	}
+
+	for _, layout := range layouts {
+		conn := openBenchConn(b, addr, layout.database)
+		if err := layout.create(conn); err != nil {
+			b.Fatalf("create table: %v", err)
+		}
+		seedBenchEvents(b, conn, totalEvents, aggregates)
+
+		repo := NewEventRepository(conn)
+		rng := rand.New(rand.NewSource(1))
+
+		b.Run(layout.name, func(b *testing.B) {
+			for b.Loop() {
+				aggregateID := fmt.Sprintf("bench-%d", rng.Intn(aggregates))
+				events, err := repo.GetEventsAfterVersion(model.DefaultTenantID, aggregateID, 0)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(events) != eventsPerAggregate {
+					b.Fatalf("aggregate %s: got %d events, want %d", aggregateID, len(events), eventsPerAggregate)
+				}
+			}
+			b.ReportMetric(float64(totalEvents), "table_events")
+		})
+
+		conn.Close()
+	}
+}
+
+// createLegacyEventTable - Karşılaştırma için redesign öncesi events tablosu
+func createLegacyEventTable(conn driver.Conn) error {
+	return conn.Exec(context.Background(), `
+		CREATE TABLE IF NOT EXISTS events (
+			id String,
+			event_type String,
+			aggregate_id String,
+			payload String,
+			timestamp DateTime64(3),
+			version UInt32,
+			INDEX idx_event_type event_type TYPE minmax GRANULARITY 4,
+			INDEX idx_aggregate_id aggregate_id TYPE minmax GRANULARITY 4,
+			INDEX idx_timestamp timestamp TYPE minmax GRANULARITY 1
+		) ENGINE = MergeTree()
+		ORDER BY (timestamp, id)
+		PARTITION BY toYYYYMM(timestamp)
+		SETTINGS index_granularity = 8192
+	`)
+}
+
+func openBenchConn(b *testing.B, addr, database string) driver.Conn {
+	b.Helper()
+
+	admin, err := clickhouse.Open(&clickhouse.Options{
+		Addr: []string{addr},
+		Auth: clickhouse.Auth{
+			Username: os.Getenv("CLICKHOUSE_BENCH_USER"),
+			Password: os.Getenv("CLICKHOUSE_BENCH_PASSWORD"),
+		},
+	})
+	if err != nil {
+		b.Fatalf("connect: %v", err)
+	}
+	if err := admin.Exec(context.Background(), "CREATE DATABASE IF NOT EXISTS "+database); err != nil {
+		b.Fatalf("create database: %v", err)
+	}
+	admin.Close()
+
+	conn, err := clickhouse.Open(&clickhouse.Options{
+		Addr: []string{addr},
+		Auth: clickhouse.Auth{
+			Database: database,
+			Username: os.Getenv("CLICKHOUSE_BENCH_USER"),
+			Password: os.Getenv("CLICKHOUSE_BENCH_PASSWORD"),
+		},
+		Settings: clickhouse.Settings{
+			"max_execution_time": 0,
+		},
+	})
+	if err != nil {
+		b.Fatalf("connect %s: %v", database, err)
+	}
+	return conn
+}
+
+// seedBenchEvents - Her aggregate'in event'leri yaklaşık bir yıla ve tüm partition'lara dağılır
+func seedBenchEvents(b *testing.B, conn driver.Conn, totalEvents, aggregates int) {
+	b.Helper()
+	ctx := context.Background()
+
+	var count uint64
+	if err := conn.QueryRow(ctx, "SELECT count() FROM events").Scan(&count); err != nil {
+		b.Fatalf("count: %v", err)
+	}
+	if count == uint64(totalEvents) {
+		return
+	}
+	if err := conn.Exec(ctx, "TRUNCATE TABLE events"); err != nil {
+		b.Fatalf("truncate: %v", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO events (id, event_type, aggregate_id, payload, timestamp, version)
+		SELECT
+			toString(generateUUIDv4(number)),
+			['user.created', 'user.login', 'user.email.changed'][number %% 3 + 1],
+			concat('bench-', toString(number %% %[2]d)),
+			concat('{"n":', toString(number), '}'),
+			toDateTime64('2024-01-01 00:00:00', 3) + toIntervalSecond(intDiv(number * 31536000, %[1]d)),
+			toUInt32(intDiv(number, %[2]d) + 1)
+		FROM numbers(%[1]d)
+	`, totalEvents, aggregates)
+	if err := conn.Exec(ctx, query); err != nil {
+		b.Fatalf("seed: %v", err)
+	}
+	if err := conn.Exec(ctx, "OPTIMIZE TABLE events FINAL"); err != nil {
+		b.Fatalf("optimize: %v", err)
+	}
+}
+
+func benchEnvInt(b *testing.B, key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		b.Fatalf("%s must be a positive integer, got %q", key, value)
+	}
+	return n
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// KeyRepository - ClickHouse KeyStore implementasyonu
+// Anahtarlar events tablosundan ayrı data_keys tablosunda tutulur; yok etme işlemi
+// senkron mutation ile part'ları yeniden yazar, böylece anahtar diskte de kalmaz
+type KeyRepository struct {
+	conn driver.Conn
+}
+
+func NewKeyRepository(conn driver.Conn) *KeyRepository {
+	return &KeyRepository{conn: conn}
+}
+
+// CreateTable - data_keys tablosunu oluşturur
+// destroyed = 1 satırlar tombstone'dur: unutulan aggregate için tekrar anahtar üretilmez
+func (r *KeyRepository) CreateTable() error {
+	ctx := context.Background()
+	query := `
+		CREATE TABLE IF NOT EXISTS data_keys (
+			aggregate_id String,
+			data_key String,
+			created_at DateTime64(3),
+			destroyed UInt8 DEFAULT 0
+		) ENGINE = MergeTree()
+		ORDER BY aggregate_id
+	`
+	return r.conn.Exec(ctx, query)
+}
+
+func (r *KeyRepository) GetKey(aggregateID string) ([]byte, error) {
+	ctx := context.Background()
+
+	rows, err := r.conn.Query(ctx, `
+		SELECT data_key, destroyed FROM data_keys
+		WHERE aggregate_id = ?
+		ORDER BY destroyed DESC
+		LIMIT 1
+	`, aggregateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data key: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows error: %w", err)
+		}
+		return nil, ErrKeyNotFound
+	}
+
+	var key string
+	var destroyed uint8
+	if err := rows.Scan(&key, &destroyed); err != nil {
+		return nil, fmt.Errorf("failed to scan data key: %w", err)
+	}
+	if destroyed == 1 {
+		return nil, ErrKeyDestroyed
+	}
+
+	return []byte(key), nil
+}
+
+// GetOrCreateKey - Yazmalar event service'te tek writer ile yapıldığı için
+// okuma + insert arasında başka bir anahtar oluşturulmaz
+func (r *KeyRepository) GetOrCreateKey(aggregateID string, newKey []byte) ([]byte, error) {
+	key, err := r.GetKey(aggregateID)
+	if err != ErrKeyNotFound {
+		return key, err
+	}
+
+	ctx := context.Background()
+	if err := r.conn.Exec(ctx, `
+		INSERT INTO data_keys (aggregate_id, data_key, created_at, destroyed)
+		VALUES (?, ?, ?, 0)
+	`, aggregateID, string(newKey), time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to save data key: %w", err)
+	}
+
+	return newKey, nil
+}
+
+// DestroyKey - Anahtarı siler; mutation bitene kadar bekler (mutations_sync = 2)
+func (r *KeyRepository) DestroyKey(aggregateID string) error {
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithSettings(clickhouse.Settings{
+		"mutations_sync": 2,
+	}))
+
+	var count uint64
+	if err := r.conn.QueryRow(ctx, "SELECT count() FROM data_keys WHERE aggregate_id = ?", aggregateID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check data key: %w", err)
+	}
+
+	// Anahtarı hiç olmamış (şifreli event'i olmayan) aggregate'ler için sadece tombstone yazılır
+	if count == 0 {
+		if err := r.conn.Exec(ctx, `
+			INSERT INTO data_keys (aggregate_id, data_key, created_at, destroyed)
+			VALUES (?, '', ?, 1)
+		`, aggregateID, time.Now()); err != nil {
+			return fmt.Errorf("failed to save key tombstone: %w", err)
+		}
+		return nil
+	}
+
+	if err := r.conn.Exec(ctx, `
+		ALTER TABLE data_keys UPDATE data_key = '', destroyed = 1
+		WHERE aggregate_id = ?
+	`, aggregateID); err != nil {
+		return fmt.Errorf("failed to destroy data key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// MemoryEventRepository - ClickHouse olmadan çalışmak (local/test) için in-memory EventStore
+// Event'ler insert sırasıyla tutulur; process kapanınca kaybolur
+type MemoryEventRepository struct {
+	mu     sync.RWMutex
+	events []*model.Event
+	byID   map[string]struct{}
+}
+
+func NewMemoryEventRepository() *MemoryEventRepository {
+	return &MemoryEventRepository{
+		byID: make(map[string]struct{}),
+	}
+}
+
+var _ EventStore = (*MemoryEventRepository)(nil)
+
+func (r *MemoryEventRepository) SaveEvent(event *model.Event) error {
+	return r.SaveEvents([]*model.Event{event})
+}
+
+// SaveEvents - Event'lerin kopyasını saklar (caller sonradan değiştirse de store etkilenmez)
+func (r *MemoryEventRepository) SaveEvents(events []*model.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, event := range events {
+		stored := *event
+		stored.TenantID = model.NormalizeTenantID(stored.TenantID)
+		stored.Category = model.NormalizeCategory(stored.Category, stored.AggregateType)
+		stored.AggregateType = model.NormalizeAggregateType(stored.AggregateType)
+		r.events = append(r.events, &stored)
+		r.byID[stored.ID] = struct{}{}
+	}
+	return nil
+}
+
+// GetEvents - ClickHouse implementasyonu ile aynı semantik: filtre + timestamp (FromPosition varsa position) ASC + limit/offset
+func (r *MemoryEventRepository) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []*model.Event
+	for _, event := range r.events {
+		if !filter.Matches(event) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+
+	// Event'ler position sırasıyla eklendiği için position sıralaması insert sırasıdır
+	if filter.FromPosition == 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return matched[i].Timestamp.Before(matched[j].Timestamp)
+		})
+	}
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(matched) {
+			return nil, nil
+		}
+		matched = matched[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	return copyEvents(matched), nil
+}
+
+func (r *MemoryEventRepository) StreamTail(tenantID, aggregateID string, fromVersion uint32) (*model.StreamTail, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID = model.NormalizeTenantID(tenantID)
+	var tail model.StreamTail
+	for _, event := range r.events {
+		if event.TenantID != tenantID || event.AggregateID != aggregateID || event.Version < fromVersion {
+			continue
+		}
+		tail.Events++
+		tail.Bytes += uint64(len(event.Payload))
+		if tail.FirstAt.IsZero() || event.Timestamp.Before(tail.FirstAt) {
+			tail.FirstAt = event.Timestamp
+		}
+	}
+	return &tail, nil
+}
+
+func (r *MemoryEventRepository) CountEvents(tenantID string) (uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID = model.NormalizeTenantID(tenantID)
+	var count uint64
+	for _, event := range r.events {
+		if model.NormalizeTenantID(event.TenantID) == tenantID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryEventRepository) GetLastPosition() (uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var position uint64
+	for _, event := range r.events {
+		if event.Position > position {
+			position = event.Position
+		}
+	}
+	return position, nil
+}
+
+func (r *MemoryEventRepository) GetLatestVersionForAggregate(aggregateID string) (uint32, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var version uint32
+	for _, event := range r.events {
+		if event.AggregateID == aggregateID && event.Version > version {
+			version = event.Version
+		}
+	}
+	return version, nil
+}
+
+func (r *MemoryEventRepository) GetEventsAfterVersion(tenantID, aggregateID string, afterVersion uint32) ([]*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID = model.NormalizeTenantID(tenantID)
+	var matched []*model.Event
+	for _, event := range r.events {
+		if event.AggregateID == aggregateID && event.Version > afterVersion && event.TenantID == tenantID {
+			matched = append(matched, event)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Version < matched[j].Version
+	})
+
+	return copyEvents(matched), nil
+}
+
+// ReadStream - Eşleşen event'lerin kopyasını alıp lock dışında fn'e iletir
+func (r *MemoryEventRepository) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	r.mu.RLock()
+	var matched []*model.Event
+	for _, event := range r.events {
+		if query.Matches(event) {
+			matched = append(matched, event)
+		}
+	}
+	matched = copyEvents(matched)
+	r.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if query.Direction == model.ReadBackward {
+			return matched[i].Version > matched[j].Version
+		}
+		return matched[i].Version < matched[j].Version
+	})
+	if query.MaxCount > 0 && query.MaxCount < len(matched) {
+		matched = matched[:query.MaxCount]
+	}
+
+	for _, event := range matched {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListStreams - Stream'leri event'lerden toplar; aggregate ID sırasıyla, AfterAggregateID'den sonrası
+func (r *MemoryEventRepository) ListStreams(query model.StreamListQuery) ([]*model.StreamInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID := model.NormalizeTenantID(query.TenantID)
+	streams := make(map[string]*model.StreamInfo)
+	for _, event := range r.events {
+		if event.TenantID != tenantID || event.AggregateID <= query.AfterAggregateID {
+			continue
+		}
+		if query.Category != "" && event.Category != query.Category {
+			continue
+		}
+		if query.AggregateType != "" && event.AggregateType != query.AggregateType {
+			continue
+		}
+
+		stream, ok := streams[event.AggregateID]
+		if !ok {
+			stream = &model.StreamInfo{
+				AggregateID:   event.AggregateID,
+				AggregateType: event.AggregateType,
+				Category:      event.Category,
+			}
+			streams[event.AggregateID] = stream
+		}
+		stream.EventCount++
+		if event.Version > stream.Version {
+			stream.Version = event.Version
+		}
+		if event.Timestamp.After(stream.LastEventAt) {
+			stream.LastEventAt = event.Timestamp
+		}
+	}
+
+	var result []*model.StreamInfo
+	for _, stream := range streams {
+		if !query.ActiveSince.IsZero() && stream.LastEventAt.Before(query.ActiveSince) {
+			continue
+		}
+		result = append(result, stream)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].AggregateID < result[j].AggregateID
+	})
+	if query.Limit > 0 && query.Limit < len(result) {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+// ListCategories - Tenant'ın event'i olan kategorileri (alfabetik)
+func (r *MemoryEventRepository) ListCategories(tenantID string) ([]*model.CategoryInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenantID = model.NormalizeTenantID(tenantID)
+	categories := make(map[string]*model.CategoryInfo)
+	types := make(map[string]map[string]bool)
+	streams := make(map[string]bool)
+	for _, event := range r.events {
+		if event.TenantID != tenantID {
+			continue
+		}
+
+		category, ok := categories[event.Category]
+		if !ok {
+			category = &model.CategoryInfo{Category: event.Category}
+			categories[event.Category] = category
+			types[event.Category] = make(map[string]bool)
+		}
+		category.Events++
+		if !streams[event.AggregateID] {
+			streams[event.AggregateID] = true
+			category.Streams++
+		}
+		if !types[event.Category][event.AggregateType] {
+			types[event.Category][event.AggregateType] = true
+			category.AggregateTypes = append(category.AggregateTypes, event.AggregateType)
+		}
+	}
+
+	var result []*model.CategoryInfo
+	for _, category := range categories {
+		sort.Strings(category.AggregateTypes)
+		result = append(result, category)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Category < result[j].Category
+	})
+	return result, nil
+}
+
+func (r *MemoryEventRepository) EventExists(eventID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.byID[eventID]
+	return ok, nil
+}
+
+func (r *MemoryEventRepository) FindExistingEventIDs(eventIDs []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing := make(map[string]bool)
+	for _, id := range eventIDs {
+		if _, ok := r.byID[id]; ok {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// copyEvents - Store'daki event'lerin dışarıya kopyasını verir
+func copyEvents(events []*model.Event) []*model.Event {
+	if len(events) == 0 {
+		return nil
+	}
+
+	copies := make([]*model.Event, len(events))
+	for i, event := range events {
+		c := *event
+		copies[i] = &c
+	}
+	return copies
+}
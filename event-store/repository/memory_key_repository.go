@@ -0,0 +1,58 @@
+package repository
+
+import "sync"
+
+// MemoryKeyRepository - In-memory KeyStore (process kapanınca anahtarlar, dolayısıyla
+// şifreli alanlar da kaybolur; sadece memory backend ile kullanılmalı)
+type MemoryKeyRepository struct {
+	mu        sync.RWMutex
+	keys      map[string][]byte
+	destroyed map[string]bool
+}
+
+func NewMemoryKeyRepository() *MemoryKeyRepository {
+	return &MemoryKeyRepository{
+		keys:      make(map[string][]byte),
+		destroyed: make(map[string]bool),
+	}
+}
+
+var _ KeyStore = (*MemoryKeyRepository)(nil)
+
+func (r *MemoryKeyRepository) GetKey(aggregateID string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.destroyed[aggregateID] {
+		return nil, ErrKeyDestroyed
+	}
+	key, ok := r.keys[aggregateID]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (r *MemoryKeyRepository) GetOrCreateKey(aggregateID string, newKey []byte) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.destroyed[aggregateID] {
+		return nil, ErrKeyDestroyed
+	}
+	if key, ok := r.keys[aggregateID]; ok {
+		return key, nil
+	}
+
+	r.keys[aggregateID] = newKey
+	return newKey, nil
+}
+
+func (r *MemoryKeyRepository) DestroyKey(aggregateID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, aggregateID)
+	r.destroyed[aggregateID] = true
+	return nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// MemorySnapshotRepository - In-memory SnapshotStore
+// Aggregate başına snapshot'lar version'a göre artan sırada tutulur
+type MemorySnapshotRepository struct {
+	mu        sync.RWMutex
+	snapshots map[snapshotKey][]*model.Snapshot
+}
+
+// snapshotKey - Snapshot'lar tenant ile birlikte anahtarlanır; başka tenant'ın listesine erişilemez
+type snapshotKey struct {
+	tenantID    string
+	aggregateID string
+}
+
+func newSnapshotKey(tenantID, aggregateID string) snapshotKey {
+	return snapshotKey{tenantID: model.NormalizeTenantID(tenantID), aggregateID: aggregateID}
+}
+
+func NewMemorySnapshotRepository() *MemorySnapshotRepository {
+	return &MemorySnapshotRepository{
+		snapshots: make(map[snapshotKey][]*model.Snapshot),
+	}
+}
+
+var _ SnapshotStore = (*MemorySnapshotRepository)(nil)
+
+// SaveSnapshot - Aynı version'da snapshot varsa yenisiyle değiştirir (ReplacingMergeTree davranışı)
+func (r *MemorySnapshotRepository) SaveSnapshot(snapshot *model.Snapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *snapshot
+	stored.TenantID = model.NormalizeTenantID(stored.TenantID)
+	key := newSnapshotKey(stored.TenantID, stored.AggregateID)
+
+	list := r.snapshots[key]
+	for i, existing := range list {
+		if existing.Version == snapshot.Version {
+			list[i] = &stored
+			return nil
+		}
+	}
+
+	list = append(list, &stored)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	r.snapshots[key] = list
+	return nil
+}
+
+func (r *MemorySnapshotRepository) GetLatestSnapshot(tenantID, aggregateID string) (*model.Snapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := r.snapshots[newSnapshotKey(tenantID, aggregateID)]
+	if len(list) == 0 {
+		return nil, fmt.Errorf("snapshot not found for aggregate %s", aggregateID)
+	}
+
+	snapshot := *list[len(list)-1]
+	return &snapshot, nil
+}
+
+func (r *MemorySnapshotRepository) GetSnapshotAtVersion(tenantID, aggregateID string, version uint32) (*model.Snapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := r.snapshots[newSnapshotKey(tenantID, aggregateID)]
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Version <= version {
+			snapshot := *list[i]
+			return &snapshot, nil
+		}
+	}
+
+	return nil, fmt.Errorf("snapshot not found for aggregate %s at version %d", aggregateID, version)
+}
+
+func (r *MemorySnapshotRepository) HasSnapshot(tenantID, aggregateID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.snapshots[newSnapshotKey(tenantID, aggregateID)]) > 0, nil
+}
+
+func (r *MemorySnapshotRepository) DeleteOldSnapshots(tenantID, aggregateID string, keepLastN int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := newSnapshotKey(tenantID, aggregateID)
+	list := r.snapshots[key]
+	if keepLastN < 0 {
+		keepLastN = 0
+	}
+	if len(list) > keepLastN {
+		r.snapshots[key] = list[len(list)-keepLastN:]
+	}
+	return nil
+}
+
+func (r *MemorySnapshotRepository) DeleteSnapshots(tenantID, aggregateID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.snapshots, newSnapshotKey(tenantID, aggregateID))
+	return nil
+}
+
+func (r *MemorySnapshotRepository) DeleteSnapshot(tenantID, aggregateID string, version uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := newSnapshotKey(tenantID, aggregateID)
+	list := r.snapshots[key]
+	for i, snapshot := range list {
+		if snapshot.Version == version {
+			r.snapshots[key] = append(list[:i:i], list[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: aggregate %s at version %d", ErrSnapshotNotFound, aggregateID, version)
+}
+
+func (r *MemorySnapshotRepository) ListSnapshots(tenantID, aggregateID string) ([]*model.SnapshotInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := r.snapshots[newSnapshotKey(tenantID, aggregateID)]
+	infos := make([]*model.SnapshotInfo, 0, len(list))
+	for _, snapshot := range list {
+		infos = append(infos, &model.SnapshotInfo{
+			ID:            snapshot.ID,
+			Version:       snapshot.Version,
+			AggregateType: snapshot.AggregateType,
+			StateVersion:  snapshot.StateVersion,
+			SizeBytes:     uint64(len(snapshot.State)),
+			CreatedAt:     snapshot.CreatedAt,
+		})
+	}
+	return infos, nil
+}
+
+func (r *MemorySnapshotRepository) PruneSnapshots(keepLastN int) (int, uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if keepLastN < 0 {
+		keepLastN = 0
+	}
+	var streams int
+	var deleted uint64
+	for key, list := range r.snapshots {
+		if len(list) > keepLastN {
+			streams++
+			deleted += uint64(len(list) - keepLastN)
+			r.snapshots[key] = list[len(list)-keepLastN:]
+		}
+	}
+	return streams, deleted, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// partitionIDPattern - events tablosu toYYYYMM(timestamp) ile bölündüğü için partition ID'leri YYYYMM'dir
+var partitionIDPattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+func validatePartition(partition string) error {
+	if !partitionIDPattern.MatchString(partition) {
+		return fmt.Errorf("invalid partition id %q (expected YYYYMM)", partition)
+	}
+	return nil
+}
+
+// ListPartitions - Aktif part'ı olan partition'lar (eskiden yeniye)
+func (r *EventRepository) ListPartitions() ([]EventPartition, error) {
+	ctx := context.Background()
+
+	rows, err := r.conn.Query(ctx, `
+		SELECT partition_id, sum(rows) FROM system.parts
+		WHERE database = currentDatabase() AND table = 'events' AND active
+		GROUP BY partition_id
+		ORDER BY partition_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []EventPartition
+	for rows.Next() {
+		var partition EventPartition
+		if err := rows.Scan(&partition.ID, &partition.Rows); err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+		partitions = append(partitions, partition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return partitions, nil
+}
+
+// CountPartition - Partition'daki satır sayısı (arşivden sonra, drop'tan önce tekrar kontrol için)
+func (r *EventRepository) CountPartition(partition string) (uint64, error) {
+	if err := validatePartition(partition); err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+	var count uint64
+	if err := r.conn.QueryRow(ctx, "SELECT count() FROM events WHERE _partition_id = ?", partition).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count partition %s: %w", partition, err)
+	}
+	return count, nil
+}
+
+// ReadPartition - Partition'ı satır satır okur; büyük partition'lar belleğe alınmaz
+func (r *EventRepository) ReadPartition(ctx context.Context, partition string, fn func(*model.Event) error) error {
+	if err := validatePartition(partition); err != nil {
+		return err
+	}
+
+	// Tek partition'ın tamamı okunurken bağlantının max_execution_time limiti aşılabilir
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+		"max_execution_time": 0,
+	}))
+
+	rows, err := r.conn.Query(ctx, "SELECT "+eventColumns+" FROM events WHERE _partition_id = ? ORDER BY position ASC", partition)
+	if err != nil {
+		return fmt.Errorf("failed to query partition %s: %w", partition, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event model.Event
+		if err := rows.Scan(eventFields(&event)...); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+
+	return nil
+}
+
+// DropPartition - Partition'ı siler (geri alınamaz; sadece arşivlendikten sonra çağrılmalı)
+func (r *EventRepository) DropPartition(partition string) error {
+	if err := validatePartition(partition); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	// ALTER sorgularında partition parametre olarak bağlanamadığı için ID doğrulanıp sorguya yazılır
+	if err := r.conn.Exec(ctx, fmt.Sprintf("ALTER TABLE events DROP PARTITION ID '%s'", partition)); err != nil {
+		return fmt.Errorf("failed to drop partition %s: %w", partition, err)
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgresEventRepository - Postgres EventStore implementasyonu
+type PostgresEventRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresEventRepository(db *sql.DB) *PostgresEventRepository {
+	return &PostgresEventRepository{db: db}
+}
+
+var _ EventStore = (*PostgresEventRepository)(nil)
+
+func (r *PostgresEventRepository) SaveEvent(event *model.Event) error {
+	return r.SaveEvents([]*model.Event{event})
+}
+
+// SaveEvents - Event'leri tek transaction içinde kaydeder (batch ya tamamen yazılır ya hiç)
+func (r *PostgresEventRepository) SaveEvents(events []*model.Event) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO events (` + postgresEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	for _, event := range events {
+		if _, err := tx.ExecContext(ctx, query, eventValues(event)...); err != nil {
+			return fmt.Errorf("failed to save event: %w", translatePostgresError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit events: %w", translatePostgresError(err))
+	}
+
+	return nil
+}
+
+func (r *PostgresEventRepository) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	addCondition("tenant_id = $%d", model.NormalizeTenantID(filter.TenantID))
+
+	if filter.EventType != "" {
+		addCondition("event_type = $%d", filter.EventType)
+	}
+
+	if filter.AggregateID != "" {
+		addCondition("aggregate_id = $%d", filter.AggregateID)
+	}
+
+	if !filter.StartTime.IsZero() {
+		addCondition("timestamp >= $%d", filter.StartTime)
+	}
+
+	if !filter.EndTime.IsZero() {
+		addCondition("timestamp <= $%d", filter.EndTime)
+	}
+
+	if filter.FromPosition > 0 {
+		addCondition("global_position >= $%d", filter.FromPosition)
+	}
+
+	for _, column := range metadataConditions(filter) {
+		addCondition(column.name+" = $%d", column.value)
+	}
+
+	query := "SELECT " + postgresEventColumns + " FROM events WHERE " + strings.Join(conditions, " AND ")
+	if filter.FromPosition > 0 {
+		query += " ORDER BY global_position ASC"
+	} else {
+		query += " ORDER BY timestamp ASC, global_position ASC"
+	}
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPostgresEvents(rows)
+}
+
+func (r *PostgresEventRepository) CountEvents(tenantID string) (uint64, error) {
+	ctx := context.Background()
+	var count uint64
+	if err := r.db.QueryRowContext(ctx, "SELECT count(*) FROM events WHERE tenant_id = $1", model.NormalizeTenantID(tenantID)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresEventRepository) StreamTail(tenantID, aggregateID string, fromVersion uint32) (*model.StreamTail, error) {
+	ctx := context.Background()
+	query := `
+		SELECT count(*), COALESCE(sum(octet_length(payload::text)), 0), min(timestamp)
+		FROM events
+		WHERE aggregate_id = $1 AND tenant_id = $2 AND version >= $3
+	`
+
+	var tail model.StreamTail
+	var first sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID), fromVersion).Scan(&tail.Events, &tail.Bytes, &first); err != nil {
+		return nil, fmt.Errorf("failed to measure stream tail: %w", err)
+	}
+	if first.Valid {
+		tail.FirstAt = first.Time
+	}
+	return &tail, nil
+}
+
+// GetLastPosition - Kaydedilmiş en büyük global position (boş store için 0)
+func (r *PostgresEventRepository) GetLastPosition() (uint64, error) {
+	ctx := context.Background()
+	var position uint64
+
+	if err := r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(global_position), 0) FROM events").Scan(&position); err != nil {
+		return 0, fmt.Errorf("failed to get last position: %w", err)
+	}
+
+	return position, nil
+}
+
+func (r *PostgresEventRepository) GetLatestVersionForAggregate(aggregateID string) (uint32, error) {
+	ctx := context.Background()
+	var version uint32
+
+	query := "SELECT COALESCE(MAX(version), 0) FROM events WHERE aggregate_id = $1"
+	if err := r.db.QueryRowContext(ctx, query, aggregateID).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get latest version: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetEventsAfterVersion - Belirli bir version'dan sonraki event'leri getirir
+func (r *PostgresEventRepository) GetEventsAfterVersion(tenantID, aggregateID string, afterVersion uint32) ([]*model.Event, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + postgresEventColumns + `
+		FROM events
+		WHERE aggregate_id = $1 AND version > $2 AND tenant_id = $3
+		ORDER BY version ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, aggregateID, afterVersion, model.NormalizeTenantID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events after version: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPostgresEvents(rows)
+}
+
+func (r *PostgresEventRepository) EventExists(eventID string) (bool, error) {
+	ctx := context.Background()
+	var exists bool
+
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)", eventID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check event existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *PostgresEventRepository) FindExistingEventIDs(eventIDs []string) (map[string]bool, error) {
+	ctx := context.Background()
+	existing := make(map[string]bool)
+	if len(eventIDs) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM events WHERE id = ANY($1)", eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query existing event ids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan event id: %w", err)
+		}
+		existing[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return existing, nil
+}
+
+// ReadStream - Aggregate stream'ini satır satır okuyup fn'e iletir
+func (r *PostgresEventRepository) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	conditions := []string{"aggregate_id = $1", "tenant_id = $2"}
+	args := []interface{}{query.AggregateID, model.NormalizeTenantID(query.TenantID)}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if query.FromVersion > 0 {
+		addCondition("version >= $%d", query.FromVersion)
+	}
+	if query.ToVersion > 0 {
+		addCondition("version <= $%d", query.ToVersion)
+	}
+	if len(query.EventTypes) > 0 {
+		addCondition("event_type = ANY($%d)", query.EventTypes)
+	}
+	if !query.EndTime.IsZero() {
+		addCondition("timestamp <= $%d", query.EndTime)
+	}
+
+	sqlQuery := "SELECT " + postgresEventColumns + " FROM events WHERE " +
+		strings.Join(conditions, " AND ")
+	if query.Direction == model.ReadBackward {
+		sqlQuery += " ORDER BY version DESC"
+	} else {
+		sqlQuery += " ORDER BY version ASC"
+	}
+	if query.MaxCount > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT %d", query.MaxCount)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query stream: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event model.Event
+		if err := rows.Scan(eventFields(&event)...); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+
+	return nil
+}
+
+// ListStreams - Stream'leri aggregate_id'ye göre gruplayarak listeler (idx_events_tenant_category)
+func (r *PostgresEventRepository) ListStreams(query model.StreamListQuery) ([]*model.StreamInfo, error) {
+	ctx := context.Background()
+
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	addCondition("tenant_id = $%d", model.NormalizeTenantID(query.TenantID))
+	if query.Category != "" {
+		addCondition("category = $%d", query.Category)
+	}
+	if query.AggregateType != "" {
+		addCondition("aggregate_type = $%d", query.AggregateType)
+	}
+	if query.AfterAggregateID != "" {
+		addCondition("aggregate_id > $%d", query.AfterAggregateID)
+	}
+
+	sqlQuery := `
+		SELECT aggregate_id, MIN(aggregate_type), MIN(category), MAX(version), COUNT(*), MAX(timestamp)
+		FROM events
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY aggregate_id`
+	if !query.ActiveSince.IsZero() {
+		args = append(args, query.ActiveSince)
+		sqlQuery += fmt.Sprintf(" HAVING MAX(timestamp) >= $%d", len(args))
+	}
+	sqlQuery += " ORDER BY aggregate_id ASC"
+	if query.Limit > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list streams: %w", err)
+	}
+	defer rows.Close()
+
+	var streams []*model.StreamInfo
+	for rows.Next() {
+		var stream model.StreamInfo
+		if err := rows.Scan(&stream.AggregateID, &stream.AggregateType, &stream.Category,
+			&stream.Version, &stream.EventCount, &stream.LastEventAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stream: %w", err)
+		}
+		streams = append(streams, &stream)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return streams, nil
+}
+
+// ListCategories - Tenant'ın kategorileri (aggregate tipleri virgülle birleştirilip ayrılır; tip adlarında virgül olamaz)
+func (r *PostgresEventRepository) ListCategories(tenantID string) ([]*model.CategoryInfo, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT category, string_agg(DISTINCT aggregate_type, ',' ORDER BY aggregate_type),
+			COUNT(DISTINCT aggregate_id), COUNT(*)
+		FROM events
+		WHERE tenant_id = $1
+		GROUP BY category
+		ORDER BY category ASC
+	`, model.NormalizeTenantID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*model.CategoryInfo
+	for rows.Next() {
+		var category model.CategoryInfo
+		var types string
+		if err := rows.Scan(&category.Category, &types, &category.Streams, &category.Events); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		category.AggregateTypes = strings.Split(types, ",")
+		categories = append(categories, &category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return categories, nil
+}
+
+// postgresEventColumns - eventColumns'ın Postgres karşılığı (position kolonu global_position)
+const postgresEventColumns = "id, event_type, aggregate_id, payload, timestamp, version, global_position, schema_version, " +
+	"correlation_id, causation_id, actor_id, source_service, tenant_id, aggregate_type, category"
+
+func scanPostgresEvents(rows *sql.Rows) ([]*model.Event, error) {
+	var events []*model.Event
+	for rows.Next() {
+		var event model.Event
+		if err := rows.Scan(eventFields(&event)...); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return events, nil
+}
+
+// translatePostgresError - Unique constraint ihlallerini repository hatalarına çevirir
+func translatePostgresError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+
+	switch pgErr.ConstraintName {
+	case "events_aggregate_version_key":
+		return fmt.Errorf("%w: %s", ErrVersionConflict, pgErr.Detail)
+	case "events_id_key":
+		return fmt.Errorf("%w: %s", ErrDuplicateEventID, pgErr.Detail)
+	default:
+		return err
+	}
+}
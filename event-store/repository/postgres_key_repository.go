@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PostgresKeyRepository - Postgres KeyStore implementasyonu
+// Yok edilen anahtarın satırı tombstone olarak kalır (data_key NULL, destroyed_at dolu)
+type PostgresKeyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresKeyRepository(db *sql.DB) *PostgresKeyRepository {
+	return &PostgresKeyRepository{db: db}
+}
+
+var _ KeyStore = (*PostgresKeyRepository)(nil)
+
+func (r *PostgresKeyRepository) CreateTable() error {
+	ctx := context.Background()
+	query := `
+		CREATE TABLE IF NOT EXISTS data_keys (
+			aggregate_id TEXT PRIMARY KEY,
+			data_key BYTEA,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			destroyed_at TIMESTAMPTZ
+		)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create data_keys table: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresKeyRepository) GetKey(aggregateID string) ([]byte, error) {
+	ctx := context.Background()
+
+	var key []byte
+	var destroyedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx,
+		"SELECT data_key, destroyed_at FROM data_keys WHERE aggregate_id = $1",
+		aggregateID,
+	).Scan(&key, &destroyedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data key: %w", err)
+	}
+	if destroyedAt.Valid {
+		return nil, ErrKeyDestroyed
+	}
+
+	return key, nil
+}
+
+func (r *PostgresKeyRepository) GetOrCreateKey(aggregateID string, newKey []byte) ([]byte, error) {
+	ctx := context.Background()
+
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO data_keys (aggregate_id, data_key)
+		VALUES ($1, $2)
+		ON CONFLICT (aggregate_id) DO NOTHING
+	`, aggregateID, newKey); err != nil {
+		return nil, fmt.Errorf("failed to save data key: %w", err)
+	}
+
+	return r.GetKey(aggregateID)
+}
+
+func (r *PostgresKeyRepository) DestroyKey(aggregateID string) error {
+	ctx := context.Background()
+
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO data_keys (aggregate_id, data_key, destroyed_at)
+		VALUES ($1, NULL, now())
+		ON CONFLICT (aggregate_id) DO UPDATE
+		SET data_key = NULL, destroyed_at = COALESCE(data_keys.destroyed_at, now())
+	`, aggregateID); err != nil {
+		return fmt.Errorf("failed to destroy data key: %w", err)
+	}
+
+	return nil
+}
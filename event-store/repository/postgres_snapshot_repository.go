@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// PostgresSnapshotRepository - Postgres SnapshotStore implementasyonu
+// Snapshot'lar event'lerden ayrı kendi tablolarında tutulur
+type PostgresSnapshotRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSnapshotRepository(db *sql.DB) *PostgresSnapshotRepository {
+	return &PostgresSnapshotRepository{db: db}
+}
+
+var _ SnapshotStore = (*PostgresSnapshotRepository)(nil)
+
+// CreateTable - Snapshot tablosunu oluşturur (eski tablolara sonradan eklenen kolonlar eklenir)
+func (r *PostgresSnapshotRepository) CreateTable() error {
+	ctx := context.Background()
+	query := `
+		CREATE TABLE IF NOT EXISTS snapshots (
+			id TEXT PRIMARY KEY,
+			aggregate_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			state JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			aggregate_type TEXT NOT NULL DEFAULT '',
+			state_version INTEGER NOT NULL DEFAULT 0,
+			CONSTRAINT snapshots_aggregate_version_key UNIQUE (aggregate_id, version)
+		);
+		ALTER TABLE snapshots ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+		ALTER TABLE snapshots ADD COLUMN IF NOT EXISTS aggregate_type TEXT NOT NULL DEFAULT '';
+		ALTER TABLE snapshots ADD COLUMN IF NOT EXISTS state_version INTEGER NOT NULL DEFAULT 0;
+	`
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
+
+// SaveSnapshot - Snapshot'ı kaydeder; aynı version'da snapshot varsa günceller
+func (r *PostgresSnapshotRepository) SaveSnapshot(snapshot *model.Snapshot) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO snapshots (` + snapshotColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (aggregate_id, version) DO UPDATE
+		SET id = EXCLUDED.id, state = EXCLUDED.state, created_at = EXCLUDED.created_at,
+			aggregate_type = EXCLUDED.aggregate_type, state_version = EXCLUDED.state_version
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, snapshotValues(snapshot)...); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestSnapshot - Aggregate için en son snapshot'ı getirir
+func (r *PostgresSnapshotRepository) GetLatestSnapshot(tenantID, aggregateID string) (*model.Snapshot, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM snapshots
+		WHERE aggregate_id = $1 AND tenant_id = $2
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	var snapshot model.Snapshot
+	err := r.db.QueryRowContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID)).Scan(snapshotFields(&snapshot)...)
+
+	if err != nil {
+		return nil, fmt.Errorf("snapshot not found for aggregate %s: %w", aggregateID, err)
+	}
+
+	return &snapshot, nil
+}
+
+// GetSnapshotAtVersion - Belirli bir version'daki snapshot'ı getirir
+func (r *PostgresSnapshotRepository) GetSnapshotAtVersion(tenantID, aggregateID string, version uint32) (*model.Snapshot, error) {
+	ctx := context.Background()
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM snapshots
+		WHERE aggregate_id = $1 AND tenant_id = $2 AND version <= $3
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	var snapshot model.Snapshot
+	err := r.db.QueryRowContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID), version).Scan(snapshotFields(&snapshot)...)
+
+	if err != nil {
+		return nil, fmt.Errorf("snapshot not found for aggregate %s at version %d: %w", aggregateID, version, err)
+	}
+
+	return &snapshot, nil
+}
+
+// HasSnapshot - Aggregate için snapshot olup olmadığını kontrol eder
+func (r *PostgresSnapshotRepository) HasSnapshot(tenantID, aggregateID string) (bool, error) {
+	ctx := context.Background()
+	var exists bool
+
+	query := "SELECT EXISTS (SELECT 1 FROM snapshots WHERE aggregate_id = $1 AND tenant_id = $2)"
+	if err := r.db.QueryRowContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID)).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// DeleteOldSnapshots - Son N snapshot dışındakileri siler
+func (r *PostgresSnapshotRepository) DeleteOldSnapshots(tenantID, aggregateID string, keepLastN int) error {
+	ctx := context.Background()
+	query := `
+		DELETE FROM snapshots
+		WHERE aggregate_id = $1 AND tenant_id = $2 AND version NOT IN (
+			SELECT version FROM snapshots
+			WHERE aggregate_id = $1 AND tenant_id = $2
+			ORDER BY version DESC
+			LIMIT $3
+		)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID), keepLastN)
+	return err
+}
+
+// DeleteSnapshots - Aggregate'in tüm snapshot'larını siler
+func (r *PostgresSnapshotRepository) DeleteSnapshots(tenantID, aggregateID string) error {
+	ctx := context.Background()
+
+	query := "DELETE FROM snapshots WHERE aggregate_id = $1 AND tenant_id = $2"
+	if _, err := r.db.ExecContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID)); err != nil {
+		return fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+	return nil
+}
+
+// DeleteSnapshot - Tek bir version'daki snapshot'ı siler
+func (r *PostgresSnapshotRepository) DeleteSnapshot(tenantID, aggregateID string, version uint32) error {
+	ctx := context.Background()
+
+	query := "DELETE FROM snapshots WHERE aggregate_id = $1 AND tenant_id = $2 AND version = $3"
+	result, err := r.db.ExecContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID), version)
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("%w: aggregate %s at version %d", ErrSnapshotNotFound, aggregateID, version)
+	}
+	return nil
+}
+
+// ListSnapshots - Aggregate'in snapshot'ları; state okunmaz, sadece boyutu hesaplanır
+func (r *PostgresSnapshotRepository) ListSnapshots(tenantID, aggregateID string) ([]*model.SnapshotInfo, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, version, aggregate_type, state_version, octet_length(state::text), created_at
+		FROM snapshots
+		WHERE aggregate_id = $1 AND tenant_id = $2
+		ORDER BY version ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, aggregateID, model.NormalizeTenantID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var infos []*model.SnapshotInfo
+	for rows.Next() {
+		var info model.SnapshotInfo
+		if err := rows.Scan(&info.ID, &info.Version, &info.AggregateType, &info.StateVersion, &info.SizeBytes, &info.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		infos = append(infos, &info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return infos, nil
+}
+
+// PruneSnapshots - Her stream'in en yeni keepLastN snapshot'ı dışındakileri tek DELETE ile siler
+func (r *PostgresSnapshotRepository) PruneSnapshots(keepLastN int) (int, uint64, error) {
+	ctx := context.Background()
+	query := `
+		WITH deleted AS (
+			DELETE FROM snapshots
+			WHERE id IN (
+				SELECT id FROM (
+					SELECT id, row_number() OVER (PARTITION BY tenant_id, aggregate_id ORDER BY version DESC) AS rank
+					FROM snapshots
+				) ranked
+				WHERE rank > $1
+			)
+			RETURNING tenant_id, aggregate_id
+		)
+		SELECT count(*), COALESCE(sum(snapshots), 0)
+		FROM (SELECT count(*) AS snapshots FROM deleted GROUP BY tenant_id, aggregate_id) pruned
+	`
+
+	var streams int
+	var deleted uint64
+	if err := r.db.QueryRowContext(ctx, query, keepLastN).Scan(&streams, &deleted); err != nil {
+		return 0, 0, fmt.Errorf("failed to prune snapshots: %w", err)
+	}
+	return streams, deleted, nil
+}
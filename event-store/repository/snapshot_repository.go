@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/eyupaydin41/event-store/model"
 )
@@ -17,6 +18,7 @@ func NewSnapshotRepository(conn driver.Conn) *SnapshotRepository {
 }
 
 // CreateTable - Snapshot tablosunu oluşturur
+// tenant_id sonradan eklendi; eski tablolara ADD COLUMN ile eklenir ve mevcut satırlar 'default' olur
 func (r *SnapshotRepository) CreateTable() error {
 	ctx := context.Background()
 	query := `
@@ -25,28 +27,70 @@ func (r *SnapshotRepository) CreateTable() error {
 			aggregate_id String,
 			version UInt32,
 			state String,
-			created_at DateTime
+			created_at DateTime,
+			tenant_id LowCardinality(String) DEFAULT 'default',
+			aggregate_type LowCardinality(String) DEFAULT '',
+			state_version UInt32 DEFAULT 0
 		) ENGINE = ReplacingMergeTree(created_at)
 		ORDER BY (aggregate_id, version)
 	`
-	return r.conn.Exec(ctx, query)
+	if err := r.conn.Exec(ctx, query); err != nil {
+		return err
+	}
+
+	// Sonradan eklenen kolonlar; eski snapshot'lar tag'siz kalır ve yüklenirken yok sayılır
+	for _, column := range []string{
+		"tenant_id LowCardinality(String) DEFAULT 'default'",
+		"aggregate_type LowCardinality(String) DEFAULT ''",
+		"state_version UInt32 DEFAULT 0",
+	} {
+		if err := r.conn.Exec(ctx, "ALTER TABLE snapshots ADD COLUMN IF NOT EXISTS "+column); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// SaveSnapshot - Snapshot'ı kaydeder
-func (r *SnapshotRepository) SaveSnapshot(snapshot *model.Snapshot) error {
-	ctx := context.Background()
-	query := `
-		INSERT INTO snapshots (id, aggregate_id, version, state, created_at)
-		VALUES (?, ?, ?, ?, ?)
-	`
+// snapshotColumns - snapshots tablosundan okunan/yazılan kolonlar (snapshotFields ile aynı sırada)
+const snapshotColumns = "id, aggregate_id, version, state, created_at, tenant_id, aggregate_type, state_version"
 
-	if err := r.conn.Exec(ctx, query,
+// snapshotFields - Snapshot'ın snapshotColumns sırasındaki alanları (Scan için pointer'lar)
+func snapshotFields(snapshot *model.Snapshot) []interface{} {
+	return []interface{}{
+		&snapshot.ID,
+		&snapshot.AggregateID,
+		&snapshot.Version,
+		&snapshot.State,
+		&snapshot.CreatedAt,
+		&snapshot.TenantID,
+		&snapshot.AggregateType,
+		&snapshot.StateVersion,
+	}
+}
+
+// snapshotValues - Snapshot'ın snapshotColumns sırasındaki değerleri (INSERT için)
+func snapshotValues(snapshot *model.Snapshot) []interface{} {
+	return []interface{}{
 		snapshot.ID,
 		snapshot.AggregateID,
 		snapshot.Version,
 		snapshot.State,
 		snapshot.CreatedAt,
-	); err != nil {
+		model.NormalizeTenantID(snapshot.TenantID),
+		snapshot.AggregateType,
+		snapshot.StateVersion,
+	}
+}
+
+// SaveSnapshot - Snapshot'ı kaydeder
+func (r *SnapshotRepository) SaveSnapshot(snapshot *model.Snapshot) error {
+	ctx := context.Background()
+	query := `
+		INSERT INTO snapshots (` + snapshotColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	if err := r.conn.Exec(ctx, query, snapshotValues(snapshot)...); err != nil {
 		return fmt.Errorf("failed to save snapshot: %w", err)
 	}
 
@@ -54,24 +98,19 @@ func (r *SnapshotRepository) SaveSnapshot(snapshot *model.Snapshot) error {
 }
 
 // GetLatestSnapshot - Aggregate için en son snapshot'ı getirir
-func (r *SnapshotRepository) GetLatestSnapshot(aggregateID string) (*model.Snapshot, error) {
+// FINAL: aynı version'da yeniden alınmış snapshot merge'den önce eskisiyle birlikte durur; en yenisi okunur
+func (r *SnapshotRepository) GetLatestSnapshot(tenantID, aggregateID string) (*model.Snapshot, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, aggregate_id, version, state, created_at
-		FROM snapshots
-		WHERE aggregate_id = ?
+		SELECT ` + snapshotColumns + `
+		FROM snapshots FINAL
+		WHERE aggregate_id = ? AND tenant_id = ?
 		ORDER BY version DESC
 		LIMIT 1
 	`
 
 	var snapshot model.Snapshot
-	err := r.conn.QueryRow(ctx, query, aggregateID).Scan(
-		&snapshot.ID,
-		&snapshot.AggregateID,
-		&snapshot.Version,
-		&snapshot.State,
-		&snapshot.CreatedAt,
-	)
+	err := r.conn.QueryRow(ctx, query, aggregateID, model.NormalizeTenantID(tenantID)).Scan(snapshotFields(&snapshot)...)
 
 	if err != nil {
 		return nil, fmt.Errorf("snapshot not found for aggregate %s: %w", aggregateID, err)
@@ -81,24 +120,18 @@ func (r *SnapshotRepository) GetLatestSnapshot(aggregateID string) (*model.Snaps
 }
 
 // GetSnapshotAtVersion - Belirli bir version'daki snapshot'ı getirir
-func (r *SnapshotRepository) GetSnapshotAtVersion(aggregateID string, version uint32) (*model.Snapshot, error) {
+func (r *SnapshotRepository) GetSnapshotAtVersion(tenantID, aggregateID string, version uint32) (*model.Snapshot, error) {
 	ctx := context.Background()
 	query := `
-		SELECT id, aggregate_id, version, state, created_at
-		FROM snapshots
-		WHERE aggregate_id = ? AND version <= ?
+		SELECT ` + snapshotColumns + `
+		FROM snapshots FINAL
+		WHERE aggregate_id = ? AND tenant_id = ? AND version <= ?
 		ORDER BY version DESC
 		LIMIT 1
 	`
 
 	var snapshot model.Snapshot
-	err := r.conn.QueryRow(ctx, query, aggregateID, version).Scan(
-		&snapshot.ID,
-		&snapshot.AggregateID,
-		&snapshot.Version,
-		&snapshot.State,
-		&snapshot.CreatedAt,
-	)
+	err := r.conn.QueryRow(ctx, query, aggregateID, model.NormalizeTenantID(tenantID), version).Scan(snapshotFields(&snapshot)...)
 
 	if err != nil {
 		return nil, fmt.Errorf("snapshot not found for aggregate %s at version %d: %w", aggregateID, version, err)
@@ -108,12 +141,12 @@ func (r *SnapshotRepository) GetSnapshotAtVersion(aggregateID string, version ui
 }
 
 // HasSnapshot - Aggregate için snapshot olup olmadığını kontrol eder
-func (r *SnapshotRepository) HasSnapshot(aggregateID string) (bool, error) {
+func (r *SnapshotRepository) HasSnapshot(tenantID, aggregateID string) (bool, error) {
 	ctx := context.Background()
 	var count uint64
 
-	query := "SELECT count() FROM snapshots WHERE aggregate_id = ?"
-	err := r.conn.QueryRow(ctx, query, aggregateID).Scan(&count)
+	query := "SELECT count() FROM snapshots WHERE aggregate_id = ? AND tenant_id = ?"
+	err := r.conn.QueryRow(ctx, query, aggregateID, model.NormalizeTenantID(tenantID)).Scan(&count)
 
 	if err != nil {
 		return false, err
@@ -123,19 +156,124 @@ func (r *SnapshotRepository) HasSnapshot(aggregateID string) (bool, error) {
 }
 
 // DeleteOldSnapshots - Belirli bir version'dan eski snapshot'ları siler
-func (r *SnapshotRepository) DeleteOldSnapshots(aggregateID string, keepLastN int) error {
+func (r *SnapshotRepository) DeleteOldSnapshots(tenantID, aggregateID string, keepLastN int) error {
 	ctx := context.Background()
+	tenantID = model.NormalizeTenantID(tenantID)
 
 	// ClickHouse'da DELETE yerine ALTER TABLE ... DELETE kullanılır
+	// Aynı version'da birleşmemiş (merge bekleyen) satırlar tutulacak slotları doldurmasın diye DISTINCT
 	query := `
 		ALTER TABLE snapshots DELETE
-		WHERE aggregate_id = ? AND version NOT IN (
-			SELECT version FROM snapshots
-			WHERE aggregate_id = ?
+		WHERE aggregate_id = ? AND tenant_id = ? AND version NOT IN (
+			SELECT DISTINCT version FROM snapshots
+			WHERE aggregate_id = ? AND tenant_id = ?
 			ORDER BY version DESC
 			LIMIT ?
 		)
 	`
 
-	return r.conn.Exec(ctx, query, aggregateID, aggregateID, keepLastN)
+	return r.conn.Exec(ctx, query, aggregateID, tenantID, aggregateID, tenantID, keepLastN)
+}
+
+// DeleteSnapshots - Aggregate'in tüm snapshot'larını siler
+// Mutation tamamlanana kadar beklenir; aksi halde silinecek state bir süre daha okunabilir
+func (r *SnapshotRepository) DeleteSnapshots(tenantID, aggregateID string) error {
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithSettings(clickhouse.Settings{
+		"mutations_sync": 2,
+	}))
+
+	query := "ALTER TABLE snapshots DELETE WHERE aggregate_id = ? AND tenant_id = ?"
+	if err := r.conn.Exec(ctx, query, aggregateID, model.NormalizeTenantID(tenantID)); err != nil {
+		return fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+	return nil
+}
+
+// DeleteSnapshot - Tek bir version'daki snapshot'ı siler (mutation tamamlanana kadar beklenir)
+func (r *SnapshotRepository) DeleteSnapshot(tenantID, aggregateID string, version uint32) error {
+	ctx := context.Background()
+	tenantID = model.NormalizeTenantID(tenantID)
+
+	var count uint64
+	query := "SELECT count() FROM snapshots WHERE aggregate_id = ? AND tenant_id = ? AND version = ?"
+	if err := r.conn.QueryRow(ctx, query, aggregateID, tenantID, version).Scan(&count); err != nil {
+		return fmt.Errorf("failed to find snapshot: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: aggregate %s at version %d", ErrSnapshotNotFound, aggregateID, version)
+	}
+
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+		"mutations_sync": 2,
+	}))
+	query = "ALTER TABLE snapshots DELETE WHERE aggregate_id = ? AND tenant_id = ? AND version = ?"
+	if err := r.conn.Exec(ctx, query, aggregateID, tenantID, version); err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshots - Aggregate'in snapshot'ları; state okunmaz, sadece boyutu hesaplanır
+func (r *SnapshotRepository) ListSnapshots(tenantID, aggregateID string) ([]*model.SnapshotInfo, error) {
+	ctx := context.Background()
+	query := `
+		SELECT id, version, aggregate_type, state_version, length(state), created_at
+		FROM snapshots FINAL
+		WHERE aggregate_id = ? AND tenant_id = ?
+		ORDER BY version ASC
+	`
+
+	rows, err := r.conn.Query(ctx, query, aggregateID, model.NormalizeTenantID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var infos []*model.SnapshotInfo
+	for rows.Next() {
+		var info model.SnapshotInfo
+		if err := rows.Scan(&info.ID, &info.Version, &info.AggregateType, &info.StateVersion, &info.SizeBytes, &info.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		infos = append(infos, &info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return infos, nil
+}
+
+// keptSnapshots - Her stream'in en yeni ? (keepLastN) farklı version'ı; aynı version'daki tekrar satırlar tek sayılır
+const keptSnapshots = `
+	SELECT tenant_id, aggregate_id, version
+	FROM (SELECT DISTINCT tenant_id, aggregate_id, version FROM snapshots)
+	ORDER BY tenant_id, aggregate_id, version DESC
+	LIMIT ? BY tenant_id, aggregate_id
+`
+
+// PruneSnapshots - Her stream'in en yeni keepLastN version'ı dışındaki snapshot'larını tek mutation ile siler
+// Sayılar mutation'dan hemen önce hesaplanır; arada alınan yeni snapshot'lar sayıyı bir miktar kaydırabilir
+func (r *SnapshotRepository) PruneSnapshots(keepLastN int) (int, uint64, error) {
+	ctx := context.Background()
+	query := `
+		SELECT uniqExact(tenant_id, aggregate_id), count()
+		FROM (SELECT DISTINCT tenant_id, aggregate_id, version FROM snapshots)
+		WHERE (tenant_id, aggregate_id, version) NOT IN (` + keptSnapshots + `)
+	`
+
+	var streams, deleted uint64
+	if err := r.conn.QueryRow(ctx, query, keepLastN).Scan(&streams, &deleted); err != nil {
+		return 0, 0, fmt.Errorf("failed to count prunable snapshots: %w", err)
+	}
+	if deleted == 0 {
+		return 0, 0, nil
+	}
+
+	query = "ALTER TABLE snapshots DELETE WHERE (tenant_id, aggregate_id, version) NOT IN (" + keptSnapshots + ")"
+	if err := r.conn.Exec(ctx, query, keepLastN); err != nil {
+		return 0, 0, fmt.Errorf("failed to prune snapshots: %w", err)
+	}
+	return int(streams), deleted, nil
 }
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// StatsRepository - events tablosuna bağlı materialized view'lardan okunan istatistikler
+// View'lar insert anında saatlik/günlük toplamları SummingMergeTree tablolarına yazar; sorgular
+// events tablosunu taramaz. Arşivlenip silinen partition'ların toplamları view tablolarında kalır
+type StatsRepository struct {
+	conn driver.Conn
+}
+
+func NewStatsRepository(conn driver.Conn) *StatsRepository {
+	return &StatsRepository{conn: conn}
+}
+
+// statsView - Hedef tablo, onu dolduran materialized view ve events'ten aynı toplamı üreten SELECT
+type statsView struct {
+	table string
+	ddl   string
+	view  string
+	query string
+}
+
+// statsViews - Bucket'lar UTC; hafta/ay toplamları saatlik/günlük satırlardan sorgu anında kurulur
+//   - event_counts_hourly: tenant + event tipi başına saatlik event sayısı (signup, şifre değişikliği de buradan)
+//   - login_user_agents_hourly: user agent ailesine göre saatlik login sayısı; user_agent şifreli
+//     tutulduğu için privacy store'un düz yazdığı user_agent_family alanı kullanılır
+//   - aggregate_activity_daily: aggregate başına günlük event sayısı (en aktif aggregate'ler)
+var statsViews = []statsView{
+	{
+		table: "event_counts_hourly",
+		ddl: `
+			CREATE TABLE IF NOT EXISTS event_counts_hourly (
+				tenant_id LowCardinality(String),
+				event_type LowCardinality(String),
+				hour DateTime('UTC'),
+				events UInt64
+			) ENGINE = SummingMergeTree(events)
+			PARTITION BY toYYYYMM(hour)
+			ORDER BY (tenant_id, event_type, hour)`,
+		view: "event_counts_hourly_mv",
+		query: `
+			SELECT tenant_id, event_type, toStartOfHour(toDateTime(timestamp, 'UTC')) AS hour, count() AS events
+			FROM events
+			GROUP BY tenant_id, event_type, hour`,
+	},
+	{
+		table: "login_user_agents_hourly",
+		ddl: `
+			CREATE TABLE IF NOT EXISTS login_user_agents_hourly (
+				tenant_id LowCardinality(String),
+				user_agent_family LowCardinality(String),
+				hour DateTime('UTC'),
+				logins UInt64
+			) ENGINE = SummingMergeTree(logins)
+			PARTITION BY toYYYYMM(hour)
+			ORDER BY (tenant_id, user_agent_family, hour)`,
+		view: "login_user_agents_hourly_mv",
+		query: `
+			SELECT tenant_id,
+				if(JSONExtractString(payload, 'user_agent_family') = '', 'unknown', JSONExtractString(payload, 'user_agent_family')) AS user_agent_family,
+				toStartOfHour(toDateTime(timestamp, 'UTC')) AS hour,
+				count() AS logins
+			FROM events
+			WHERE event_type = 'user.login.recorded'
+			GROUP BY tenant_id, user_agent_family, hour`,
+	},
+	{
+		table: "aggregate_activity_daily",
+		ddl: `
+			CREATE TABLE IF NOT EXISTS aggregate_activity_daily (
+				tenant_id LowCardinality(String),
+				aggregate_type LowCardinality(String),
+				aggregate_id String,
+				day Date,
+				events UInt64
+			) ENGINE = SummingMergeTree(events)
+			PARTITION BY toYYYYMM(day)
+			ORDER BY (tenant_id, day, aggregate_type, aggregate_id)`,
+		view: "aggregate_activity_daily_mv",
+		query: `
+			SELECT tenant_id, aggregate_type, aggregate_id, toDate(timestamp, 'UTC') AS day, count() AS events
+			FROM events
+			GROUP BY tenant_id, aggregate_type, aggregate_id, day`,
+	},
+}
+
+// CreateViews - Hedef tabloları ve materialized view'ları oluşturur
+// View ilk kez oluşturulmadan önce mevcut event'ler hedef tabloya doldurulur. Consumer
+// başlamadan önce çalışır; doldurma ile view'ın oluşması arasında yeni event yazılmaz
+func (r *StatsRepository) CreateViews() error {
+	// Büyük events tablosunun doldurulması bağlantının max_execution_time limitini aşabilir
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithSettings(clickhouse.Settings{
+		"max_execution_time": 0,
+	}))
+
+	for _, v := range statsViews {
+		if err := r.conn.Exec(ctx, v.ddl); err != nil {
+			return fmt.Errorf("failed to create %s: %w", v.table, err)
+		}
+
+		var exists uint64
+		if err := r.conn.QueryRow(ctx, `
+			SELECT count() FROM system.tables
+			WHERE database = currentDatabase() AND name = ?
+		`, v.view).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check %s: %w", v.view, err)
+		}
+		if exists > 0 {
+			continue
+		}
+
+		// View yoksa tabloyu besleyen bir şey yoktur; içindeki satırlar sadece yarıda kalmış bir
+		// önceki doldurmadan gelebilir. Tablo boşaltılıp doldurulur, view en son oluşturulur:
+		// arada çökülürse sonraki açılışta aynı adımlar baştan tekrarlanır
+		started := time.Now()
+		if err := r.conn.Exec(ctx, "TRUNCATE TABLE "+v.table); err != nil {
+			return fmt.Errorf("failed to truncate %s: %w", v.table, err)
+		}
+		if err := r.conn.Exec(ctx, fmt.Sprintf("INSERT INTO %s %s", v.table, v.query)); err != nil {
+			return fmt.Errorf("failed to backfill %s: %w", v.table, err)
+		}
+		if err := r.conn.Exec(ctx, fmt.Sprintf("CREATE MATERIALIZED VIEW %s TO %s AS %s", v.view, v.table, v.query)); err != nil {
+			return fmt.Errorf("failed to create %s: %w", v.view, err)
+		}
+		log.Printf("backfilled %s and created %s in %s", v.table, v.view, time.Since(started))
+	}
+
+	return nil
+}
+
+// statsBucketExpr - Saatlik kolondan istenen bucket'ın başlangıcı (DateTime('UTC'))
+func statsBucketExpr(bucket, column string) (string, error) {
+	switch bucket {
+	case model.StatsBucketHour:
+		return column, nil
+	case model.StatsBucketDay:
+		return fmt.Sprintf("toStartOfDay(%s)", column), nil
+	case model.StatsBucketWeek:
+		return fmt.Sprintf("toDateTime(toMonday(%s), 'UTC')", column), nil
+	case model.StatsBucketMonth:
+		return fmt.Sprintf("toDateTime(toStartOfMonth(%s), 'UTC')", column), nil
+	}
+	return "", fmt.Errorf("unknown stats bucket %q", bucket)
+}
+
+// CountEventsByType - Bucket ve event tipi başına event sayıları
+func (r *StatsRepository) CountEventsByType(query model.StatsQuery) ([]*model.StatsPoint, error) {
+	bucket, err := statsBucketExpr(query.Bucket, "hour")
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery := `
+		SELECT ` + bucket + ` AS bucket, toString(event_type), sum(events)
+		FROM event_counts_hourly
+		WHERE tenant_id = ? AND hour >= ? AND hour < ?`
+	args := []interface{}{model.NormalizeTenantID(query.TenantID), query.From, query.To}
+	if len(query.EventTypes) > 0 {
+		sqlQuery += " AND event_type IN ?"
+		args = append(args, query.EventTypes)
+	}
+	sqlQuery += " GROUP BY bucket, event_type ORDER BY bucket, event_type"
+
+	return r.queryPoints(sqlQuery, args...)
+}
+
+// CountLoginsByUserAgent - Bucket ve user agent ailesi başına login sayıları
+func (r *StatsRepository) CountLoginsByUserAgent(query model.StatsQuery) ([]*model.StatsPoint, error) {
+	bucket, err := statsBucketExpr(query.Bucket, "hour")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryPoints(`
+		SELECT `+bucket+` AS bucket, toString(user_agent_family), sum(logins)
+		FROM login_user_agents_hourly
+		WHERE tenant_id = ? AND hour >= ? AND hour < ?
+		GROUP BY bucket, user_agent_family
+		ORDER BY bucket, user_agent_family
+	`, model.NormalizeTenantID(query.TenantID), query.From, query.To)
+}
+
+func (r *StatsRepository) queryPoints(sqlQuery string, args ...interface{}) ([]*model.StatsPoint, error) {
+	rows, err := r.conn.Query(context.Background(), sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats: %w", err)
+	}
+	defer rows.Close()
+
+	var points []*model.StatsPoint
+	for rows.Next() {
+		var point model.StatsPoint
+		if err := rows.Scan(&point.Bucket, &point.Key, &point.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan stats: %w", err)
+		}
+		points = append(points, &point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return points, nil
+}
+
+// TopAggregates - Aralıkta en çok event alan aggregate'ler
+// Günlük tablodan okunduğu için aralık From ve To'nun günlerini tamamen kapsar
+func (r *StatsRepository) TopAggregates(query model.StatsQuery) ([]*model.AggregateActivity, error) {
+	sqlQuery := `
+		SELECT aggregate_id, toString(any(aggregate_type)), sum(events) AS total, max(day)
+		FROM aggregate_activity_daily
+		WHERE tenant_id = ? AND day >= toDate(?, 'UTC') AND day <= toDate(?, 'UTC')`
+	// To hariç: tam gece yarısında biten aralık o günü kapsamaz
+	args := []interface{}{model.NormalizeTenantID(query.TenantID), query.From, query.To.Add(-time.Millisecond)}
+	if query.AggregateType != "" {
+		sqlQuery += " AND aggregate_type = ?"
+		args = append(args, query.AggregateType)
+	}
+	sqlQuery += fmt.Sprintf(" GROUP BY aggregate_id ORDER BY total DESC, aggregate_id ASC LIMIT %d", query.Limit)
+
+	rows, err := r.conn.Query(context.Background(), sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top aggregates: %w", err)
+	}
+	defer rows.Close()
+
+	var aggregates []*model.AggregateActivity
+	for rows.Next() {
+		var activity model.AggregateActivity
+		if err := rows.Scan(&activity.AggregateID, &activity.AggregateType, &activity.Events, &activity.LastActiveDay); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregate activity: %w", err)
+		}
+		aggregates = append(aggregates, &activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return aggregates, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// EventStore - Event storage backend'lerinin ortak interface'i
+// Service'ler ve gRPC server sadece bu interface'e bağımlıdır.
+// Okuma yolları (GetEvents, GetEventsAfterVersion, ReadStream, CountEvents) her zaman tek bir tenant'a
+// kısıtlanır; boş tenant DefaultTenantID'dir
+type EventStore interface {
+	SaveEvent(event *model.Event) error
+	SaveEvents(events []*model.Event) error
+	GetEvents(filter model.EventFilter) ([]*model.Event, error)
+	// CountEvents - Tenant'ın event sayısı
+	CountEvents(tenantID string) (uint64, error)
+	GetLastPosition() (uint64, error)
+	// GetLatestVersionForAggregate - Version ataması için aggregate'in (tenant'tan bağımsız) son version'ı
+	// Aggregate ID'leri tenant'lar arasında tekildir; okuma yolları önce stream'in tenant'ını kontrol etmelidir
+	GetLatestVersionForAggregate(aggregateID string) (uint32, error)
+	GetEventsAfterVersion(tenantID, aggregateID string, afterVersion uint32) ([]*model.Event, error)
+	// ReadStream - Aggregate stream'ini limitsiz, satır satır okur (fn hata dönerse durur)
+	ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error
+	// StreamTail - Stream'in fromVersion ve sonrasındaki event sayısı, payload boyutu ve ilk event zamanı
+	// Event'ler okunmadan storage'da toplanır; event yoksa sıfır değerler döner
+	StreamTail(tenantID, aggregateID string, fromVersion uint32) (*model.StreamTail, error)
+	EventExists(eventID string) (bool, error)
+	FindExistingEventIDs(eventIDs []string) (map[string]bool, error)
+	// ListStreams - Tenant'ın stream'leri aggregate ID sırasıyla (kategori/tip/aktivite filtreli)
+	ListStreams(query model.StreamListQuery) ([]*model.StreamInfo, error)
+	// ListCategories - Tenant'ta event'i olan kategoriler, stream ve event sayılarıyla (alfabetik)
+	ListCategories(tenantID string) ([]*model.CategoryInfo, error)
+}
+
+// Upcaster - Okuduğu event'leri güncel şema versiyonuna çeviren store'lar (upcast.Store)
+// Storage'dan gelmeyen event'leri (canlı subscription'lar) aynı şekle getirmek için kullanılır
+type Upcaster interface {
+	Upcast(event *model.Event) (*model.Event, error)
+}
+
+// SnapshotStore - Snapshot storage backend'lerinin ortak interface'i
+// Snapshot'lar (tenant_id, aggregate_id) ile okunur; başka tenant'ın snapshot'ı bulunamaz
+type SnapshotStore interface {
+	SaveSnapshot(snapshot *model.Snapshot) error
+	GetLatestSnapshot(tenantID, aggregateID string) (*model.Snapshot, error)
+	GetSnapshotAtVersion(tenantID, aggregateID string, version uint32) (*model.Snapshot, error)
+	HasSnapshot(tenantID, aggregateID string) (bool, error)
+	DeleteOldSnapshots(tenantID, aggregateID string, keepLastN int) error
+	// DeleteSnapshots - Aggregate'in tüm snapshot'larını hemen siler (forget user)
+	DeleteSnapshots(tenantID, aggregateID string) error
+	// DeleteSnapshot - Aggregate'in tek bir version'daki snapshot'ını siler; yoksa ErrSnapshotNotFound
+	DeleteSnapshot(tenantID, aggregateID string, version uint32) error
+	// ListSnapshots - Aggregate'in snapshot'ları version sırasıyla (state olmadan)
+	ListSnapshots(tenantID, aggregateID string) ([]*model.SnapshotInfo, error)
+	// PruneSnapshots - Tüm tenant'larda her stream'in en yeni keepLastN version'ı dışındaki snapshot'larını
+	// tek seferde siler (retention); budanan stream ve silinen snapshot (version) sayısı döner
+	PruneSnapshots(keepLastN int) (streams int, deleted uint64, err error)
+}
+
+// KeyStore - Aggregate başına veri anahtarları (crypto-shredding)
+// Anahtar yok edildiğinde aggregate'in şifreli alanları bir daha çözülemez
+type KeyStore interface {
+	// GetKey - ErrKeyNotFound ya da ErrKeyDestroyed dönebilir
+	GetKey(aggregateID string) ([]byte, error)
+	// GetOrCreateKey - Anahtar varsa onu, yoksa newKey'i kaydedip döner
+	// Unutulmuş aggregate için yeni anahtar oluşturulamaz (ErrKeyDestroyed)
+	GetOrCreateKey(aggregateID string, newKey []byte) ([]byte, error)
+	// DestroyKey - Anahtarı siler ve aggregate'i unutulmuş olarak işaretler (idempotent)
+	DestroyKey(aggregateID string) error
+}
+
+// EventPartition - events tablosunun aylık bir partition'ı (toYYYYMM(timestamp), örn. "202401")
+type EventPartition struct {
+	ID   string
+	Rows uint64
+}
+
+// PartitionStore - Partition bazında arşivlenebilen backend'ler (ClickHouse)
+type PartitionStore interface {
+	ListPartitions() ([]EventPartition, error)
+	CountPartition(partition string) (uint64, error)
+	// ReadPartition - Partition'daki event'leri position sırasıyla fn'e iletir
+	ReadPartition(ctx context.Context, partition string, fn func(*model.Event) error) error
+	DropPartition(partition string) error
+}
+
+// StatsStore - Event istatistikleri; sadece materialized view destekleyen backend'ler (ClickHouse)
+// Sorgular [From, To) aralığında ve query.Bucket'a göre gruplanmış döner
+type StatsStore interface {
+	CountEventsByType(query model.StatsQuery) ([]*model.StatsPoint, error)
+	CountLoginsByUserAgent(query model.StatsQuery) ([]*model.StatsPoint, error)
+	TopAggregates(query model.StatsQuery) ([]*model.AggregateActivity, error)
+}
+
+// ClickHouse implementasyonları interface'leri karşılıyor mu (compile-time kontrol)
+var (
+	_ EventStore     = (*EventRepository)(nil)
+	_ SnapshotStore  = (*SnapshotRepository)(nil)
+	_ KeyStore       = (*KeyRepository)(nil)
+	_ PartitionStore = (*EventRepository)(nil)
+	_ StatsStore     = (*StatsRepository)(nil)
+)
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// ListStreams - Stream'leri events tablosundan aggregate_id'ye göre gruplayarak listeler
+// Sıralama primary key'in ilk kolonu olduğu için AfterAggregateID ile sayfalama granule'ları eler
+func (r *EventRepository) ListStreams(query model.StreamListQuery) ([]*model.StreamInfo, error) {
+	ctx := context.Background()
+
+	conditions := []string{"tenant_id = ?"}
+	args := []interface{}{model.NormalizeTenantID(query.TenantID)}
+
+	if query.Category != "" {
+		conditions = append(conditions, "category = ?")
+		args = append(args, query.Category)
+	}
+	if query.AggregateType != "" {
+		conditions = append(conditions, "aggregate_type = ?")
+		args = append(args, query.AggregateType)
+	}
+	if query.AfterAggregateID != "" {
+		conditions = append(conditions, "aggregate_id > ?")
+		args = append(args, query.AfterAggregateID)
+	}
+
+	sqlQuery := `
+		SELECT aggregate_id, any(aggregate_type), any(category), max(version), count(), max(timestamp)
+		FROM events
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY aggregate_id`
+	if !query.ActiveSince.IsZero() {
+		sqlQuery += " HAVING max(timestamp) >= ?"
+		args = append(args, query.ActiveSince)
+	}
+	sqlQuery += " ORDER BY aggregate_id ASC"
+	if query.Limit > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	rows, err := r.conn.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list streams: %w", err)
+	}
+	defer rows.Close()
+
+	var streams []*model.StreamInfo
+	for rows.Next() {
+		var stream model.StreamInfo
+		if err := rows.Scan(&stream.AggregateID, &stream.AggregateType, &stream.Category,
+			&stream.Version, &stream.EventCount, &stream.LastEventAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stream: %w", err)
+		}
+		streams = append(streams, &stream)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return streams, nil
+}
+
+// ListCategories - Tenant'ın kategorileri; category set index'i sayesinde tek geçişte sayılır
+func (r *EventRepository) ListCategories(tenantID string) ([]*model.CategoryInfo, error) {
+	ctx := context.Background()
+
+	rows, err := r.conn.Query(ctx, `
+		SELECT category, groupUniqArray(toString(aggregate_type)), uniqExact(aggregate_id), count()
+		FROM events
+		WHERE tenant_id = ?
+		GROUP BY category
+		ORDER BY category ASC
+	`, model.NormalizeTenantID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*model.CategoryInfo
+	for rows.Next() {
+		var category model.CategoryInfo
+		if err := rows.Scan(&category.Category, &category.AggregateTypes, &category.Streams, &category.Events); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		sort.Strings(category.AggregateTypes)
+		categories = append(categories, &category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return categories, nil
+}
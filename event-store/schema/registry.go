@@ -0,0 +1,201 @@
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// DefaultVersion - schema_version göndermeyen producer'ların mesajları bu versiyonla doğrulanır
+const DefaultVersion = 1
+
+//go:embed schemas/*.json
+var builtinSchemas embed.FS
+
+// schemaFileName - <event_type>.v<version>.json (örn. user.created.v1.json)
+var schemaFileName = regexp.MustCompile(`^(.+)\.v(\d+)\.json$`)
+
+var (
+	// ErrUnknownSchema - Event tipi ya da versiyonu için kayıtlı şema yok
+	ErrUnknownSchema = errors.New("unknown event schema")
+	// ErrInvalidPayload - Payload şemaya uymuyor
+	ErrInvalidPayload = errors.New("payload does not match schema")
+)
+
+// Schema - Bir event tipinin belirli bir versiyonu için JSON Schema
+type Schema struct {
+	EventType  string          `json:"event_type"`
+	Version    int             `json:"version"`
+	Definition json.RawMessage `json:"schema"`
+
+	compiled *gojsonschema.Schema
+}
+
+// ValidationError - Payload'un şemaya uymadığı noktalar
+type ValidationError struct {
+	EventType string
+	Version   int
+	Errors    []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s v%d: %v", e.EventType, e.Version, e.Errors)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidPayload
+}
+
+// Registry - Event tipi ve versiyonuna göre JSON Schema kayıtları
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]map[int]*Schema
+}
+
+func NewRegistry() *Registry {
+	return &Registry{schemas: make(map[string]map[int]*Schema)}
+}
+
+// LoadRegistry - Gömülü şemaları, dir boş değilse oradaki şemaları da yükler
+// dir'deki dosyalar aynı tip/versiyondaki gömülü şemayı ezer; yeni versiyonlar kod değişmeden eklenebilir
+func LoadRegistry(dir string) (*Registry, error) {
+	registry := NewRegistry()
+
+	if err := registry.loadFS(builtinSchemas, "schemas"); err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		if err := registry.loadFS(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+func (r *Registry) loadFS(fsys fs.FS, root string) error {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		match := schemaFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[2])
+		definition, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(root, entry.Name())))
+		if err != nil {
+			return fmt.Errorf("failed to read schema %s: %w", entry.Name(), err)
+		}
+
+		if err := r.Register(match[1], version, definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Register - Şemayı derleyip kaydeder
+func (r *Registry) Register(eventType string, version int, definition []byte) error {
+	if eventType == "" || version <= 0 {
+		return fmt.Errorf("event type and a positive version are required")
+	}
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(definition))
+	if err != nil {
+		return fmt.Errorf("invalid schema for %s v%d: %w", eventType, version, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.schemas[eventType] == nil {
+		r.schemas[eventType] = make(map[int]*Schema)
+	}
+	r.schemas[eventType][version] = &Schema{
+		EventType:  eventType,
+		Version:    version,
+		Definition: json.RawMessage(definition),
+		compiled:   compiled,
+	}
+
+	return nil
+}
+
+// Get - Tip ve versiyona ait şema
+func (r *Registry) Get(eventType string, version int) (*Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schema, ok := r.schemas[eventType][version]
+	return schema, ok
+}
+
+// Versions - Tipin kayıtlı versiyonları (artan sırada)
+func (r *Registry) Versions(eventType string) []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]int, 0, len(r.schemas[eventType]))
+	for version := range r.schemas[eventType] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// EventTypes - Şeması kayıtlı event tipleri (alfabetik)
+func (r *Registry) EventTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.schemas))
+	for eventType := range r.schemas {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Validate - Payload'u tip/versiyon şemasına göre doğrular
+// Şema yoksa ErrUnknownSchema, uymuyorsa *ValidationError (errors.Is(err, ErrInvalidPayload)) döner
+func (r *Registry) Validate(eventType string, version int, payload []byte) error {
+	if version == 0 {
+		version = DefaultVersion
+	}
+
+	schema, ok := r.Get(eventType, version)
+	if !ok {
+		return fmt.Errorf("%w: %s v%d", ErrUnknownSchema, eventType, version)
+	}
+
+	result, err := schema.compiled.Validate(gojsonschema.NewBytesLoader(payload))
+	if err != nil {
+		return &ValidationError{EventType: eventType, Version: version, Errors: []string{err.Error()}}
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	validationErr := &ValidationError{EventType: eventType, Version: version}
+	for _, resultErr := range result.Errors() {
+		validationErr.Errors = append(validationErr.Errors, resultErr.String())
+	}
+	return validationErr
+}
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuiltinSchemasValidateProducerPayloads(t *testing.T) {
+	registry, err := LoadRegistry("")
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+
+	valid := `{"event_id":"e-1","aggregate_id":"user-1","timestamp":"2024-01-01T10:00:00Z","version":1,` +
+		`"email":"a@example.com","password_hash":"$2a$10$abc"}`
+	if err := registry.Validate("user.created", 0, []byte(valid)); err != nil {
+		t.Fatalf("expected valid payload, got %v", err)
+	}
+
+	missingHash := `{"aggregate_id":"user-1","email":"a@example.com"}`
+	err = registry.Validate("user.created", 1, []byte(missingHash))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(validationErr.Errors) != 1 {
+		t.Errorf("expected one error, got %v", validationErr.Errors)
+	}
+
+	badEmail := `{"aggregate_id":"user-1","email":"not-an-email","password_hash":"x"}`
+	if err := registry.Validate("user.created", 1, []byte(badEmail)); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("expected invalid email to be rejected, got %v", err)
+	}
+}
+
+func TestUnknownTypeOrVersionIsRejected(t *testing.T) {
+	registry, err := LoadRegistry("")
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+
+	if err := registry.Validate("user.renamed", 1, []byte(`{}`)); !errors.Is(err, ErrUnknownSchema) {
+		t.Errorf("expected ErrUnknownSchema for unknown type, got %v", err)
+	}
+	if err := registry.Validate("user.created", 2, []byte(`{}`)); !errors.Is(err, ErrUnknownSchema) {
+		t.Errorf("expected ErrUnknownSchema for unknown version, got %v", err)
+	}
+}
+
+func TestSchemaDirectoryAddsVersions(t *testing.T) {
+	dir := t.TempDir()
+	v2 := `{"type":"object","required":["aggregate_id","email","password_hash","tenant"]}`
+	if err := os.WriteFile(filepath.Join(dir, "user.created.v2.json"), []byte(v2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := LoadRegistry(dir)
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+
+	if versions := registry.Versions("user.created"); len(versions) != 2 || versions[1] != 2 {
+		t.Fatalf("expected versions [1 2], got %v", versions)
+	}
+
+	payload := []byte(`{"aggregate_id":"user-1","email":"a@example.com","password_hash":"x"}`)
+	if err := registry.Validate("user.created", 1, payload); err != nil {
+		t.Errorf("v1 should still accept the payload, got %v", err)
+	}
+	if err := registry.Validate("user.created", 2, payload); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("v2 should require tenant, got %v", err)
+	}
+}
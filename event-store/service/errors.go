@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// ErrConcurrencyConflict - errors.Is ile conflict kontrolü için
+var ErrConcurrencyConflict = errors.New("concurrency conflict")
+
+// ConcurrencyConflictError - Stream beklenen versiyonda değilse döner
+type ConcurrencyConflictError struct {
+	AggregateID     string
+	ExpectedVersion model.ExpectedVersion
+	ActualVersion   uint32
+}
+
+func (e *ConcurrencyConflictError) Error() string {
+	if e.ExpectedVersion.Kind == model.ExpectedVersionExact {
+		return fmt.Sprintf("concurrency conflict on aggregate %s: expected version %d, actual version %d",
+			e.AggregateID, e.ExpectedVersion.Version, e.ActualVersion)
+	}
+	return fmt.Sprintf("concurrency conflict on aggregate %s: expected %s, actual version %d",
+		e.AggregateID, e.ExpectedVersion, e.ActualVersion)
+}
+
+func (e *ConcurrencyConflictError) Unwrap() error {
+	return ErrConcurrencyConflict
+}
+
+// ErrAggregateNotFound - Aggregate'e ait hiç event yok
+var ErrAggregateNotFound = errors.New("aggregate not found")
+
+// ErrTenantMismatch - Aggregate başka bir tenant'a ait; yazma reddedilir
+// Okumalarda başka tenant'ın aggregate'i ErrAggregateNotFound ile aynı görünür
+var ErrTenantMismatch = errors.New("aggregate belongs to another tenant")
+
+// ErrAggregateTypeMismatch - Stream başka bir aggregate tipinde ya da batch'te farklı tipler var
+// Bir stream'in tüm event'leri aynı tipte (ve kategoride) olmalıdır
+var ErrAggregateTypeMismatch = errors.New("aggregate type mismatch")
+
+// ErrInvalidAggregateType - Aggregate tipi adı geçersiz (model.ValidStreamName)
+var ErrInvalidAggregateType = errors.New("invalid aggregate type")
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// EventBroadcaster - Kaydedilen event'leri canlı subscription'lara dağıtır
+// Yavaş subscriber'lar yazma yolunu bekletmez: buffer'ı dolan subscription kapatılır
+// ve subscriber kaçırdığı event'leri storage'dan tekrar okur (catch-up)
+type EventBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*liveSubscription]struct{}
+}
+
+// liveSubscription - Tek bir subscriber'ın canlı event kanalı
+// Kanal kapanırsa subscriber geride kalmıştır
+type liveSubscription struct {
+	events chan *model.Event
+}
+
+func NewEventBroadcaster() *EventBroadcaster {
+	return &EventBroadcaster{
+		subscribers: make(map[*liveSubscription]struct{}),
+	}
+}
+
+func (b *EventBroadcaster) subscribe(buffer int) *liveSubscription {
+	sub := &liveSubscription{events: make(chan *model.Event, buffer)}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+func (b *EventBroadcaster) unsubscribe(sub *liveSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// publish - Event'leri position sırasıyla tüm subscriber'lara gönderir (appendMu altında çağrılır)
+func (b *EventBroadcaster) publish(events []*model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subscribers) == 0 {
+		return
+	}
+
+	// Subscriber'lar event'i sadece okur, tek kopya hepsine yeter
+	copies := copyEventsForBroadcast(events)
+
+	for sub := range b.subscribers {
+		for _, event := range copies {
+			select {
+			case sub.events <- event:
+			default:
+				delete(b.subscribers, sub)
+				close(sub.events)
+			}
+			if _, ok := b.subscribers[sub]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// SubscriberCount - Aktif canlı subscription sayısı
+func (b *EventBroadcaster) SubscriberCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscribers)
+}
+
+func copyEventsForBroadcast(events []*model.Event) []*model.Event {
+	copies := make([]*model.Event, len(events))
+	for i, event := range events {
+		c := *event
+		copies[i] = &c
+	}
+	return copies
+}
@@ -1,31 +1,89 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/eyupaydin41/event-store/catalog"
 	"github.com/eyupaydin41/event-store/model"
 	"github.com/eyupaydin41/event-store/repository"
+	"github.com/google/uuid"
 )
 
+// ErrDuplicateEvent - Aynı ID ile bir event zaten kaydedilmişse SaveEvent bunu döner
+// Ingestion bunu hata değil no-op olarak ele alır (at-least-once delivery)
+var ErrDuplicateEvent = errors.New("duplicate event")
+
 type EventService struct {
-	repo *repository.EventRepository
+	repo repository.EventStore
+	// types - Aggregate tipi -> kategori eşlemesi (event'lere yazılırken damgalanır)
+	types *catalog.Catalog
+
+	// ClickHouse transaction desteklemediği için version okuma + insert
+	// bu lock altında yapılır. Lock sadece bu event-store instance'ı içindeki append'leri
+	// sıralar; aynı store'a yazan ikinci bir instance expected version kontrolünü atlatabilir
+	// (tek event-store instance varsayımı)
+	appendMu sync.Mutex
+
+	// lastPosition - Son atanan global position (appendMu ile korunur)
+	// positionLoaded false ise bir sonraki append'te storage'dan okunur
+	lastPosition   uint64
+	positionLoaded bool
+
+	// broadcaster - Kaydedilen event'leri canlı subscription'lara iletir
+	broadcaster *EventBroadcaster
 }
 
-func NewEventService(repo *repository.EventRepository) *EventService {
-	return &EventService{repo: repo}
+func NewEventService(repo repository.EventStore, types *catalog.Catalog) *EventService {
+	return &EventService{
+		repo:        repo,
+		types:       types,
+		broadcaster: NewEventBroadcaster(),
+	}
 }
 
 func (s *EventService) SaveEvent(event *model.Event) error {
+	s.appendMu.Lock()
+	defer s.appendMu.Unlock()
+
+	// Producer'ın atadığı ID ile event zaten kaydedilmişse (AppendEvents ile yazılmış
+	// ya da Kafka'dan tekrar gelmiş) version'ı tekrar artırma
+	if event.ID != "" {
+		exists, err := s.repo.EventExists(event.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check existing event: %w", err)
+		}
+		if exists {
+			return ErrDuplicateEvent
+		}
+	} else {
+		event.ID = uuid.New().String()
+	}
+	event.TenantID = model.NormalizeTenantID(event.TenantID)
+
 	if event.AggregateID != "" {
 		latestVersion, err := s.repo.GetLatestVersionForAggregate(event.AggregateID)
 		if err != nil {
 			log.Printf("error getting latest version for aggregate %s: %v", event.AggregateID, err)
 			return fmt.Errorf("failed to get latest version: %w", err)
 		}
+		streamType, err := checkTenant(s.repo, event.TenantID, event.AggregateID, latestVersion)
+		if err != nil {
+			return err
+		}
+		if err := s.stampStream(streamType, []*model.Event{event}); err != nil {
+			return err
+		}
 		event.Version = latestVersion + 1
 	} else {
+		if err := s.stampStream("", []*model.Event{event}); err != nil {
+			return err
+		}
 		event.Version = 1
 	}
 
@@ -33,14 +91,202 @@ func (s *EventService) SaveEvent(event *model.Event) error {
 		event.Timestamp = time.Now()
 	}
 
+	position, err := s.currentPosition()
+	if err != nil {
+		return err
+	}
+	event.Position = position + 1
+
 	if err := s.repo.SaveEvent(event); err != nil {
+		s.positionLoaded = false
+		if errors.Is(err, repository.ErrDuplicateEventID) {
+			return ErrDuplicateEvent
+		}
 		return fmt.Errorf("failed to save event: %w", err)
 	}
+	s.lastPosition = event.Position
+	s.broadcast([]*model.Event{event})
 
-	log.Printf("event saved: %s (type: %s, version: %d)", event.ID, event.EventType, event.Version)
+	log.Printf("event saved: %s (type: %s, version: %d, position: %d)", event.ID, event.EventType, event.Version, event.Position)
 	return nil
 }
 
+// AppendEvents - Event'leri optimistic concurrency kontrolü ile tenant'ın aggregate'ine ekler
+// Stream beklenen versiyonda değilse *ConcurrencyConflictError, aggregate başka tenant'ınsa ErrTenantMismatch,
+// event'lerin aggregate tipi stream'inkiyle (ya da birbiriyle) uyuşmuyorsa ErrAggregateTypeMismatch döner
+func (s *EventService) AppendEvents(tenantID, aggregateID string, expected model.ExpectedVersion, events []*model.Event) (uint32, error) {
+	if aggregateID == "" {
+		return 0, fmt.Errorf("aggregate_id is required")
+	}
+	if len(events) == 0 {
+		return 0, fmt.Errorf("at least one event is required")
+	}
+
+	s.appendMu.Lock()
+	defer s.appendMu.Unlock()
+
+	currentVersion, err := s.repo.GetLatestVersionForAggregate(aggregateID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest version: %w", err)
+	}
+
+	tenantID = model.NormalizeTenantID(tenantID)
+	streamType, err := checkTenant(s.repo, tenantID, aggregateID, currentVersion)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.stampStream(streamType, events); err != nil {
+		return 0, err
+	}
+
+	// Aynı batch daha önce yazılmışsa (client retry) idempotent olarak başarılı dön
+	existing, err := s.repo.FindExistingEventIDs(eventIDs(events))
+	if err != nil {
+		return 0, fmt.Errorf("failed to check existing events: %w", err)
+	}
+	if len(existing) > 0 {
+		if len(existing) == len(events) {
+			log.Printf("append batch for aggregate %s already stored, skipping", aggregateID)
+			return currentVersion, nil
+		}
+		return 0, fmt.Errorf("%w: %d of %d events in batch already stored", ErrDuplicateEvent, len(existing), len(events))
+	}
+
+	if !expected.Matches(currentVersion) {
+		return 0, &ConcurrencyConflictError{
+			AggregateID:     aggregateID,
+			ExpectedVersion: expected,
+			ActualVersion:   currentVersion,
+		}
+	}
+
+	position, err := s.currentPosition()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for i, event := range events {
+		event.TenantID = tenantID
+		event.AggregateID = aggregateID
+		event.Version = currentVersion + uint32(i) + 1
+		event.Position = position + uint64(i) + 1
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+		if event.Timestamp.IsZero() {
+			event.Timestamp = now
+		}
+	}
+
+	if err := s.repo.SaveEvents(events); err != nil {
+		s.positionLoaded = false
+		// Unique constraint'li backend'lerde başka bir writer araya girmiş olabilir
+		if errors.Is(err, repository.ErrVersionConflict) {
+			actual, _ := s.repo.GetLatestVersionForAggregate(aggregateID)
+			return 0, &ConcurrencyConflictError{
+				AggregateID:     aggregateID,
+				ExpectedVersion: expected,
+				ActualVersion:   actual,
+			}
+		}
+		return 0, fmt.Errorf("failed to append events: %w", err)
+	}
+
+	s.lastPosition = position + uint64(len(events))
+	s.broadcast(events)
+
+	lastVersion := currentVersion + uint32(len(events))
+	log.Printf("appended %d events to aggregate %s (version %d -> %d)", len(events), aggregateID, currentVersion, lastVersion)
+	return lastVersion, nil
+}
+
+// broadcast - Kaydedilen event'leri canlı subscription'lara iletir
+// Store upcast ediyorsa subscriber'lar da catch-up'taki gibi güncel şekli görür
+func (s *EventService) broadcast(events []*model.Event) {
+	upcaster, ok := s.repo.(repository.Upcaster)
+	if !ok {
+		s.broadcaster.publish(events)
+		return
+	}
+
+	upcasted := make([]*model.Event, len(events))
+	for i, event := range events {
+		u, err := upcaster.Upcast(event)
+		if err != nil {
+			log.Printf("failed to upcast event %s for subscribers: %v", event.ID, err)
+			u = event
+		}
+		upcasted[i] = u
+	}
+	s.broadcaster.publish(upcasted)
+}
+
+// currentPosition - Son atanan global position'ı döner (appendMu altında çağrılmalı)
+// Yazma hatasından sonra event'in kısmen yazılmış olma ihtimaline karşı storage'dan tekrar okunur
+func (s *EventService) currentPosition() (uint64, error) {
+	if !s.positionLoaded {
+		position, err := s.repo.GetLastPosition()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get last position: %w", err)
+		}
+		s.lastPosition = position
+		s.positionLoaded = true
+	}
+	return s.lastPosition, nil
+}
+
+// stampStream - Event'lere stream'in aggregate tipini ve catalog'daki kategorisini yazar
+// Tip mevcut stream'den (streamType), yoksa event'lerde verilen tipten, o da yoksa event tipinin
+// önekinden ("order.created" -> "order") gelir; hiçbiri yoksa DefaultAggregateType
+func (s *EventService) stampStream(streamType string, events []*model.Event) error {
+	var requested string
+	for _, event := range events {
+		if event.AggregateType == "" {
+			continue
+		}
+		if !model.ValidStreamName(event.AggregateType) {
+			return fmt.Errorf("%w: %q", ErrInvalidAggregateType, event.AggregateType)
+		}
+		if requested != "" && requested != event.AggregateType {
+			return fmt.Errorf("%w: batch mixes %s and %s", ErrAggregateTypeMismatch, requested, event.AggregateType)
+		}
+		requested = event.AggregateType
+	}
+
+	aggregateType := streamType
+	switch {
+	case streamType != "" && requested != "" && requested != streamType:
+		return fmt.Errorf("%w: stream is %s, got %s", ErrAggregateTypeMismatch, streamType, requested)
+	case streamType != "":
+	case requested != "":
+		aggregateType = requested
+	default:
+		aggregateType = model.AggregateTypeOf(events[0].EventType)
+		if !model.ValidStreamName(aggregateType) {
+			aggregateType = model.DefaultAggregateType
+		}
+	}
+
+	category := s.types.CategoryOf(aggregateType)
+	for _, event := range events {
+		event.AggregateType = aggregateType
+		event.Category = category
+	}
+	return nil
+}
+
+// eventIDs - Producer'ın atadığı (boş olmayan) event ID'lerini toplar
+func eventIDs(events []*model.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		if event.ID != "" {
+			ids = append(ids, event.ID)
+		}
+	}
+	return ids
+}
+
 func (s *EventService) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
 	if filter.Limit == 0 {
 		filter.Limit = 100
@@ -55,43 +301,63 @@ func (s *EventService) GetEvents(filter model.EventFilter) ([]*model.Event, erro
 	return events, nil
 }
 
-func (s *EventService) GetEventsByAggregateID(aggregateID string, fromVersion int) ([]*model.Event, error) {
+func (s *EventService) GetEventsByAggregateID(tenantID, aggregateID string, fromVersion int) ([]*model.Event, error) {
 	if aggregateID == "" {
 		return nil, fmt.Errorf("aggregate_id is required")
 	}
 
-	filter := model.EventFilter{
-		AggregateID: aggregateID,
-		Limit:       1000,
+	query := model.StreamQuery{TenantID: tenantID, AggregateID: aggregateID}
+	if fromVersion > 0 {
+		query.FromVersion = uint32(fromVersion)
 	}
 
-	events, err := s.repo.GetEvents(filter)
+	var events []*model.Event
+	err := s.ReadStream(context.Background(), query, func(event *model.Event) error {
+		events = append(events, event)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get events for aggregate %s: %w", aggregateID, err)
-	}
-
-	if fromVersion > 0 {
-		var filtered []*model.Event
-		for _, event := range events {
-			if int(event.Version) >= fromVersion {
-				filtered = append(filtered, event)
-			}
-		}
-		events = filtered
+		return nil, err
 	}
 
 	log.Printf("retrieved %d events for aggregate: %s", len(events), aggregateID)
 	return events, nil
 }
 
-func (s *EventService) GetEventsSince(since time.Time) ([]*model.Event, error) {
+// ReadStream - Aggregate stream'ini limitsiz olarak event event fn'e iletir
+// Filtreler storage sorgusuna iletilir; fn hata dönerse okuma durur (hata errors.Is ile ayırt edilebilir)
+func (s *EventService) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	if query.AggregateID == "" {
+		return fmt.Errorf("aggregate_id is required")
+	}
+	if query.ToVersion > 0 && query.FromVersion > query.ToVersion {
+		return fmt.Errorf("from_version (%d) is greater than to_version (%d)", query.FromVersion, query.ToVersion)
+	}
+
+	if err := s.repo.ReadStream(ctx, query, fn); err != nil {
+		return fmt.Errorf("failed to read stream %s: %w", query.AggregateID, err)
+	}
+	return nil
+}
+
+// GetEventsSince - Tenant'ın since'dan sonraki event'lerini global position sırasıyla getirir
+// fromPosition ile sayfalanır (0 = baştan); limit 0 ise 10000
+func (s *EventService) GetEventsSince(tenantID string, since time.Time, fromPosition uint64, limit int) ([]*model.Event, error) {
 	if since.IsZero() {
 		return nil, fmt.Errorf("since time is required")
 	}
+	if fromPosition == 0 {
+		fromPosition = 1
+	}
+	if limit <= 0 {
+		limit = 10000
+	}
 
 	filter := model.EventFilter{
-		StartTime: since,
-		Limit:     10000,
+		TenantID:     tenantID,
+		StartTime:    since,
+		FromPosition: fromPosition,
+		Limit:        limit,
 	}
 
 	events, err := s.repo.GetEvents(filter)
@@ -103,8 +369,38 @@ func (s *EventService) GetEventsSince(since time.Time) ([]*model.Event, error) {
 	return events, nil
 }
 
-func (s *EventService) CountEvents() (uint64, error) {
-	count, err := s.repo.CountEvents()
+// GetEventsFromPosition - Global position'dan (dahil) itibaren tenant'ın event'lerini position sırasıyla getirir
+// Timestamp'e göre okumanın aksine aynı milisaniyedeki event'leri atlamaz ya da tekrarlamaz.
+// Position'lar tüm tenant'lar arasında ortak olduğu için sonuçlarda boşluk olması beklenir.
+// eventType ve category boş değilse sadece eşleşen event'ler döner
+func (s *EventService) GetEventsFromPosition(tenantID string, fromPosition uint64, eventType, category string, limit int) ([]*model.Event, error) {
+	if fromPosition == 0 {
+		fromPosition = 1
+	}
+	if limit <= 0 {
+		limit = 10000
+	}
+
+	filter := model.EventFilter{
+		TenantID:     tenantID,
+		EventType:    eventType,
+		Category:     category,
+		FromPosition: fromPosition,
+		Limit:        limit,
+	}
+
+	events, err := s.repo.GetEvents(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events from position %d: %w", fromPosition, err)
+	}
+
+	log.Printf("retrieved %d events from position %d", len(events), fromPosition)
+	return events, nil
+}
+
+// CountEvents - Tenant'ın event sayısı (arşivlenmişler dahil)
+func (s *EventService) CountEvents(tenantID string) (uint64, error) {
+	count, err := s.repo.CountEvents(tenantID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count events: %w", err)
 	}
@@ -112,7 +408,8 @@ func (s *EventService) CountEvents() (uint64, error) {
 	return count, nil
 }
 
-func (s *EventService) GetLatestVersionForAggregate(aggregateID string) (uint32, error) {
+// GetLatestVersionForAggregate - Tenant'ın aggregate'inin son version'ı (başka tenant'ın aggregate'i için 0)
+func (s *EventService) GetLatestVersionForAggregate(tenantID, aggregateID string) (uint32, error) {
 	if aggregateID == "" {
 		return 0, fmt.Errorf("aggregate_id is required")
 	}
@@ -121,6 +418,70 @@ func (s *EventService) GetLatestVersionForAggregate(aggregateID string) (uint32,
 	if err != nil {
 		return 0, fmt.Errorf("failed to get latest version for aggregate %s: %w", aggregateID, err)
 	}
+	if version == 0 {
+		return 0, nil
+	}
+
+	owned, err := streamExists(s.repo, tenantID, aggregateID, version)
+	if err != nil {
+		return 0, err
+	}
+	if !owned {
+		return 0, nil
+	}
 
 	return version, nil
 }
+
+// StreamCategory - Kategorinin catalog ayarları ve tenant'taki stream/event sayıları
+type StreamCategory struct {
+	model.CategoryInfo
+	Snapshot catalog.SnapshotSettings `json:"snapshot"`
+}
+
+// ListCategories - Tenant'ın kategorileri: catalog'da tanımlı olanlar (event'i olmasa da) ve
+// catalog'da olmayıp event'i bulunanlar, alfabetik
+func (s *EventService) ListCategories(tenantID string) ([]*StreamCategory, error) {
+	stats, err := s.repo.ListCategories(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	byName := make(map[string]*StreamCategory)
+	for _, category := range s.types.Categories() {
+		byName[category.Name] = &StreamCategory{
+			CategoryInfo: model.CategoryInfo{Category: category.Name, AggregateTypes: []string{}},
+			Snapshot:     category.Snapshot,
+		}
+	}
+	for _, info := range stats {
+		category, ok := byName[info.Category]
+		if !ok {
+			category = &StreamCategory{Snapshot: s.types.Snapshot(info.Category)}
+			byName[info.Category] = category
+		}
+		category.CategoryInfo = *info
+	}
+
+	categories := make([]*StreamCategory, 0, len(byName))
+	for _, category := range byName {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Category < categories[j].Category
+	})
+	return categories, nil
+}
+
+// ListStreams - Tenant'ın stream'leri aggregate ID sırasıyla; limit 0 ise 100
+func (s *EventService) ListStreams(query model.StreamListQuery) ([]*model.StreamInfo, error) {
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+
+	streams, err := s.repo.ListStreams(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list streams: %w", err)
+	}
+	return streams, nil
+}
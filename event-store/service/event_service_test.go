@@ -0,0 +1,335 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/catalog"
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+func newTestEventService() *EventService {
+	return NewEventService(repository.NewMemoryEventRepository(), catalog.NewCatalog())
+}
+
+func TestAppendEventsAssignsVersions(t *testing.T) {
+	svc := newTestEventService()
+
+	lastVersion, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}, []*model.Event{
+		{EventType: "user.created", Payload: `{}`},
+		{EventType: "user.email.changed", Payload: `{}`},
+	})
+	if err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+	if lastVersion != 2 {
+		t.Fatalf("expected last version 2, got %d", lastVersion)
+	}
+
+	events, err := svc.GetEventsByAggregateID(model.DefaultTenantID, "user-1", 0)
+	if err != nil {
+		t.Fatalf("GetEventsByAggregateID: %v", err)
+	}
+	for i, event := range events {
+		if event.Version != uint32(i+1) {
+			t.Errorf("event %d: expected version %d, got %d", i, i+1, event.Version)
+		}
+	}
+}
+
+func TestAppendEventsRejectsStaleExpectedVersion(t *testing.T) {
+	svc := newTestEventService()
+
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}, []*model.Event{
+		{EventType: "user.created", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	// İki command aynı version'dan yüklenmiş: ilki kazanır, ikincisi conflict almalı
+	exact := model.ExpectedVersion{Kind: model.ExpectedVersionExact, Version: 1}
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", exact, []*model.Event{{EventType: "user.email.changed", Payload: `{}`}}); err != nil {
+		t.Fatalf("first AppendEvents: %v", err)
+	}
+
+	_, err := svc.AppendEvents(model.DefaultTenantID, "user-1", exact, []*model.Event{{EventType: "user.email.changed", Payload: `{}`}})
+	var conflict *ConcurrencyConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConcurrencyConflictError, got %v", err)
+	}
+	if conflict.ActualVersion != 2 {
+		t.Errorf("expected actual version 2, got %d", conflict.ActualVersion)
+	}
+	if !errors.Is(err, ErrConcurrencyConflict) {
+		t.Errorf("expected errors.Is(err, ErrConcurrencyConflict)")
+	}
+
+	// NoStream mevcut stream'e yazamaz
+	_, err = svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}, []*model.Event{{EventType: "user.created", Payload: `{}`}})
+	if !errors.Is(err, ErrConcurrencyConflict) {
+		t.Errorf("expected conflict for no-stream on existing stream, got %v", err)
+	}
+}
+
+func TestSaveEventSkipsDuplicateEventID(t *testing.T) {
+	svc := newTestEventService()
+
+	event := &model.Event{ID: "evt-1", EventType: "user.login.recorded", AggregateID: "user-1", Payload: `{}`}
+	if err := svc.SaveEvent(event); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+
+	redelivered := &model.Event{ID: "evt-1", EventType: "user.login.recorded", AggregateID: "user-1", Payload: `{}`}
+	if err := svc.SaveEvent(redelivered); !errors.Is(err, ErrDuplicateEvent) {
+		t.Fatalf("expected ErrDuplicateEvent, got %v", err)
+	}
+
+	version, err := svc.GetLatestVersionForAggregate(model.DefaultTenantID, "user-1")
+	if err != nil {
+		t.Fatalf("GetLatestVersionForAggregate: %v", err)
+	}
+	if version != 1 {
+		t.Errorf("duplicate must not bump the version, got %d", version)
+	}
+}
+
+func TestAppendEventsIsIdempotentForRetriedBatch(t *testing.T) {
+	svc := newTestEventService()
+
+	batch := func() []*model.Event {
+		return []*model.Event{{ID: "evt-1", EventType: "user.created", Payload: `{}`}}
+	}
+
+	noStream := model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", noStream, batch()); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	// Client timeout sonrası aynı batch'i tekrar gönderirse conflict değil no-op almalı
+	lastVersion, err := svc.AppendEvents(model.DefaultTenantID, "user-1", noStream, batch())
+	if err != nil {
+		t.Fatalf("retried AppendEvents: %v", err)
+	}
+	if lastVersion != 1 {
+		t.Errorf("expected version 1, got %d", lastVersion)
+	}
+
+	count, _ := svc.CountEvents(model.DefaultTenantID)
+	if count != 1 {
+		t.Errorf("expected 1 stored event, got %d", count)
+	}
+	if count, _ := svc.CountEvents("other-tenant"); count != 0 {
+		t.Errorf("expected another tenant to count 0 events, got %d", count)
+	}
+}
+
+func TestPositionsAreGapFreeAcrossStreams(t *testing.T) {
+	svc := newTestEventService()
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Aynı milisaniyedeki event'ler timestamp ile ayırt edilemez, position ile edilir
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{}, []*model.Event{
+		{EventType: "user.created", Payload: `{}`, Timestamp: at},
+		{EventType: "user.email.changed", Payload: `{}`, Timestamp: at},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+	if err := svc.SaveEvent(&model.Event{EventType: "user.created", AggregateID: "user-2", Payload: `{}`, Timestamp: at}); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+	// Duplicate position tüketmemeli
+	if err := svc.SaveEvent(&model.Event{ID: "dup", EventType: "user.login.recorded", AggregateID: "user-2", Payload: `{}`, Timestamp: at}); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+	if err := svc.SaveEvent(&model.Event{ID: "dup", EventType: "user.login.recorded", AggregateID: "user-2", Payload: `{}`, Timestamp: at}); !errors.Is(err, ErrDuplicateEvent) {
+		t.Fatalf("expected ErrDuplicateEvent, got %v", err)
+	}
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{}, []*model.Event{{EventType: "user.deactivated", Payload: `{}`, Timestamp: at}}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	all, err := svc.GetEventsFromPosition(model.DefaultTenantID, 0, "", "", 0)
+	if err != nil {
+		t.Fatalf("GetEventsFromPosition: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(all))
+	}
+	for i, event := range all {
+		if event.Position != uint64(i+1) {
+			t.Errorf("event %d: expected position %d, got %d", i, i+1, event.Position)
+		}
+	}
+
+	resumed, err := svc.GetEventsFromPosition(model.DefaultTenantID, 4, "", "", 0)
+	if err != nil {
+		t.Fatalf("GetEventsFromPosition: %v", err)
+	}
+	if len(resumed) != 2 || resumed[0].Position != 4 || resumed[0].ID != "dup" {
+		t.Errorf("expected to resume exactly at position 4, got %+v", resumed)
+	}
+}
+
+func TestPositionContinuesFromStorage(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	if err := repo.SaveEvent(&model.Event{ID: "old", EventType: "user.created", AggregateID: "user-1", Version: 1, Position: 41}); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+
+	// Yeniden başlatılan servis son position'ı storage'dan okumalı
+	svc := NewEventService(repo, catalog.NewCatalog())
+	event := &model.Event{EventType: "user.deactivated", AggregateID: "user-1", Payload: `{}`}
+	if err := svc.SaveEvent(event); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+	if event.Position != 42 {
+		t.Errorf("expected position 42, got %d", event.Position)
+	}
+}
+
+func TestTenantsCannotSeeOrWriteEachOthersStreams(t *testing.T) {
+	svc := newTestEventService()
+
+	if _, err := svc.AppendEvents("acme", "user-1", model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}, []*model.Event{
+		{EventType: "user.created", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	events, err := svc.GetEventsByAggregateID("globex", "user-1", 0)
+	if err != nil {
+		t.Fatalf("GetEventsByAggregateID: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected foreign stream to be invisible, got %d events", len(events))
+	}
+	if version, _ := svc.GetLatestVersionForAggregate("globex", "user-1"); version != 0 {
+		t.Errorf("expected version 0 for foreign stream, got %d", version)
+	}
+	if all, _ := svc.GetEventsFromPosition("globex", 0, "", "", 0); len(all) != 0 {
+		t.Errorf("expected no events for another tenant, got %d", len(all))
+	}
+
+	_, err = svc.AppendEvents("globex", "user-1", model.ExpectedVersion{}, []*model.Event{{EventType: "user.deactivated", Payload: `{}`}})
+	if !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("expected ErrTenantMismatch, got %v", err)
+	}
+	err = svc.SaveEvent(&model.Event{EventType: "user.login.recorded", AggregateID: "user-1", Payload: `{}`})
+	if !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("expected ErrTenantMismatch for default tenant, got %v", err)
+	}
+
+	owned, err := svc.GetEventsByAggregateID("acme", "user-1", 0)
+	if err != nil {
+		t.Fatalf("GetEventsByAggregateID: %v", err)
+	}
+	if len(owned) != 1 || owned[0].TenantID != "acme" {
+		t.Fatalf("expected the owner tenant to read its single event, got %+v", owned)
+	}
+}
+
+func TestAppendEventsStampsAggregateTypeAndCategory(t *testing.T) {
+	svc := newTestEventService()
+
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "order-1", model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}, []*model.Event{
+		{EventType: "order.placed", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{Kind: model.ExpectedVersionNoStream}, []*model.Event{
+		{EventType: "user.created", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	// Tip verilmeyen sonraki event'ler stream'in tipini alır
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "order-1", model.ExpectedVersion{}, []*model.Event{
+		{EventType: "shipment.requested", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	events, err := svc.GetEventsByAggregateID(model.DefaultTenantID, "order-1", 0)
+	if err != nil {
+		t.Fatalf("GetEventsByAggregateID: %v", err)
+	}
+	for _, event := range events {
+		if event.AggregateType != "order" || event.Category != "order" {
+			t.Errorf("event %s: expected order/order, got %s/%s", event.EventType, event.AggregateType, event.Category)
+		}
+	}
+
+	_, err = svc.AppendEvents(model.DefaultTenantID, "order-1", model.ExpectedVersion{}, []*model.Event{
+		{EventType: "order.cancelled", AggregateType: "team", Payload: `{}`},
+	})
+	if !errors.Is(err, ErrAggregateTypeMismatch) {
+		t.Fatalf("expected ErrAggregateTypeMismatch, got %v", err)
+	}
+
+	byCategory, err := svc.GetEventsFromPosition(model.DefaultTenantID, 0, "", "order", 0)
+	if err != nil {
+		t.Fatalf("GetEventsFromPosition: %v", err)
+	}
+	if len(byCategory) != 2 {
+		t.Errorf("expected 2 order events, got %d", len(byCategory))
+	}
+}
+
+func TestListStreamsAndCategories(t *testing.T) {
+	svc := newTestEventService()
+
+	for _, aggregateID := range []string{"user-2", "user-1", "user-3"} {
+		if _, err := svc.AppendEvents(model.DefaultTenantID, aggregateID, model.ExpectedVersion{}, []*model.Event{
+			{EventType: "user.created", Payload: `{}`},
+			{EventType: "user.email.changed", Payload: `{}`},
+		}); err != nil {
+			t.Fatalf("AppendEvents: %v", err)
+		}
+	}
+	if _, err := svc.AppendEvents("acme", "team-1", model.ExpectedVersion{}, []*model.Event{
+		{EventType: "team.created", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	page, err := svc.ListStreams(model.StreamListQuery{TenantID: model.DefaultTenantID, Category: "user", Limit: 2})
+	if err != nil {
+		t.Fatalf("ListStreams: %v", err)
+	}
+	if len(page) != 2 || page[0].AggregateID != "user-1" || page[1].AggregateID != "user-2" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	if page[0].Version != 2 || page[0].EventCount != 2 {
+		t.Errorf("expected version 2 with 2 events, got %+v", page[0])
+	}
+
+	rest, err := svc.ListStreams(model.StreamListQuery{TenantID: model.DefaultTenantID, Category: "user", AfterAggregateID: "user-2"})
+	if err != nil {
+		t.Fatalf("ListStreams: %v", err)
+	}
+	if len(rest) != 1 || rest[0].AggregateID != "user-3" {
+		t.Fatalf("unexpected second page: %+v", rest)
+	}
+
+	categories, err := svc.ListCategories(model.DefaultTenantID)
+	if err != nil {
+		t.Fatalf("ListCategories: %v", err)
+	}
+	if len(categories) != 1 || categories[0].Category != "user" || categories[0].Streams != 3 || categories[0].Events != 6 {
+		t.Fatalf("unexpected categories: %+v", categories)
+	}
+	if categories[0].Snapshot.Interval == 0 {
+		t.Errorf("expected catalog snapshot settings on the category")
+	}
+
+	// Catalog'daki kategoriler event'i olmayan tenant'ta da listelenir, diğer tenant'ın kategorileri görünmez
+	acme, err := svc.ListCategories("acme")
+	if err != nil {
+		t.Fatalf("ListCategories: %v", err)
+	}
+	if len(acme) != 2 || acme[0].Category != "team" || acme[1].Category != "user" || acme[1].Streams != 0 {
+		t.Fatalf("unexpected acme categories: %+v", acme)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// PrivacyService - Crypto-shredding işlemleri ("forget user")
+// Event'ler silinmez; aggregate'in veri anahtarı yok edilir ve PII alanları bir daha çözülemez
+type PrivacyService struct {
+	keyRepo      repository.KeyStore
+	snapshotRepo repository.SnapshotStore
+	eventRepo    repository.EventStore
+}
+
+func NewPrivacyService(keyRepo repository.KeyStore, snapshotRepo repository.SnapshotStore, eventRepo repository.EventStore) *PrivacyService {
+	return &PrivacyService{
+		keyRepo:      keyRepo,
+		snapshotRepo: snapshotRepo,
+		eventRepo:    eventRepo,
+	}
+}
+
+// ForgetUser - Tenant'ın aggregate'inin anahtarını yok eder ve snapshot'larını siler
+// Sonraki replay'ler, snapshot'lar ve HTTP/gRPC okumaları PII alanlarını maskelenmiş döner
+func (s *PrivacyService) ForgetUser(tenantID, aggregateID string) error {
+	if err := s.requireStream(tenantID, aggregateID); err != nil {
+		return err
+	}
+
+	if err := s.keyRepo.DestroyKey(aggregateID); err != nil {
+		return fmt.Errorf("failed to destroy data key: %w", err)
+	}
+
+	// Snapshot'lar anahtar olmadan zaten okunamaz; şifreleme öncesi düz yazılmış olanlar için de silinir
+	if err := s.snapshotRepo.DeleteSnapshots(tenantID, aggregateID); err != nil {
+		return fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+
+	log.Printf("aggregate %s forgotten (data key destroyed, snapshots deleted)", aggregateID)
+	return nil
+}
+
+// IsForgotten - Tenant'ın aggregate'inin anahtarı yok edilmiş mi?
+func (s *PrivacyService) IsForgotten(tenantID, aggregateID string) (bool, error) {
+	if err := s.requireStream(tenantID, aggregateID); err != nil {
+		return false, err
+	}
+
+	_, err := s.keyRepo.GetKey(aggregateID)
+	switch {
+	case errors.Is(err, repository.ErrKeyDestroyed):
+		return true, nil
+	case err == nil, errors.Is(err, repository.ErrKeyNotFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to get data key: %w", err)
+	}
+}
+
+// requireStream - Aggregate'in bu tenant'ta event'i yoksa ErrAggregateNotFound
+// Anahtarlar aggregate ID ile tutulduğu için başka tenant'ın anahtarına dokunulmaz
+func (s *PrivacyService) requireStream(tenantID, aggregateID string) error {
+	version, err := s.eventRepo.GetLatestVersionForAggregate(aggregateID)
+	if err != nil {
+		return fmt.Errorf("failed to check aggregate: %w", err)
+	}
+	exists := false
+	if version > 0 {
+		if exists, err = streamExists(s.eventRepo, tenantID, aggregateID, version); err != nil {
+			return fmt.Errorf("failed to check aggregate: %w", err)
+		}
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
+	}
+	return nil
+}
@@ -6,126 +6,123 @@ import (
 	"time"
 
 	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
 	"github.com/eyupaydin41/event-store/repository"
 )
 
 // ReplayService - Event replay ve time travel işlemleri
+// State stream'in aggregate tipine kayıtlı reducer ile kurulur; aggregateType boşsa stream'in kendi tipi
 type ReplayService struct {
-	repo *repository.EventRepository
+	repo     repository.EventStore
+	reducers *reducer.Registry
 }
 
-func NewReplayService(repo *repository.EventRepository) *ReplayService {
-	return &ReplayService{repo: repo}
+func NewReplayService(repo repository.EventStore, reducers *reducer.Registry) *ReplayService {
+	return &ReplayService{repo: repo, reducers: reducers}
 }
 
-// ReplayUserState - Belirli bir user'ın mevcut durumunu event'lerden reconstruct eder
-func (s *ReplayService) ReplayUserState(userID string) (*model.UserAggregate, error) {
-	filter := model.EventFilter{
-		AggregateID: userID,
-		Limit:       10000,
-	}
-
-	events, err := s.repo.GetEvents(filter)
+// ReplayState - Aggregate'in mevcut durumunu event'lerden reconstruct eder
+func (s *ReplayService) ReplayState(tenantID, aggregateType, aggregateID string) (reducer.State, error) {
+	r, err := streamReducer(s.repo, s.reducers, tenantID, aggregateType, aggregateID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
+		return nil, err
 	}
 
-	if len(events) == 0 {
-		return nil, fmt.Errorf("no events found for user: %s", userID)
-	}
-
-	// Boş aggregate ile başla
-	aggregate := model.NewUserAggregate()
-
-	// Tüm event'leri sırayla uygula
-	for _, event := range events {
-		if err := aggregate.ApplyEvent(event); err != nil {
-			log.Printf("Warning: failed to apply event %s: %v", event.ID, err)
-			continue
-		}
+	// Boş state ile başla, tüm event'leri sırayla uygula
+	state := r.New(aggregateID)
+	read, err := applyStream(s.repo, r, state, model.StreamQuery{TenantID: tenantID, AggregateID: aggregateID}, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Replayed %d events for user %s (version: %d)", aggregate.EventCount, userID, aggregate.Version)
-	return aggregate, nil
+	log.Printf("Replayed %d events for %s %s (version: %d)", read, r.AggregateType, aggregateID, state.StreamVersion())
+	return state, nil
 }
 
-// ReplayUserStateAt - Belirli bir zamandaki user state'ini gösterir (TIME TRAVEL!)
-func (s *ReplayService) ReplayUserStateAt(userID string, pointInTime time.Time) (*model.UserAggregate, error) {
-	// Belirli zamana kadar olan event'leri al
-	filter := model.EventFilter{
-		AggregateID: userID,
-		EndTime:     pointInTime,
-		Limit:       10000,
+// ReplayStateAt - Belirli bir zamandaki aggregate state'ini gösterir (TIME TRAVEL!)
+func (s *ReplayService) ReplayStateAt(tenantID, aggregateType, aggregateID string, pointInTime time.Time) (reducer.State, error) {
+	r, err := streamReducer(s.repo, s.reducers, tenantID, aggregateType, aggregateID)
+	if err != nil {
+		return nil, err
 	}
 
-	events, err := s.repo.GetEvents(filter)
+	// Boş state ile başla, belirli zamana kadar olan event'leri uygula
+	state := r.New(aggregateID)
+	read, err := applyStream(s.repo, r, state, model.StreamQuery{TenantID: tenantID, AggregateID: aggregateID, EndTime: pointInTime}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
+		return nil, err
 	}
 
-	if len(events) == 0 {
-		return nil, fmt.Errorf("no events found for user %s before %v", userID, pointInTime)
+	if read == 0 {
+		return nil, fmt.Errorf("%w: no events for %s before %v", ErrAggregateNotFound, aggregateID, pointInTime)
 	}
 
-	// Boş aggregate ile başla
-	aggregate := model.NewUserAggregate()
-
-	// Zamana kadar olan event'leri uygula
-	for _, event := range events {
-		if err := aggregate.ApplyEvent(event); err != nil {
-			log.Printf("Warning: failed to apply event %s: %v", event.ID, err)
-			continue
-		}
-	}
-
-	log.Printf("Time travel: Replayed %d events for user %s at %v (version: %d)",
-		aggregate.EventCount, userID, pointInTime, aggregate.Version)
-	return aggregate, nil
+	log.Printf("Time travel: Replayed %d events for %s %s at %v (version: %d)",
+		read, r.AggregateType, aggregateID, pointInTime, state.StreamVersion())
+	return state, nil
 }
 
-// GetUserHistory - Kullanıcının tüm değişiklik geçmişini döner
-func (s *ReplayService) GetUserHistory(userID string) ([]*model.UserAggregate, error) {
-	filter := model.EventFilter{
-		AggregateID: userID,
-		Limit:       10000,
+// GetHistory - Aggregate'in değişiklik geçmişini fromVersion'dan itibaren (dahil) limit kadar döner
+// Önceki version'lar state'i kurmak için uygulanır ama listeye eklenmez.
+// Daha fazla geçmiş varsa bir sonraki sayfanın başlayacağı version'ı, yoksa 0 döner
+func (s *ReplayService) GetHistory(tenantID, aggregateType, aggregateID string, fromVersion uint32, limit int) ([]reducer.State, uint32, error) {
+	r, err := streamReducer(s.repo, s.reducers, tenantID, aggregateType, aggregateID)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	events, err := s.repo.GetEvents(filter)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
+	if fromVersion == 0 {
+		fromVersion = 1
 	}
 
-	if len(events) == 0 {
-		return nil, fmt.Errorf("no events found for user: %s", userID)
+	query := model.StreamQuery{TenantID: tenantID, AggregateID: aggregateID}
+	if limit > 0 {
+		query.ToVersion = fromVersion + uint32(limit) - 1
 	}
 
-	// Her event sonrası state'i kaydet
-	var history []*model.UserAggregate
-	aggregate := model.NewUserAggregate()
+	// Her event sonrası state'in bir kopyasını kaydet
+	var history []reducer.State
+	state := r.New(aggregateID)
 
-	for _, event := range events {
-		if err := aggregate.ApplyEvent(event); err != nil {
-			log.Printf("Warning: failed to apply event %s: %v", event.ID, err)
-			continue
+	_, err = applyStream(s.repo, r, state, query, func() error {
+		if state.StreamVersion() < fromVersion {
+			return nil
+		}
+		snapshot, err := r.Copy(aggregateID, state)
+		if err != nil {
+			return err
 		}
+		history = append(history, snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
 
-		// State'in bir kopyasını kaydet
-		snapshot := *aggregate
-		history = append(history, &snapshot)
+	// Stream tenant filtresiyle okunup bulunduğu için aggregate bu tenant'ındır
+	var nextVersion uint32
+	if query.ToVersion > 0 {
+		latest, err := s.repo.GetLatestVersionForAggregate(aggregateID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get latest version: %w", err)
+		}
+		if latest > query.ToVersion {
+			nextVersion = query.ToVersion + 1
+		}
 	}
 
-	log.Printf("Retrieved %d state snapshots for user %s", len(history), userID)
-	return history, nil
+	log.Printf("Retrieved %d state snapshots for %s %s", len(history), r.AggregateType, aggregateID)
+	return history, nextVersion, nil
 }
 
 // CompareStates - İki farklı zamandaki state'leri karşılaştırır
-func (s *ReplayService) CompareStates(userID string, time1, time2 time.Time) (before, after *model.UserAggregate, err error) {
-	before, err = s.ReplayUserStateAt(userID, time1)
+func (s *ReplayService) CompareStates(tenantID, aggregateType, aggregateID string, time1, time2 time.Time) (before, after reducer.State, err error) {
+	before, err = s.ReplayStateAt(tenantID, aggregateType, aggregateID, time1)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get state at %v: %w", time1, err)
 	}
 
-	after, err = s.ReplayUserStateAt(userID, time2)
+	after, err = s.ReplayStateAt(tenantID, aggregateType, aggregateID, time2)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get state at %v: %w", time2, err)
 	}
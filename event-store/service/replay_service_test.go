@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/catalog"
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// seedLongStream - Eski 10000 event sınırını aşan bir stream yazar; son event e-postayı değiştirir
+func seedLongStream(t *testing.T, repo repository.EventStore, aggregateID string, count int) {
+	t.Helper()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := make([]*model.Event, count)
+	for i := range events {
+		events[i] = &model.Event{
+			ID:          fmt.Sprintf("%s-%d", aggregateID, i+1),
+			EventType:   "user.login.recorded",
+			AggregateID: aggregateID,
+			Payload:     `{}`,
+			Timestamp:   start.Add(time.Duration(i) * time.Second),
+			Version:     uint32(i + 1),
+			Position:    uint64(i + 1),
+		}
+	}
+	events[0].EventType = "user.created"
+	events[0].Payload = `{"email":"first@example.com"}`
+	events[count-1].EventType = "user.email.changed"
+	events[count-1].Payload = `{"new_email":"last@example.com"}`
+
+	if err := repo.SaveEvents(events); err != nil {
+		t.Fatalf("SaveEvents: %v", err)
+	}
+}
+
+func TestReplayDoesNotTruncateLongStreams(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 12000)
+
+	state, err := NewReplayService(repo, reducer.NewDefaultRegistry()).ReplayState(model.DefaultTenantID, "user", "user-1")
+	if err != nil {
+		t.Fatalf("ReplayState: %v", err)
+	}
+	aggregate := state.(*model.UserAggregate)
+	if aggregate.Version != 12000 || aggregate.Email != "last@example.com" {
+		t.Errorf("expected full replay to version 12000 with last email, got version %d email %q", aggregate.Version, aggregate.Email)
+	}
+
+	snapshots := NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, catalog.NewCatalog(), reducer.NewDefaultRegistry())
+	if err := snapshots.CreateSnapshot(model.DefaultTenantID, "user-1"); err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+	loaded, err := snapshots.LoadAggregateWithSnapshot(model.DefaultTenantID, "user-1")
+	if err != nil {
+		t.Fatalf("LoadAggregateWithSnapshot: %v", err)
+	}
+	if !loaded.FromSnapshot || loaded.State.StreamVersion() != 12000 {
+		t.Errorf("expected snapshot at version 12000, got %d (from snapshot: %v)", loaded.State.StreamVersion(), loaded.FromSnapshot)
+	}
+
+	events, err := NewEventService(repo, catalog.NewCatalog()).GetEventsByAggregateID(model.DefaultTenantID, "user-1", 0)
+	if err != nil {
+		t.Fatalf("GetEventsByAggregateID: %v", err)
+	}
+	if len(events) != 12000 {
+		t.Errorf("expected 12000 events, got %d", len(events))
+	}
+}
+
+func TestReadStreamPushesDownRangeTypeAndDirection(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 20)
+	svc := NewEventService(repo, catalog.NewCatalog())
+
+	var versions []uint32
+	err := svc.ReadStream(context.Background(), model.StreamQuery{
+		AggregateID: "user-1",
+		FromVersion: 5,
+		ToVersion:   10,
+		EventTypes:  []string{"user.login.recorded"},
+		Direction:   model.ReadBackward,
+		MaxCount:    3,
+	}, func(event *model.Event) error {
+		versions = append(versions, event.Version)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadStream: %v", err)
+	}
+
+	if fmt.Sprint(versions) != "[10 9 8]" {
+		t.Errorf("expected [10 9 8], got %v", versions)
+	}
+
+	if err := svc.ReadStream(context.Background(), model.StreamQuery{AggregateID: "user-1", FromVersion: 8, ToVersion: 3}, func(*model.Event) error {
+		return nil
+	}); err == nil {
+		t.Error("expected error for inverted version range")
+	}
+}
+
+func TestGetHistoryPagesByVersion(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 5)
+	replay := NewReplayService(repo, reducer.NewDefaultRegistry())
+
+	first, next, err := replay.GetHistory(model.DefaultTenantID, "user", "user-1", 0, 2)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(first) != 2 || first[1].StreamVersion() != 2 || next != 3 {
+		t.Fatalf("expected versions 1-2 and next 3, got %d states, next %d", len(first), next)
+	}
+
+	last, next, err := replay.GetHistory(model.DefaultTenantID, "user", "user-1", 5, 2)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	// Sayfa ortadan başlasa da state önceki event'lerden kurulmuş olmalı
+	if len(last) != 1 || next != 0 {
+		t.Fatalf("expected final state at version 5 without next, got %d states next %d", len(last), next)
+	}
+	if final := last[0].(*model.UserAggregate); final.Email != "last@example.com" || final.EventCount != 5 {
+		t.Errorf("expected final state at version 5 without next, got %+v next %d", last, next)
+	}
+}
+
+func TestReplayReportsUnknownEventTypesAndTypeMismatch(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 3)
+	stray := &model.Event{ID: "user-1-4", EventType: "user.updated", AggregateID: "user-1", Payload: `{}`, Timestamp: time.Now(), Version: 4, Position: 4}
+	if err := repo.SaveEvent(stray); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+	replay := NewReplayService(repo, reducer.NewDefaultRegistry())
+
+	if _, err := replay.ReplayState(model.DefaultTenantID, "user", "user-1"); !errors.Is(err, reducer.ErrUnknownEventType) {
+		t.Errorf("expected ErrUnknownEventType, got %v", err)
+	}
+	if _, err := replay.ReplayState(model.DefaultTenantID, "order", "user-1"); !errors.Is(err, ErrAggregateTypeMismatch) {
+		t.Errorf("expected ErrAggregateTypeMismatch, got %v", err)
+	}
+	if _, err := replay.ReplayState(model.DefaultTenantID, "user", "user-2"); !errors.Is(err, ErrAggregateNotFound) {
+		t.Errorf("expected ErrAggregateNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eyupaydin41/event-store/catalog"
+)
+
+// Snapshot tetikleyicileri (SnapshotPolicyStatus.Reason)
+const (
+	SnapshotReasonStale        = "stale_snapshot"
+	SnapshotReasonTriggerEvent = "trigger_event_type"
+	SnapshotReasonFirstAfter   = "first_after"
+	SnapshotReasonInterval     = "interval"
+	SnapshotReasonMaxAge       = "max_age"
+	SnapshotReasonReplayBytes  = "max_replay_bytes"
+)
+
+// SnapshotPolicyStatus - Aggregate'e uygulanan snapshot politikası ve şu anki değerlendirmesi
+type SnapshotPolicyStatus struct {
+	AggregateID string                 `json:"aggregate_id"`
+	Policy      catalog.SnapshotPolicy `json:"policy"`
+	// HasReducer - Tipin reducer'ı yoksa politika ne derse desin snapshot alınmaz
+	HasReducer      bool       `json:"has_reducer"`
+	LatestVersion   uint32     `json:"latest_version"`
+	HasSnapshot     bool       `json:"has_snapshot"`
+	SnapshotVersion uint32     `json:"snapshot_version,omitempty"`
+	SnapshotAt      *time.Time `json:"snapshot_at,omitempty"`
+	// SnapshotStale - Son snapshot reducer'ın eski bir sürümüyle alınmış; yüklenmez, ilk fırsatta yenilenir
+	SnapshotStale bool `json:"snapshot_stale,omitempty"`
+	// EventsSinceSnapshot - Son snapshot'tan (yoksa stream başından) sonraki event sayısı
+	EventsSinceSnapshot uint32 `json:"events_since_snapshot"`
+	// ReplayBytes - Bu event'lerin payload toplamı; sadece politikada max_replay_bytes varsa hesaplanır
+	ReplayBytes uint64 `json:"replay_bytes,omitempty"`
+	// Due - Şimdi snapshot alınmalı mı? Reason tutan ilk tetikleyici
+	Due    bool   `json:"due"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// EvaluateSnapshotPolicy - Aggregate'in tipine uygulanan politikayı değerlendirir
+// eventType az önce yazılan event'in tipidir (trigger_event_types için); API'den sorulurken boştur
+func (s *SnapshotService) EvaluateSnapshotPolicy(tenantID, aggregateID, eventType string) (*SnapshotPolicyStatus, error) {
+	latestVersion, err := s.eventRepo.GetLatestVersionForAggregate(aggregateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest version: %w", err)
+	}
+	if latestVersion == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
+	}
+
+	head, owned, err := streamHead(s.eventRepo, tenantID, aggregateID, latestVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
+	}
+
+	// Head'i arşivde olan stream'in tipi okunamaz; varsayılan tipin politikası uygulanır
+	var aggregateType string
+	if head != nil {
+		aggregateType = head.AggregateType
+	}
+
+	policy := s.types.SnapshotPolicy(aggregateType)
+	status := &SnapshotPolicyStatus{
+		AggregateID:   aggregateID,
+		Policy:        policy,
+		HasReducer:    s.reducers.Has(policy.AggregateType),
+		LatestVersion: latestVersion,
+	}
+
+	hasSnapshot, err := s.snapshotRepo.HasSnapshot(tenantID, aggregateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check snapshot: %w", err)
+	}
+
+	// Son snapshot'ın (yoksa stream başının) zamanı max_age için
+	var since time.Time
+	if hasSnapshot {
+		snapshot, err := s.snapshotRepo.GetLatestSnapshot(tenantID, aggregateID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest snapshot: %w", err)
+		}
+		status.HasSnapshot = true
+		status.SnapshotVersion = snapshot.Version
+		status.SnapshotAt = &snapshot.CreatedAt
+		since = snapshot.CreatedAt
+
+		if r, err := s.reducers.Get(policy.AggregateType); err == nil && !r.Current(snapshot) {
+			status.SnapshotStale = true
+		}
+	}
+	if latestVersion > status.SnapshotVersion {
+		status.EventsSinceSnapshot = latestVersion - status.SnapshotVersion
+	}
+
+	if policy.MaxReplayBytes > 0 || (policy.MaxAge > 0 && !hasSnapshot) {
+		first, bytes, err := s.replayCost(tenantID, aggregateID, status.SnapshotVersion+1)
+		if err != nil {
+			return nil, err
+		}
+		status.ReplayBytes = bytes
+		if !hasSnapshot {
+			since = first
+		}
+	}
+
+	status.Reason = dueReason(policy.SnapshotSettings, status, eventType, since)
+	status.Due = status.HasReducer && status.Reason != ""
+	return status, nil
+}
+
+// dueReason - Tutan ilk tetikleyici; son snapshot'tan sonra event yoksa hiçbiri
+func dueReason(settings catalog.SnapshotSettings, status *SnapshotPolicyStatus, eventType string, since time.Time) string {
+	if status.SnapshotStale {
+		return SnapshotReasonStale
+	}
+	if status.EventsSinceSnapshot == 0 {
+		return ""
+	}
+
+	switch {
+	case eventType != "" && settings.Triggers(eventType):
+		return SnapshotReasonTriggerEvent
+	case !status.HasSnapshot && settings.FirstAfter > 0 && status.LatestVersion >= settings.FirstAfter:
+		return SnapshotReasonFirstAfter
+	case status.HasSnapshot && settings.Interval > 0 && status.EventsSinceSnapshot >= settings.Interval:
+		return SnapshotReasonInterval
+	case settings.MaxAge > 0 && !since.IsZero() && time.Since(since) >= time.Duration(settings.MaxAge):
+		return SnapshotReasonMaxAge
+	case settings.MaxReplayBytes > 0 && status.ReplayBytes >= settings.MaxReplayBytes:
+		return SnapshotReasonReplayBytes
+	}
+	return ""
+}
+
+// replayCost - fromVersion'dan itibaren replay edilecek event'lerin ilkinin zamanı ve payload toplamı
+func (s *SnapshotService) replayCost(tenantID, aggregateID string, fromVersion uint32) (time.Time, uint64, error) {
+	// Event'ler okunmaz; boyut ve ilk zaman storage'da toplanır
+	tail, err := s.eventRepo.StreamTail(tenantID, aggregateID, fromVersion)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to measure replay cost of %s: %w", aggregateID, err)
+	}
+	return tail.FirstAt, tail.Bytes, nil
+}
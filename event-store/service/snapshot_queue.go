@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// snapshotKey - Snapshot'ı alınacak stream
+type snapshotKey struct {
+	TenantID    string
+	AggregateID string
+}
+
+// snapshotCandidate - Kuyruktan çıkan stream ve birleştirilen event'ler arasındaki trigger event tipi
+type snapshotCandidate struct {
+	snapshotKey
+	// EventType - Politikada trigger olan bir event geldiyse onun tipi, yoksa boş
+	EventType string
+}
+
+// snapshotQueue - Snapshot'ı alınacak stream'lerin aggregate başına birleştirilen FIFO kuyruğu
+// Kuyrukta bekleyen bir aggregate tekrar eklenirse yeni kayıt açılmaz; limit dolunca ekleme reddedilir.
+// Worker'ın elindeki (done çağrılmamış) aggregate tekrar eklenirse iş bitince kuyruğa döner;
+// böylece aynı stream'i iki worker aynı anda işlemez
+type snapshotQueue struct {
+	mu sync.Mutex
+	// pending - Kuyrukta bekleyenler ve trigger event tipleri
+	pending map[snapshotKey]string
+	order   []snapshotKey
+	// active - Worker'da olanlar; değer, işlenirken gelen tekrar (varsa trigger tipiyle)
+	active map[snapshotKey]*string
+	limit  int
+	// ready - Kuyruk boşken bekleyen worker'ı uyandırır
+	ready chan struct{}
+}
+
+func newSnapshotQueue(limit int) *snapshotQueue {
+	return &snapshotQueue{
+		pending: make(map[snapshotKey]string),
+		active:  make(map[snapshotKey]*string),
+		limit:   limit,
+		ready:   make(chan struct{}, 1),
+	}
+}
+
+// push - Stream'i kuyruğa ekler; zaten bekliyorsa birleştirilir (trigger tipi korunur). Kuyruk doluysa false
+func (q *snapshotQueue) push(tenantID, aggregateID, eventType string) bool {
+	key := snapshotKey{TenantID: model.NormalizeTenantID(tenantID), AggregateID: aggregateID}
+
+	q.mu.Lock()
+	if again, ok := q.active[key]; ok {
+		if again == nil {
+			again = new(string)
+			q.active[key] = again
+		}
+		if eventType != "" {
+			*again = eventType
+		}
+		q.mu.Unlock()
+		return true
+	}
+	if trigger, ok := q.pending[key]; ok {
+		if trigger == "" {
+			q.pending[key] = eventType
+		}
+		q.mu.Unlock()
+		return true
+	}
+	if q.limit > 0 && len(q.order) >= q.limit {
+		q.mu.Unlock()
+		return false
+	}
+	q.pending[key] = eventType
+	q.order = append(q.order, key)
+	q.mu.Unlock()
+
+	q.signal()
+	return true
+}
+
+// pop - Sıradaki stream; kuyruk boşsa eklenene ya da ctx bitene kadar bekler
+// Dönen stream işlenince done çağrılmalıdır
+func (q *snapshotQueue) pop(ctx context.Context) (snapshotCandidate, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.order) > 0 {
+			key := q.order[0]
+			q.order = q.order[1:]
+			candidate := snapshotCandidate{snapshotKey: key, EventType: q.pending[key]}
+			delete(q.pending, key)
+			q.active[key] = nil
+			more := len(q.order) > 0
+			q.mu.Unlock()
+
+			// Kuyrukta kalan varsa bekleyen diğer worker'ı da uyandır
+			if more {
+				q.signal()
+			}
+			return candidate, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return snapshotCandidate{}, false
+		case <-q.ready:
+		}
+	}
+}
+
+// done - Stream'in işi bitti; işlenirken tekrar eklendiyse kuyruğun sonuna döner
+func (q *snapshotQueue) done(key snapshotKey) {
+	q.mu.Lock()
+	again := q.active[key]
+	delete(q.active, key)
+	if again == nil {
+		q.mu.Unlock()
+		return
+	}
+	q.pending[key] = *again
+	q.order = append(q.order, key)
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+func (q *snapshotQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// len - Kuyrukta bekleyen stream sayısı
+func (q *snapshotQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.order)
+}
+
+// streamLocks - Stream başına kilit; stale ve candidate kuyruklarının worker'ları aynı stream'i
+// aynı anda işlemesin diye ikisi de kullanır (her kuyruk kendi içinde zaten tek worker'a verir)
+type streamLocks struct {
+	mu    sync.Mutex
+	locks map[snapshotKey]*streamLock
+}
+
+// streamLock - Kilidi tutan ya da bekleyen worker sayısıyla; kimse kalmayınca map'ten silinir
+type streamLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock - Stream'in kilidini alır (başka worker'daysa bekler); dönen fonksiyon kilidi bırakır
+func (l *streamLocks) lock(key snapshotKey) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[snapshotKey]*streamLock)
+	}
+	stream, ok := l.locks[key]
+	if !ok {
+		stream = &streamLock{}
+		l.locks[key] = stream
+	}
+	stream.refs++
+	l.mu.Unlock()
+
+	stream.mu.Lock()
+	return func() {
+		stream.mu.Unlock()
+
+		l.mu.Lock()
+		stream.refs--
+		if stream.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/google/uuid"
+)
+
+// ErrRebuildNotFound - Tenant'ın bu ID'de bir rebuild işi yok
+var ErrRebuildNotFound = errors.New("snapshot rebuild not found")
+
+// ErrRebuildRunning - İş hâlâ çalışıyor (devam ettirilemez)
+var ErrRebuildRunning = errors.New("snapshot rebuild is still running")
+
+const (
+	// rebuildPageSize - Stream'ler bu büyüklükte sayfalarla okunur; cursor sayfa sonlarında ilerler
+	rebuildPageSize = 500
+	// DefaultRebuildWorkers ve MaxRebuildWorkers - Aynı anda snapshot'ı alınan stream sayısı
+	DefaultRebuildWorkers = 4
+	MaxRebuildWorkers     = 32
+	// maxRebuildErrors - İşin durumunda saklanan son hata sayısı
+	maxRebuildErrors = 20
+	// maxRebuildJobs - Bellekte tutulan iş sayısı; aşılınca en eski bitmiş işler silinir
+	maxRebuildJobs = 100
+)
+
+// Rebuild işinin durumları
+const (
+	RebuildRunning   = "running"
+	RebuildCompleted = "completed"
+	RebuildCancelled = "cancelled"
+	RebuildFailed    = "failed"
+)
+
+// SnapshotRebuildRequest - Snapshot'ları yeniden oluşturulacak stream'ler
+// Filtre verilmezse tenant'ın tüm stream'leri; After verilirse o aggregate ID'den sonrakiler (devam)
+type SnapshotRebuildRequest struct {
+	TenantID      string    `json:"tenant_id"`
+	Category      string    `json:"category,omitempty"`
+	AggregateType string    `json:"aggregate_type,omitempty"`
+	ActiveSince   time.Time `json:"active_since,omitempty"`
+	After         string    `json:"after,omitempty"`
+	Workers       int       `json:"workers"`
+}
+
+// SnapshotRebuild - Bir rebuild işinin ilerlemesi
+type SnapshotRebuild struct {
+	ID      string                 `json:"id"`
+	Request SnapshotRebuildRequest `json:"request"`
+	State   string                 `json:"state"`
+	// Processed = Rebuilt + Skipped (reducer'ı olmayan tip) + Failed
+	Processed uint64 `json:"processed"`
+	Rebuilt   uint64 `json:"rebuilt"`
+	Skipped   uint64 `json:"skipped"`
+	Failed    uint64 `json:"failed"`
+	// Cursor - Bu aggregate ID'ye kadar (dahil) tüm stream'ler işlendi; devam eden iş buradan başlar
+	Cursor     string     `json:"cursor,omitempty"`
+	Errors     []string   `json:"errors,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// ResumedFrom - Devam ettirilen işin ID'si
+	ResumedFrom string `json:"resumed_from,omitempty"`
+}
+
+// rebuildJob - Çalışan ya da bitmiş bir iş
+type rebuildJob struct {
+	mu     sync.Mutex
+	status SnapshotRebuild
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (j *rebuildJob) snapshot() *SnapshotRebuild {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := j.status
+	status.Errors = append([]string(nil), j.status.Errors...)
+	return &status
+}
+
+func (j *rebuildJob) update(fn func(status *SnapshotRebuild)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.status)
+}
+
+// SnapshotRebuilder - Çok sayıda stream'in snapshot'ını sınırlı bir worker havuzuyla yeniden oluşturur
+// Reducer değişikliği ya da restore sonrası kullanılır. İşler bellekte tutulur (en fazla maxRebuildJobs);
+// servis yeniden başlarsa işler kaybolur, loglanan son Cursor'dan After ile yeni bir iş başlatılarak
+// kalınan yerden devam edilir. Arşivlenmiş stream'ler archive.Store'un ListStreams'inden gelir
+type SnapshotRebuilder struct {
+	snapshots *SnapshotService
+
+	mu   sync.Mutex
+	jobs map[string]*rebuildJob
+}
+
+func NewSnapshotRebuilder(snapshots *SnapshotService) *SnapshotRebuilder {
+	return &SnapshotRebuilder{snapshots: snapshots, jobs: make(map[string]*rebuildJob)}
+}
+
+// Start - İşi arka planda başlatır ve ilk durumunu döner
+func (b *SnapshotRebuilder) Start(request SnapshotRebuildRequest) (*SnapshotRebuild, error) {
+	return b.start(request, "")
+}
+
+func (b *SnapshotRebuilder) start(request SnapshotRebuildRequest, resumedFrom string) (*SnapshotRebuild, error) {
+	request.TenantID = model.NormalizeTenantID(request.TenantID)
+	if request.Workers <= 0 {
+		request.Workers = DefaultRebuildWorkers
+	}
+	if request.Workers > MaxRebuildWorkers {
+		return nil, fmt.Errorf("workers must be at most %d", MaxRebuildWorkers)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &rebuildJob{
+		status: SnapshotRebuild{
+			ID:          uuid.New().String(),
+			Request:     request,
+			State:       RebuildRunning,
+			Cursor:      request.After,
+			StartedAt:   time.Now(),
+			ResumedFrom: resumedFrom,
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.jobs[job.status.ID] = job
+	b.pruneJobs()
+	b.mu.Unlock()
+
+	go b.run(ctx, job)
+	return job.snapshot(), nil
+}
+
+// pruneJobs - maxRebuildJobs aşıldıysa en eski bitmiş işleri siler; çalışan işler silinmez (b.mu tutulurken)
+func (b *SnapshotRebuilder) pruneJobs() {
+	if len(b.jobs) <= maxRebuildJobs {
+		return
+	}
+
+	var finished []*SnapshotRebuild
+	for _, job := range b.jobs {
+		if status := job.snapshot(); status.State != RebuildRunning {
+			finished = append(finished, status)
+		}
+	}
+	sortRebuilds(finished)
+	for i := len(finished) - 1; i >= 0 && len(b.jobs) > maxRebuildJobs; i-- {
+		delete(b.jobs, finished[i].ID)
+	}
+}
+
+// Get - Tenant'ın işinin durumu
+func (b *SnapshotRebuilder) Get(tenantID, id string) (*SnapshotRebuild, error) {
+	job, err := b.job(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return job.snapshot(), nil
+}
+
+// List - Tenant'ın işleri, en yenisi önce
+func (b *SnapshotRebuilder) List(tenantID string) []*SnapshotRebuild {
+	tenantID = model.NormalizeTenantID(tenantID)
+
+	b.mu.Lock()
+	jobs := make([]*rebuildJob, 0, len(b.jobs))
+	for _, job := range b.jobs {
+		jobs = append(jobs, job)
+	}
+	b.mu.Unlock()
+
+	var rebuilds []*SnapshotRebuild
+	for _, job := range jobs {
+		if status := job.snapshot(); status.Request.TenantID == tenantID {
+			rebuilds = append(rebuilds, status)
+		}
+	}
+	sortRebuilds(rebuilds)
+	return rebuilds
+}
+
+// Cancel - Çalışan işi durdurur; worker'lar ellerindeki stream'i bitirip çıkar
+// Cursor son tamamlanan sayfada kalır, Resume oradan devam eder
+func (b *SnapshotRebuilder) Cancel(tenantID, id string) (*SnapshotRebuild, error) {
+	job, err := b.job(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	job.cancel()
+	<-job.done
+	return job.snapshot(), nil
+}
+
+// Resume - İptal edilmiş ya da hata almış işi aynı filtrelerle Cursor'dan devam ettiren yeni iş
+func (b *SnapshotRebuilder) Resume(tenantID, id string) (*SnapshotRebuild, error) {
+	previous, err := b.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if previous.State == RebuildRunning {
+		return nil, fmt.Errorf("%w: %s", ErrRebuildRunning, id)
+	}
+
+	request := previous.Request
+	request.After = previous.Cursor
+	return b.start(request, previous.ID)
+}
+
+// Wait - İş bitene ya da ctx bitene kadar bekler
+func (b *SnapshotRebuilder) Wait(ctx context.Context, tenantID, id string) (*SnapshotRebuild, error) {
+	job, err := b.job(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-job.done:
+		return job.snapshot(), nil
+	case <-ctx.Done():
+		return job.snapshot(), ctx.Err()
+	}
+}
+
+func (b *SnapshotRebuilder) job(tenantID, id string) (*rebuildJob, error) {
+	b.mu.Lock()
+	job, ok := b.jobs[id]
+	b.mu.Unlock()
+
+	// Başka tenant'ın işi bulunamamış gibi görünür
+	if !ok || job.snapshot().Request.TenantID != model.NormalizeTenantID(tenantID) {
+		return nil, fmt.Errorf("%w: %s", ErrRebuildNotFound, id)
+	}
+	return job, nil
+}
+
+// run - Stream'leri aggregate ID sırasıyla sayfa sayfa okur, her sayfayı worker havuzuyla işler
+// Cursor bir sayfa tamamen bitince ilerler; iptal edilen sayfanın işlenmiş stream'leri devamda
+// tekrar oluşturulur (aynı version'daki snapshot'ın üzerine yazılır)
+func (b *SnapshotRebuilder) run(ctx context.Context, job *rebuildJob) {
+	defer close(job.done)
+	defer job.cancel()
+
+	request := job.snapshot().Request
+	query := model.StreamListQuery{
+		TenantID:         request.TenantID,
+		Category:         request.Category,
+		AggregateType:    request.AggregateType,
+		ActiveSince:      request.ActiveSince,
+		AfterAggregateID: request.After,
+		Limit:            rebuildPageSize,
+	}
+
+	state, runErr := RebuildCompleted, error(nil)
+	for {
+		if ctx.Err() != nil {
+			state = RebuildCancelled
+			break
+		}
+
+		streams, err := b.snapshots.eventRepo.ListStreams(query)
+		if err != nil {
+			state, runErr = RebuildFailed, fmt.Errorf("failed to list streams: %w", err)
+			break
+		}
+		if len(streams) == 0 {
+			break
+		}
+
+		b.rebuildPage(ctx, job, request, streams)
+		if ctx.Err() != nil {
+			state = RebuildCancelled
+			break
+		}
+
+		query.AfterAggregateID = streams[len(streams)-1].AggregateID
+		job.update(func(status *SnapshotRebuild) {
+			status.Cursor = query.AfterAggregateID
+		})
+		if len(streams) < rebuildPageSize {
+			break
+		}
+	}
+
+	job.update(func(status *SnapshotRebuild) {
+		finished := time.Now()
+		status.State = state
+		status.FinishedAt = &finished
+		if runErr != nil {
+			status.Error = runErr.Error()
+		}
+	})
+
+	final := job.snapshot()
+	log.Printf("Snapshot rebuild %s %s: %d processed, %d rebuilt, %d skipped, %d failed (cursor %q)",
+		final.ID, final.State, final.Processed, final.Rebuilt, final.Skipped, final.Failed, final.Cursor)
+}
+
+// rebuildPage - Sayfadaki stream'lerin snapshot'larını request.Workers worker ile oluşturur
+func (b *SnapshotRebuilder) rebuildPage(ctx context.Context, job *rebuildJob, request SnapshotRebuildRequest, streams []*model.StreamInfo) {
+	work := make(chan *model.StreamInfo)
+	var wg sync.WaitGroup
+
+	for i := 0; i < request.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stream := range work {
+				b.rebuildStream(job, request.TenantID, stream)
+			}
+		}()
+	}
+
+	for _, stream := range streams {
+		select {
+		case <-ctx.Done():
+		case work <- stream:
+			continue
+		}
+		break
+	}
+	close(work)
+	wg.Wait()
+}
+
+func (b *SnapshotRebuilder) rebuildStream(job *rebuildJob, tenantID string, stream *model.StreamInfo) {
+	if !b.snapshots.reducers.Has(stream.AggregateType) {
+		job.update(func(status *SnapshotRebuild) {
+			status.Processed++
+			status.Skipped++
+		})
+		return
+	}
+
+	err := b.snapshots.CreateSnapshot(tenantID, stream.AggregateID)
+	job.update(func(status *SnapshotRebuild) {
+		status.Processed++
+		if err == nil {
+			status.Rebuilt++
+			return
+		}
+		status.Failed++
+		status.Errors = append(status.Errors, fmt.Sprintf("%s: %v", stream.AggregateID, err))
+		if len(status.Errors) > maxRebuildErrors {
+			status.Errors = status.Errors[len(status.Errors)-maxRebuildErrors:]
+		}
+	})
+}
+
+// sortRebuilds - En yeni iş önce
+func sortRebuilds(rebuilds []*SnapshotRebuild) {
+	sort.Slice(rebuilds, func(i, j int) bool {
+		return rebuilds[i].StartedAt.After(rebuilds[j].StartedAt)
+	})
+}
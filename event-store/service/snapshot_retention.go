@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// SnapshotRetention - Her stream'in sadece en yeni keepLast snapshot'ını tutan job
+// Yükleme hep en son snapshot'ı kullandığı için eskiler sadece time travel'ı hızlandırır
+type SnapshotRetention struct {
+	store    repository.SnapshotStore
+	keepLast int
+}
+
+// RetentionResult - Bir çalıştırmada budanan stream ve silinen snapshot (version) sayısı
+type RetentionResult struct {
+	Streams int    `json:"streams"`
+	Deleted uint64 `json:"deleted"`
+}
+
+func NewSnapshotRetention(store repository.SnapshotStore, keepLast int) *SnapshotRetention {
+	if keepLast < 1 {
+		// En son snapshot her zaman kalır
+		keepLast = 1
+	}
+	return &SnapshotRetention{store: store, keepLast: keepLast}
+}
+
+// Run - keepLast'tan fazla snapshot'ı olan tüm stream'leri tek seferde budar
+func (r *SnapshotRetention) Run(ctx context.Context) (RetentionResult, error) {
+	var result RetentionResult
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	streams, deleted, err := r.store.PruneSnapshots(r.keepLast)
+	if err != nil {
+		return result, err
+	}
+	result.Streams = streams
+	result.Deleted = deleted
+	return result, nil
+}
+
+// Start - Run'ı her interval'da bir, ctx bitene kadar çalıştırır
+func (r *SnapshotRetention) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := r.Run(ctx)
+		if err != nil {
+			log.Printf("snapshot retention: %v", err)
+		}
+		if result.Deleted > 0 {
+			log.Printf("snapshot retention: deleted %d snapshots from %d streams (keeping last %d)", result.Deleted, result.Streams, r.keepLast)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
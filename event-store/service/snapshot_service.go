@@ -1,229 +1,293 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/eyupaydin41/event-store/catalog"
 	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
 	"github.com/eyupaydin41/event-store/repository"
 	"github.com/google/uuid"
 )
 
+// staleQueueLimit - Yeniden oluşturulmayı bekleyen eski sürüm snapshot'ların üst sınırı
+// Kuyruk doluyken bulunan eski snapshot'lar bir sonraki yüklemede tekrar kuyruğa eklenir
+const staleQueueLimit = 10000
+
+// candidateQueueLimit - Politikası değerlendirilmeyi bekleyen stream'lerin üst sınırı
+// Kuyruk doluyken gelen event'in stream'i atlanır; stream'e yazılan bir sonraki event onu tekrar ekler
+const candidateQueueLimit = 100000
+
 type SnapshotService struct {
-	snapshotRepo *repository.SnapshotRepository
-	eventRepo    *repository.EventRepository
+	snapshotRepo repository.SnapshotStore
+	eventRepo    repository.EventStore
+	// types - Aggregate tipi başına snapshot politikaları
+	types *catalog.Catalog
+	// reducers - Snapshot state'ini aggregate tipine göre kuran reducer'lar
+	reducers *reducer.Registry
+	// stale - Yüklenirken reducer sürümü uyuşmadığı için yok sayılan snapshot'ların stream'leri
+	stale *snapshotQueue
+	// candidates - Yeni event yazılan, snapshot politikası worker'da değerlendirilecek stream'ler
+	candidates *snapshotQueue
+	// streams - İki kuyruğun worker'ları arasında stream başına kilit (aynı version'a iki snapshot yazılmaz)
+	streams  streamLocks
+	counters snapshotCounters
 }
 
-func NewSnapshotService(snapshotRepo *repository.SnapshotRepository, eventRepo *repository.EventRepository) *SnapshotService {
+func NewSnapshotService(snapshotRepo repository.SnapshotStore, eventRepo repository.EventStore, types *catalog.Catalog, reducers *reducer.Registry) *SnapshotService {
 	return &SnapshotService{
 		snapshotRepo: snapshotRepo,
 		eventRepo:    eventRepo,
+		types:        types,
+		reducers:     reducers,
+		stale:        newSnapshotQueue(staleQueueLimit),
+		candidates:   newSnapshotQueue(candidateQueueLimit),
 	}
 }
 
-// CreateSnapshot - Aggregate için snapshot oluşturur
-func (s *SnapshotService) CreateSnapshot(aggregateID string) error {
-	// 1. Aggregate için tüm event'leri al
-	filter := model.EventFilter{
-		AggregateID: aggregateID,
-		Limit:       10000,
-	}
+// LoadedAggregate - Snapshot + sonraki event'lerden yüklenen aggregate
+type LoadedAggregate struct {
+	AggregateType string
+	State         reducer.State
+	// FromSnapshot - Yükleme bir snapshot'tan başladı mı?
+	FromSnapshot bool
+	// EventsReplayed - State'e uygulanan event sayısı (snapshot'tan sonrakiler)
+	EventsReplayed int
+	// StaleSnapshot - Reducer'ın eski bir sürümüyle alınmış snapshot bulundu ve yok sayıldı
+	StaleSnapshot bool
+}
 
-	events, err := s.eventRepo.GetEvents(filter)
+// CreateSnapshot - Tenant'ın aggregate'i için snapshot oluşturur
+func (s *SnapshotService) CreateSnapshot(tenantID, aggregateID string) error {
+	r, err := streamReducer(s.eventRepo, s.reducers, tenantID, "", aggregateID)
 	if err != nil {
-		return fmt.Errorf("failed to get events for snapshot: %w", err)
-	}
-
-	if len(events) == 0 {
-		return fmt.Errorf("no events found for aggregate: %s", aggregateID)
+		return fmt.Errorf("failed to build state for snapshot: %w", err)
 	}
+	return s.createSnapshot(tenantID, aggregateID, r, 0)
+}
 
-	// 2. Event'lerden aggregate state'ini oluştur
-	aggregate := model.NewUserAggregate()
-	for _, event := range events {
-		if err := aggregate.ApplyEvent(event); err != nil {
-			return fmt.Errorf("failed to apply event: %w", err)
-		}
+// createSnapshot - toVersion'a kadarki (0 = son) event'lerden snapshot alır
+// Aynı version'daki mevcut snapshot'ın yerine geçer
+func (s *SnapshotService) createSnapshot(tenantID, aggregateID string, r reducer.Reducer, toVersion uint32) error {
+	// 1-2. Aggregate'in event'lerini okuyup state'i oluştur
+	state := r.New(aggregateID)
+	query := model.StreamQuery{TenantID: tenantID, AggregateID: aggregateID, ToVersion: toVersion}
+	if _, err := applyStream(s.eventRepo, r, state, query, nil); err != nil {
+		return fmt.Errorf("failed to build state for snapshot: %w", err)
 	}
 
 	// 3. Aggregate state'ini JSON'a çevir
-	stateJSON, err := json.Marshal(aggregate)
+	stateJSON, err := json.Marshal(state)
 	if err != nil {
 		return fmt.Errorf("failed to marshal aggregate state: %w", err)
 	}
 
 	// 4. Snapshot'ı kaydet
 	snapshot := &model.Snapshot{
-		ID:          uuid.New().String(),
-		AggregateID: aggregateID,
-		Version:     aggregate.Version,
-		State:       string(stateJSON),
-		CreatedAt:   time.Now(),
+		ID:            uuid.New().String(),
+		TenantID:      model.NormalizeTenantID(tenantID),
+		AggregateID:   aggregateID,
+		Version:       state.StreamVersion(),
+		State:         string(stateJSON),
+		CreatedAt:     time.Now(),
+		AggregateType: r.AggregateType,
+		StateVersion:  r.StateVersion,
 	}
 
 	if err := s.snapshotRepo.SaveSnapshot(snapshot); err != nil {
 		return fmt.Errorf("failed to save snapshot: %w", err)
 	}
 
-	log.Printf("Snapshot created for aggregate %s at version %d", aggregateID, aggregate.Version)
+	log.Printf("Snapshot created for %s %s at version %d", r.AggregateType, aggregateID, snapshot.Version)
 	return nil
 }
 
 // LoadAggregateWithSnapshot - Snapshot kullanarak aggregate'i yükler
 // Önce en son snapshot'ı alır, sonra snapshot'tan sonraki event'leri uygular
-func (s *SnapshotService) LoadAggregateWithSnapshot(aggregateID string) (*model.UserAggregate, error) {
-	// 1. En son snapshot'ı al
-	snapshot, err := s.snapshotRepo.GetLatestSnapshot(aggregateID)
-
-	var aggregate *model.UserAggregate
-
+func (s *SnapshotService) LoadAggregateWithSnapshot(tenantID, aggregateID string) (*LoadedAggregate, error) {
+	r, err := streamReducer(s.eventRepo, s.reducers, tenantID, "", aggregateID)
 	if err != nil {
-		// Snapshot yok, tüm event'leri yükle
-		log.Printf("No snapshot found for aggregate %s, loading from all events", aggregateID)
-		return s.loadFromAllEvents(aggregateID)
-	}
-
-	// 2. Snapshot'tan aggregate'i deserialize et
-	aggregate = model.NewUserAggregate()
-	if err := json.Unmarshal([]byte(snapshot.State), aggregate); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal snapshot state: %w", err)
+		return nil, err
 	}
+	loaded := &LoadedAggregate{AggregateType: r.AggregateType}
 
-	log.Printf("Loaded snapshot for aggregate %s at version %d", aggregateID, snapshot.Version)
+	// 1. En son snapshot'ı al
+	snapshot, err := s.snapshotRepo.GetLatestSnapshot(tenantID, aggregateID)
 
-	// 3. Snapshot'tan sonraki event'leri al
-	events, err := s.eventRepo.GetEventsAfterVersion(aggregateID, snapshot.Version)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get events after snapshot: %w", err)
+	var fromVersion uint32
+	if err == nil && !r.Current(snapshot) {
+		// Reducer'ın eski sürümüyle alınmış; state eksik ya da yanlış olabilir
+		loaded.StaleSnapshot = true
+		s.markStale(tenantID, r, snapshot)
 	}
 
-	// 4. Snapshot'tan sonraki event'leri uygula
-	for _, event := range events {
-		if err := aggregate.ApplyEvent(event); err != nil {
-			return nil, fmt.Errorf("failed to apply event: %w", err)
+	if err != nil || loaded.StaleSnapshot {
+		// Kullanılabilir snapshot yok, tüm event'leri yükle
+		log.Printf("No usable snapshot found for aggregate %s, loading from all events", aggregateID)
+		loaded.State = r.New(aggregateID)
+	} else {
+		// 2. Snapshot'tan state'i deserialize et
+		loaded.State, err = r.Restore(aggregateID, []byte(snapshot.State))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal snapshot state: %w", err)
 		}
-	}
-
-	log.Printf("Applied %d events after snapshot for aggregate %s", len(events), aggregateID)
-	return aggregate, nil
-}
+		loaded.FromSnapshot = true
+		fromVersion = snapshot.Version
 
-// loadFromAllEvents - Tüm event'lerden aggregate'i yükler (snapshot yoksa)
-func (s *SnapshotService) loadFromAllEvents(aggregateID string) (*model.UserAggregate, error) {
-	filter := model.EventFilter{
-		AggregateID: aggregateID,
-		Limit:       10000,
+		log.Printf("Loaded snapshot for aggregate %s at version %d", aggregateID, snapshot.Version)
 	}
 
-	events, err := s.eventRepo.GetEvents(filter)
+	// 3-4. Snapshot'tan sonraki event'leri okuyup uygula
+	query := model.StreamQuery{TenantID: tenantID, AggregateID: aggregateID, FromVersion: fromVersion + 1}
+	loaded.EventsReplayed, err = applyStream(s.eventRepo, r, loaded.State, query, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
+		return nil, fmt.Errorf("failed to apply events after snapshot: %w", err)
 	}
 
-	if len(events) == 0 {
-		return nil, fmt.Errorf("aggregate not found: %s", aggregateID)
-	}
-
-	aggregate := model.NewUserAggregate()
-	for _, event := range events {
-		if err := aggregate.ApplyEvent(event); err != nil {
-			return nil, fmt.Errorf("failed to apply event: %w", err)
-		}
-	}
-
-	return aggregate, nil
+	log.Printf("Applied %d events after version %d for %s %s", loaded.EventsReplayed, fromVersion, r.AggregateType, aggregateID)
+	return loaded, nil
 }
 
 // LoadAggregateAtVersion - Belirli bir version'daki aggregate state'ini yükler
-func (s *SnapshotService) LoadAggregateAtVersion(aggregateID string, targetVersion uint32) (*model.UserAggregate, error) {
+func (s *SnapshotService) LoadAggregateAtVersion(tenantID, aggregateID string, targetVersion uint32) (reducer.State, error) {
+	r, err := streamReducer(s.eventRepo, s.reducers, tenantID, "", aggregateID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1. Target version'dan önce veya eşit olan en son snapshot'ı al
-	snapshot, err := s.snapshotRepo.GetSnapshotAtVersion(aggregateID, targetVersion)
+	snapshot, err := s.snapshotRepo.GetSnapshotAtVersion(tenantID, aggregateID, targetVersion)
 
-	var aggregate *model.UserAggregate
+	var state reducer.State
 	var fromVersion uint32 = 0
 
+	if err == nil && !r.Current(snapshot) {
+		s.markStale(tenantID, r, snapshot)
+		err = errStaleSnapshot
+	}
+
 	if err != nil {
-		// Snapshot yok, baştan başla
-		aggregate = model.NewUserAggregate()
+		// Kullanılabilir snapshot yok, baştan başla
+		state = r.New(aggregateID)
 	} else {
 		// Snapshot'tan başla
-		aggregate = model.NewUserAggregate()
-		if err := json.Unmarshal([]byte(snapshot.State), aggregate); err != nil {
+		state, err = r.Restore(aggregateID, []byte(snapshot.State))
+		if err != nil {
 			return nil, fmt.Errorf("failed to unmarshal snapshot state: %w", err)
 		}
 		fromVersion = snapshot.Version
 	}
 
-	// 2. Snapshot'tan target version'a kadar olan event'leri al
-	filter := model.EventFilter{
+	if targetVersion == 0 {
+		return state, nil
+	}
+
+	// 2-3. Snapshot'tan target version'a kadar olan event'leri okuyup uygula
+	query := model.StreamQuery{
+		TenantID:    tenantID,
 		AggregateID: aggregateID,
-		Limit:       10000,
+		FromVersion: fromVersion + 1,
+		ToVersion:   targetVersion,
+	}
+	if _, err := applyStream(s.eventRepo, r, state, query, nil); err != nil {
+		return nil, err
 	}
 
-	events, err := s.eventRepo.GetEvents(filter)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
+	return state, nil
+}
+
+// errStaleSnapshot - Snapshot reducer'ın şu anki sürümüyle alınmamış
+var errStaleSnapshot = errors.New("snapshot was taken by another reducer version")
+
+// markStale - Eski sürüm snapshot'ın stream'ini arka planda yeniden oluşturulmak üzere kuyruğa ekler
+func (s *SnapshotService) markStale(tenantID string, r reducer.Reducer, snapshot *model.Snapshot) {
+	log.Printf("Ignoring snapshot of %s at version %d: taken by %s v%d, current reducer is %s v%d",
+		snapshot.AggregateID, snapshot.Version, snapshot.AggregateType, snapshot.StateVersion, r.AggregateType, r.StateVersion)
+	if !s.stale.push(tenantID, snapshot.AggregateID, "") {
+		log.Printf("Warning: stale snapshot queue is full, %s will be queued again on its next load", snapshot.AggregateID)
 	}
+}
 
-	// 3. Target version'a kadar event'leri uygula
-	for _, event := range events {
-		if event.Version > fromVersion && event.Version <= targetVersion {
-			if err := aggregate.ApplyEvent(event); err != nil {
-				return nil, fmt.Errorf("failed to apply event: %w", err)
-			}
+// RebuildStaleSnapshots - Yüklenirken yok sayılan eski sürüm snapshot'ları ctx bitene kadar yeniden oluşturur
+// Eski snapshot'lar sadece okunduklarında (lazy) bulunur; hiç okunmayan stream'ler kuyruğa girmez
+func (s *SnapshotService) RebuildStaleSnapshots(ctx context.Context) {
+	for {
+		candidate, ok := s.stale.pop(ctx)
+		if !ok {
+			return
 		}
+		unlock := s.streams.lock(candidate.snapshotKey)
+		if err := s.rebuildStaleSnapshots(candidate.TenantID, candidate.AggregateID); err != nil {
+			log.Printf("Warning: failed to rebuild stale snapshot of %s: %v", candidate.AggregateID, err)
+		}
+		unlock()
+		s.stale.done(candidate.snapshotKey)
 	}
-
-	return aggregate, nil
 }
 
-// ShouldCreateSnapshot - Snapshot oluşturulmalı mı kontrol eder
-// Her N event'te bir snapshot oluştur (örneğin her 50 event)
-func (s *SnapshotService) ShouldCreateSnapshot(aggregateID string, snapshotInterval uint32) (bool, error) {
-	latestVersion, err := s.eventRepo.GetLatestVersionForAggregate(aggregateID)
+// rebuildStaleSnapshots - Stream'in eski sürümle alınmış bütün snapshot'larını kendi version'larında yeniden alır
+// Sadece en son version yeniden alınsaydı LoadAggregateAtVersion'ın okuduğu eski version'lar hep eski kalırdı
+func (s *SnapshotService) rebuildStaleSnapshots(tenantID, aggregateID string) error {
+	r, err := streamReducer(s.eventRepo, s.reducers, tenantID, "", aggregateID)
 	if err != nil {
-		return false, err
+		return err
 	}
 
-	// Snapshot var mı kontrol et
-	hasSnapshot, err := s.snapshotRepo.HasSnapshot(aggregateID)
+	infos, err := s.snapshotRepo.ListSnapshots(tenantID, aggregateID)
 	if err != nil {
-		return false, err
+		return err
 	}
-
-	if !hasSnapshot {
-		// İlk snapshot - 10 event'ten sonra oluştur
-		return latestVersion >= 10, nil
+	for _, info := range infos {
+		if info.AggregateType == r.AggregateType && info.StateVersion == r.StateVersion {
+			continue
+		}
+		if err := s.createSnapshot(tenantID, aggregateID, r, info.Version); err != nil {
+			return fmt.Errorf("version %d: %w", info.Version, err)
+		}
 	}
+	return nil
+}
 
-	// Son snapshot'ı al
-	snapshot, err := s.snapshotRepo.GetLatestSnapshot(aggregateID)
-	if err != nil {
-		return false, err
+// StaleSnapshotsQueued - Yeniden oluşturulmayı bekleyen eski sürüm snapshot sayısı
+func (s *SnapshotService) StaleSnapshotsQueued() int {
+	return s.stale.len()
+}
+
+// AutoCreateSnapshots - Yeni event yazılan aggregate için politikası gerektiriyorsa snapshot oluşturur
+// Politika aggregate tipinin catalog ayarlarından gelir; reducer'ı kayıtlı olmayan tiplerin
+// state'i kurulamadığı için snapshot'ı alınmaz
+func (s *SnapshotService) AutoCreateSnapshots(event *model.Event) error {
+	if !s.reducers.Has(event.AggregateType) {
+		return nil
 	}
 
-	// Snapshot'tan sonra yeterli event var mı?
-	eventsSinceSnapshot := latestVersion - snapshot.Version
-	return eventsSinceSnapshot >= snapshotInterval, nil
+	_, err := s.snapshotIfDue(event.TenantID, event.AggregateID, event.EventType)
+	return err
 }
 
-// AutoCreateSnapshots - Belirli bir aggregate için otomatik snapshot oluşturur
-func (s *SnapshotService) AutoCreateSnapshots(aggregateID string, snapshotInterval uint32) error {
-	shouldCreate, err := s.ShouldCreateSnapshot(aggregateID, snapshotInterval)
+// snapshotIfDue - Politika gerektiriyorsa snapshot alır; alındıysa true
+func (s *SnapshotService) snapshotIfDue(tenantID, aggregateID, eventType string) (bool, error) {
+	status, err := s.EvaluateSnapshotPolicy(tenantID, aggregateID, eventType)
 	if err != nil {
-		return err
+		return false, err
 	}
-
-	if shouldCreate {
-		return s.CreateSnapshot(aggregateID)
+	if !status.Due {
+		return false, nil
 	}
 
-	return nil
+	log.Printf("Snapshot policy for %s %s is due (%s)", status.Policy.AggregateType, aggregateID, status.Reason)
+	if err := s.CreateSnapshot(tenantID, aggregateID); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // HasSnapshot - Aggregate için snapshot olup olmadığını kontrol eder
-func (s *SnapshotService) HasSnapshot(aggregateID string) (bool, error) {
-	return s.snapshotRepo.HasSnapshot(aggregateID)
+func (s *SnapshotService) HasSnapshot(tenantID, aggregateID string) (bool, error) {
+	return s.snapshotRepo.HasSnapshot(tenantID, aggregateID)
 }
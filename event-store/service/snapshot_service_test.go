@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/catalog"
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+func loadTestCatalog(t *testing.T, file string) *catalog.Catalog {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	c, err := catalog.LoadCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCatalog: %v", err)
+	}
+	return c
+}
+
+func TestAutoCreateSnapshotsFollowsTypePolicy(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 3)
+
+	types := loadTestCatalog(t, `{"aggregate_types": {"user": {"snapshot": {"trigger_event_types": ["user.deactivated"]}}}}`)
+	snapshots := NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, types, reducer.NewDefaultRegistry())
+
+	// 3 event first_after (10) eşiğinin altında
+	status, err := snapshots.EvaluateSnapshotPolicy(model.DefaultTenantID, "user-1", "")
+	if err != nil {
+		t.Fatalf("EvaluateSnapshotPolicy: %v", err)
+	}
+	if status.Due || status.Policy.Sources["trigger_event_types"] != "aggregate_type" {
+		t.Fatalf("expected no snapshot due yet, got %+v", status)
+	}
+
+	deactivated := &model.Event{ID: "user-1-4", EventType: "user.deactivated", AggregateID: "user-1", Payload: `{}`, Timestamp: time.Now(), Version: 4, Position: 4}
+	if err := repo.SaveEvent(deactivated); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+	if err := snapshots.AutoCreateSnapshots(deactivated); err != nil {
+		t.Fatalf("AutoCreateSnapshots: %v", err)
+	}
+
+	status, err = snapshots.EvaluateSnapshotPolicy(model.DefaultTenantID, "user-1", deactivated.EventType)
+	if err != nil {
+		t.Fatalf("EvaluateSnapshotPolicy: %v", err)
+	}
+	if !status.HasSnapshot || status.SnapshotVersion != 4 || status.Due {
+		t.Errorf("expected trigger event to snapshot version 4, got %+v", status)
+	}
+}
+
+func TestSnapshotPolicyAgeAndReplayCost(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 3)
+
+	// seedLongStream event'leri 2024 tarihli; stream başı max_age'den eski
+	aged := loadTestCatalog(t, `{"snapshot": {"max_age": "1h"}}`)
+	status, err := NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, aged, reducer.NewDefaultRegistry()).
+		EvaluateSnapshotPolicy(model.DefaultTenantID, "user-1", "")
+	if err != nil {
+		t.Fatalf("EvaluateSnapshotPolicy: %v", err)
+	}
+	if !status.Due || status.Reason != SnapshotReasonMaxAge {
+		t.Errorf("expected max_age to be due, got %+v", status)
+	}
+
+	costly := loadTestCatalog(t, `{"snapshot": {"max_replay_bytes": 32}}`)
+	status, err = NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, costly, reducer.NewDefaultRegistry()).
+		EvaluateSnapshotPolicy(model.DefaultTenantID, "user-1", "")
+	if err != nil {
+		t.Fatalf("EvaluateSnapshotPolicy: %v", err)
+	}
+	if !status.Due || status.Reason != SnapshotReasonReplayBytes || status.ReplayBytes < 32 {
+		t.Errorf("expected max_replay_bytes to be due, got %+v", status)
+	}
+
+	// Reducer'ı olmayan tipte politika tutsa da snapshot alınmaz
+	order := &model.Event{ID: "order-1-1", EventType: "order.placed", AggregateID: "order-1", AggregateType: "order",
+		Payload: `{"items":"` + strings.Repeat("x", 64) + `"}`, Timestamp: time.Now(), Version: 1, Position: 4}
+	if err := repo.SaveEvent(order); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+	status, err = NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, costly, reducer.NewDefaultRegistry()).
+		EvaluateSnapshotPolicy(model.DefaultTenantID, "order-1", "")
+	if err != nil {
+		t.Fatalf("EvaluateSnapshotPolicy: %v", err)
+	}
+	if status.Due || status.HasReducer || status.Reason != SnapshotReasonReplayBytes {
+		t.Errorf("expected policy to match but no snapshot for a type without reducer, got %+v", status)
+	}
+}
+
+func TestStaleSnapshotsAreIgnoredAndRebuilt(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 5)
+
+	snapshotRepo := repository.NewMemorySnapshotRepository()
+	// Registry öncesi tag'siz snapshot: e-posta eski, password_hash yok
+	stale := &model.Snapshot{ID: "old", AggregateID: "user-1", Version: 5, State: `{"id":"user-1","email":"stale@example.com","status":"active","version":5}`, CreatedAt: time.Now()}
+	if err := snapshotRepo.SaveSnapshot(stale); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	// Time travel'ın okuduğu daha eski version da aynı sürümle alınmış
+	older := &model.Snapshot{ID: "older", AggregateID: "user-1", Version: 3, State: `{"id":"user-1","email":"stale@example.com","status":"active","version":3}`, CreatedAt: time.Now()}
+	if err := snapshotRepo.SaveSnapshot(older); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	snapshots := NewSnapshotService(snapshotRepo, repo, catalog.NewCatalog(), reducer.NewDefaultRegistry())
+	loaded, err := snapshots.LoadAggregateWithSnapshot(model.DefaultTenantID, "user-1")
+	if err != nil {
+		t.Fatalf("LoadAggregateWithSnapshot: %v", err)
+	}
+	if loaded.FromSnapshot || !loaded.StaleSnapshot || loaded.EventsReplayed != 5 ||
+		loaded.State.(*model.UserAggregate).Email != "last@example.com" {
+		t.Fatalf("expected stale snapshot to be ignored, got %+v", loaded)
+	}
+	if queued := snapshots.StaleSnapshotsQueued(); queued != 1 {
+		t.Fatalf("expected 1 stale snapshot queued, got %d", queued)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		snapshots.RebuildStaleSnapshots(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		latest, err := snapshotRepo.GetLatestSnapshot(model.DefaultTenantID, "user-1")
+		if err == nil && latest.StateVersion != 0 {
+			if latest.AggregateType != "user" || !strings.Contains(latest.State, "last@example.com") {
+				t.Errorf("unexpected rebuilt snapshot: %+v", latest)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stale snapshot was not rebuilt")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	loaded, err = snapshots.LoadAggregateWithSnapshot(model.DefaultTenantID, "user-1")
+	if err != nil || !loaded.FromSnapshot || loaded.StaleSnapshot || loaded.EventsReplayed != 0 {
+		t.Errorf("expected rebuilt snapshot to be used, got %+v (%v)", loaded, err)
+	}
+
+	rebuilt, err := snapshotRepo.GetSnapshotAtVersion(model.DefaultTenantID, "user-1", 4)
+	if err != nil || rebuilt.Version != 3 || rebuilt.StateVersion == 0 || rebuilt.AggregateType != "user" {
+		t.Errorf("expected the older stale snapshot to be rebuilt at its own version, got %+v (%v)", rebuilt, err)
+	}
+}
+
+func TestSnapshotManagementAndVerify(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 5)
+
+	snapshotRepo := repository.NewMemorySnapshotRepository()
+	snapshots := NewSnapshotService(snapshotRepo, repo, catalog.NewCatalog(), reducer.NewDefaultRegistry())
+	if err := snapshots.CreateSnapshot(model.DefaultTenantID, "user-1"); err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+
+	verification, err := snapshots.VerifySnapshot(model.DefaultTenantID, "user-1", 0)
+	if err != nil {
+		t.Fatalf("VerifySnapshot: %v", err)
+	}
+	if !verification.Match || verification.Stale || verification.Version != 5 {
+		t.Errorf("expected fresh snapshot to match, got %+v", verification)
+	}
+
+	// Bozuk yazılmış (drift) bir ara snapshot
+	drifted := &model.Snapshot{ID: "drifted", AggregateID: "user-1", Version: 3, AggregateType: "user", StateVersion: 2,
+		State: `{"id":"user-1","email":"wrong@example.com","password_hash":"","status":"active","version":3,"event_count":3}`}
+	if err := snapshotRepo.SaveSnapshot(drifted); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	verification, err = snapshots.VerifySnapshot(model.DefaultTenantID, "user-1", 3)
+	if err != nil {
+		t.Fatalf("VerifySnapshot: %v", err)
+	}
+	if verification.Match || verification.Differences["email"].After != "first@example.com" {
+		t.Errorf("expected email drift to be reported, got %+v", verification)
+	}
+	if _, err := snapshots.VerifySnapshot(model.DefaultTenantID, "user-1", 4); !errors.Is(err, repository.ErrSnapshotNotFound) {
+		t.Errorf("expected ErrSnapshotNotFound for a missing version, got %v", err)
+	}
+
+	listed, err := snapshots.ListSnapshots(model.DefaultTenantID, "user-1")
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Version != 3 || listed[1].Version != 5 || listed[1].SizeBytes == 0 {
+		t.Fatalf("unexpected snapshot listing: %+v", listed)
+	}
+
+	if err := snapshots.DeleteSnapshot(model.DefaultTenantID, "user-1", 3); err != nil {
+		t.Fatalf("DeleteSnapshot: %v", err)
+	}
+	if err := snapshots.DeleteSnapshot(model.DefaultTenantID, "user-1", 3); !errors.Is(err, repository.ErrSnapshotNotFound) {
+		t.Errorf("expected ErrSnapshotNotFound on second delete, got %v", err)
+	}
+	if err := snapshots.DeleteSnapshots(model.DefaultTenantID, "user-1", 0); err != nil {
+		t.Fatalf("DeleteSnapshots: %v", err)
+	}
+	if has, _ := snapshots.HasSnapshot(model.DefaultTenantID, "user-1"); has {
+		t.Error("expected all snapshots to be deleted")
+	}
+}
+
+func TestSnapshotRetentionKeepsLastN(t *testing.T) {
+	snapshotRepo := repository.NewMemorySnapshotRepository()
+	for _, aggregateID := range []string{"user-1", "user-2"} {
+		for version := uint32(1); version <= 4; version++ {
+			snapshot := &model.Snapshot{ID: fmt.Sprintf("%s-%d", aggregateID, version), AggregateID: aggregateID, Version: version, State: `{}`}
+			if err := snapshotRepo.SaveSnapshot(snapshot); err != nil {
+				t.Fatalf("SaveSnapshot: %v", err)
+			}
+		}
+	}
+	if err := snapshotRepo.SaveSnapshot(&model.Snapshot{ID: "user-3-1", AggregateID: "user-3", Version: 1, State: `{}`}); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	result, err := NewSnapshotRetention(snapshotRepo, 2).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Streams != 2 || result.Deleted != 4 {
+		t.Errorf("expected 4 snapshots deleted from 2 streams, got %+v", result)
+	}
+
+	kept, _ := snapshotRepo.ListSnapshots(model.DefaultTenantID, "user-1")
+	if len(kept) != 2 || kept[0].Version != 3 || kept[1].Version != 4 {
+		t.Errorf("expected versions 3 and 4 to be kept, got %+v", kept)
+	}
+}
+
+func TestSnapshotRebuildFiltersSkipsAndResumes(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	events := NewEventService(repo, catalog.NewCatalog())
+	for _, aggregateID := range []string{"user-1", "user-2", "user-3"} {
+		if _, err := events.AppendEvents(model.DefaultTenantID, aggregateID, model.ExpectedVersion{}, []*model.Event{
+			{EventType: "user.created", Payload: `{}`},
+			{EventType: "user.login.recorded", Payload: `{}`},
+		}); err != nil {
+			t.Fatalf("AppendEvents: %v", err)
+		}
+	}
+	if _, err := events.AppendEvents(model.DefaultTenantID, "team-1", model.ExpectedVersion{}, []*model.Event{
+		{EventType: "team.created", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	snapshotRepo := repository.NewMemorySnapshotRepository()
+	rebuilder := NewSnapshotRebuilder(NewSnapshotService(snapshotRepo, repo, catalog.NewCatalog(), reducer.NewDefaultRegistry()))
+	ctx := context.Background()
+
+	// team tipinin reducer'ı yok, atlanır
+	started, err := rebuilder.Start(SnapshotRebuildRequest{TenantID: model.DefaultTenantID, Workers: 2})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	all, err := rebuilder.Wait(ctx, model.DefaultTenantID, started.ID)
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if all.State != RebuildCompleted || all.Processed != 4 || all.Rebuilt != 3 || all.Skipped != 1 || all.Cursor != "user-3" {
+		t.Fatalf("unexpected rebuild result: %+v", all)
+	}
+	if snapshot, _ := snapshotRepo.GetLatestSnapshot(model.DefaultTenantID, "user-2"); snapshot == nil || snapshot.Version != 2 {
+		t.Errorf("expected user-2 snapshot at version 2, got %+v", snapshot)
+	}
+
+	// Kategori filtresi ve After ile kalınan yerden devam
+	started, err = rebuilder.Start(SnapshotRebuildRequest{TenantID: model.DefaultTenantID, Category: "user", After: "user-1"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	filtered, _ := rebuilder.Wait(ctx, model.DefaultTenantID, started.ID)
+	if filtered.Processed != 2 || filtered.Rebuilt != 2 || filtered.Request.Workers != DefaultRebuildWorkers {
+		t.Errorf("expected user-2 and user-3 to be rebuilt, got %+v", filtered)
+	}
+
+	resumed, err := rebuilder.Resume(model.DefaultTenantID, filtered.ID)
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	resumed, _ = rebuilder.Wait(ctx, model.DefaultTenantID, resumed.ID)
+	if resumed.Request.After != "user-3" || resumed.Processed != 0 || resumed.ResumedFrom != filtered.ID {
+		t.Errorf("expected resume to start after user-3, got %+v", resumed)
+	}
+
+	if _, err := rebuilder.Get("acme", filtered.ID); !errors.Is(err, ErrRebuildNotFound) {
+		t.Errorf("expected other tenant to get ErrRebuildNotFound, got %v", err)
+	}
+	if got := rebuilder.List(model.DefaultTenantID); len(got) != 3 || got[0].ID != resumed.ID {
+		t.Errorf("expected 3 rebuilds newest first, got %d", len(got))
+	}
+	if _, err := rebuilder.Start(SnapshotRebuildRequest{Workers: MaxRebuildWorkers + 1}); err == nil {
+		t.Error("expected too many workers to be rejected")
+	}
+}
+
+func TestSnapshotRebuilderPrunesFinishedJobs(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	rebuilder := NewSnapshotRebuilder(NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, catalog.NewCatalog(), reducer.NewDefaultRegistry()))
+
+	var first string
+	for i := 0; i < maxRebuildJobs+5; i++ {
+		started, err := rebuilder.Start(SnapshotRebuildRequest{TenantID: model.DefaultTenantID})
+		if err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		if i == 0 {
+			first = started.ID
+		}
+		if _, err := rebuilder.Wait(context.Background(), model.DefaultTenantID, started.ID); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+
+	if got := rebuilder.List(model.DefaultTenantID); len(got) != maxRebuildJobs {
+		t.Errorf("expected %d jobs to be kept, got %d", maxRebuildJobs, len(got))
+	}
+	if _, err := rebuilder.Get(model.DefaultTenantID, first); !errors.Is(err, ErrRebuildNotFound) {
+		t.Errorf("expected the oldest job to be pruned, got %v", err)
+	}
+}
+
+func TestQueuedSnapshotsCoalescePerAggregate(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	seedLongStream(t, repo, "user-1", 3)
+
+	types := loadTestCatalog(t, `{"aggregate_types": {"user": {"snapshot": {"trigger_event_types": ["user.email.changed"]}}}}`)
+	snapshots := NewSnapshotService(repository.NewMemorySnapshotRepository(), repo, types, reducer.NewDefaultRegistry())
+
+	// Trigger event'i sonraki event'lerle birleşse de kaybolmaz
+	for _, eventType := range []string{"user.login.recorded", "user.email.changed", "user.login.recorded"} {
+		snapshots.QueueSnapshot(&model.Event{EventType: eventType, AggregateType: "user", AggregateID: "user-1"})
+	}
+	snapshots.QueueSnapshot(&model.Event{EventType: "team.created", AggregateType: "team", AggregateID: "team-1"})
+
+	if stats := snapshots.QueueStats(); stats.Pending != 1 || stats.Queued != 3 {
+		t.Fatalf("expected 3 events coalesced into 1 pending stream, got %+v", stats)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go snapshots.RunSnapshotWorker(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for stats := snapshots.QueueStats(); stats.Created+stats.Failed == 0; stats = snapshots.QueueStats() {
+		if time.Now().After(deadline) {
+			t.Fatalf("snapshot worker did not run: %+v", snapshots.QueueStats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	has, _ := snapshots.HasSnapshot(model.DefaultTenantID, "user-1")
+	if stats := snapshots.QueueStats(); !has || stats.Created != 1 || stats.Failed != 0 {
+		t.Errorf("expected trigger event to snapshot user-1, got %+v", stats)
+	}
+}
+
+func TestSnapshotQueueRequeuesAggregateUpdatedWhileActive(t *testing.T) {
+	queue := newSnapshotQueue(1)
+	ctx := context.Background()
+
+	queue.push("", "user-1", "")
+	first, _ := queue.pop(ctx)
+
+	// İşlenirken gelen event'ler bekletilir, limit'e sayılmaz
+	queue.push("", "user-1", "user.deactivated")
+	queue.push("", "user-1", "")
+	if !queue.push("", "user-2", "") || queue.push("", "user-3", "") {
+		t.Fatal("expected the limit to allow exactly one pending stream")
+	}
+
+	queue.done(first.snapshotKey)
+	second, _ := queue.pop(ctx)
+	third, _ := queue.pop(ctx)
+	if second.AggregateID != "user-2" || third.AggregateID != "user-1" || third.EventType != "user.deactivated" {
+		t.Errorf("expected user-2 then user-1 with its trigger, got %+v and %+v", second, third)
+	}
+}
+
+func TestStreamLocksSerializeWorkersOfBothQueues(t *testing.T) {
+	var locks streamLocks
+	key := snapshotKey{TenantID: model.DefaultTenantID, AggregateID: "user-1"}
+
+	unlock := locks.lock(key)
+	// Başka stream beklemez
+	locks.lock(snapshotKey{TenantID: model.DefaultTenantID, AggregateID: "user-2"})()
+
+	acquired := make(chan struct{})
+	go func() {
+		locks.lock(key)()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected the second worker to wait for the stream")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the second worker to get the stream after unlock")
+	}
+
+	locks.mu.Lock()
+	defer locks.mu.Unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("expected released locks to be removed, got %d", len(locks.locks))
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// snapshotCounters - Snapshot worker'ının servis açıldığından beri sayaçları
+type snapshotCounters struct {
+	queued    atomic.Uint64
+	dropped   atomic.Uint64
+	evaluated atomic.Uint64
+	created   atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// SnapshotQueueStats - Snapshot kuyruklarının derinliği ve worker sayaçları
+type SnapshotQueueStats struct {
+	// Pending - Politikası değerlendirilmeyi bekleyen stream'ler (aggregate başına tek kayıt)
+	Pending int `json:"pending"`
+	// Stale - Reducer sürümü değiştiği için yeniden oluşturulmayı bekleyen snapshot'lar
+	Stale int `json:"stale"`
+	// Queued - Kuyruğa verilen event'ler; aynı stream'e ait bekleyenler tek kayıtta birleşir
+	Queued uint64 `json:"queued"`
+	// Dropped - Kuyruk dolu olduğu için atlanan event'ler
+	Dropped   uint64 `json:"dropped"`
+	Evaluated uint64 `json:"evaluated"`
+	Created   uint64 `json:"created"`
+	Failed    uint64 `json:"failed"`
+}
+
+// QueueSnapshot - Yeni yazılan event'in stream'ini snapshot worker'ına verir, beklemez
+// Consumer'ın hot path'inde storage'a gidilmez: sadece event tipinin politikada trigger olup
+// olmadığına catalog'dan bakılır; stream zaten bekliyorsa kayıtlar birleştirilir
+func (s *SnapshotService) QueueSnapshot(event *model.Event) {
+	if !s.reducers.Has(event.AggregateType) {
+		return
+	}
+
+	var trigger string
+	if s.types.SnapshotPolicy(event.AggregateType).Triggers(event.EventType) {
+		trigger = event.EventType
+	}
+
+	s.counters.queued.Add(1)
+	if !s.candidates.push(event.TenantID, event.AggregateID, trigger) {
+		s.counters.dropped.Add(1)
+		log.Printf("Warning: snapshot queue is full, skipping snapshot check of %s", event.AggregateID)
+	}
+}
+
+// RunSnapshotWorker - Kuyruktaki stream'lerin snapshot politikasını ctx bitene kadar değerlendirir
+// Birden fazla worker çalışabilir; aynı stream aynı anda tek worker'da işlenir (stale rebuild dahil)
+func (s *SnapshotService) RunSnapshotWorker(ctx context.Context) {
+	for {
+		candidate, ok := s.candidates.pop(ctx)
+		if !ok {
+			return
+		}
+
+		s.counters.evaluated.Add(1)
+		unlock := s.streams.lock(candidate.snapshotKey)
+		created, err := s.snapshotIfDue(candidate.TenantID, candidate.AggregateID, candidate.EventType)
+		unlock()
+		switch {
+		case err != nil:
+			s.counters.failed.Add(1)
+			log.Printf("Warning: Failed to auto-create snapshot for aggregate %s: %v", candidate.AggregateID, err)
+		case created:
+			s.counters.created.Add(1)
+		}
+		s.candidates.done(candidate.snapshotKey)
+	}
+}
+
+// QueueStats - Snapshot kuyruklarının anlık derinliği ve sayaçlar
+func (s *SnapshotService) QueueStats() SnapshotQueueStats {
+	return SnapshotQueueStats{
+		Pending:   s.candidates.len(),
+		Stale:     s.stale.len(),
+		Queued:    s.counters.queued.Load(),
+		Dropped:   s.counters.dropped.Load(),
+		Evaluated: s.counters.evaluated.Load(),
+		Created:   s.counters.created.Load(),
+		Failed:    s.counters.failed.Load(),
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// ErrInvalidStatsQuery - Aralık ya da bucket geçersiz
+var ErrInvalidStatsQuery = errors.New("invalid stats query")
+
+const (
+	// maxStatsBuckets - Tek sorguda dönen bucket sayısı sınırı (örn. saatlik bucket'larla ~83 gün)
+	maxStatsBuckets = 2000
+	// defaultStatsRange - From verilmezse To'dan geriye bakılan süre
+	defaultStatsRange = 7 * 24 * time.Hour
+	// DefaultTopAggregates ve MaxTopAggregates - En aktif aggregate listesinin uzunluğu
+	DefaultTopAggregates = 10
+	MaxTopAggregates     = 1000
+)
+
+// Stats raporlarının kullandığı event tipleri
+const (
+	signupEventType         = "user.created"
+	passwordChangeEventType = "user.password.changed"
+)
+
+// StatsSeries - Bucket'lara bölünmüş sayılar; From/To bucket sınırlarına genişletilmiş aralıktır
+type StatsSeries struct {
+	Bucket string              `json:"bucket"`
+	From   time.Time           `json:"from"`
+	To     time.Time           `json:"to"`
+	Points []*model.StatsPoint `json:"points"`
+	// Totals - Aralığın tamamında key başına toplam
+	Totals map[string]uint64 `json:"totals"`
+}
+
+func newStatsSeries(query model.StatsQuery, points []*model.StatsPoint) *StatsSeries {
+	series := &StatsSeries{Bucket: query.Bucket, From: query.From, To: query.To, Points: points, Totals: make(map[string]uint64)}
+	if series.Points == nil {
+		series.Points = []*model.StatsPoint{}
+	}
+	for _, point := range points {
+		series.Totals[point.Key] += point.Count
+	}
+	return series
+}
+
+// StatsService - ClickHouse materialized view'larından event istatistikleri
+type StatsService struct {
+	store repository.StatsStore
+}
+
+func NewStatsService(store repository.StatsStore) *StatsService {
+	return &StatsService{store: store}
+}
+
+// EventCounts - Bucket ve event tipi başına event sayıları (EventTypes boşsa tüm tipler)
+func (s *StatsService) EventCounts(query model.StatsQuery) (*StatsSeries, error) {
+	query, err := normalizeStatsQuery(query, model.StatsBucketHour)
+	if err != nil {
+		return nil, err
+	}
+	points, err := s.store.CountEventsByType(query)
+	if err != nil {
+		return nil, err
+	}
+	return newStatsSeries(query, points), nil
+}
+
+// Signups - Bucket başına oluşturulan kullanıcı sayısı
+func (s *StatsService) Signups(query model.StatsQuery) (*StatsSeries, error) {
+	query.EventTypes = []string{signupEventType}
+	return s.EventCounts(withDefaultBucket(query, model.StatsBucketDay))
+}
+
+// PasswordChanges - Bucket başına şifre değişikliği sayısı
+func (s *StatsService) PasswordChanges(query model.StatsQuery) (*StatsSeries, error) {
+	query.EventTypes = []string{passwordChangeEventType}
+	return s.EventCounts(withDefaultBucket(query, model.StatsBucketDay))
+}
+
+// LoginsByUserAgent - Bucket ve user agent ailesi başına login sayıları
+func (s *StatsService) LoginsByUserAgent(query model.StatsQuery) (*StatsSeries, error) {
+	query, err := normalizeStatsQuery(query, model.StatsBucketDay)
+	if err != nil {
+		return nil, err
+	}
+	points, err := s.store.CountLoginsByUserAgent(query)
+	if err != nil {
+		return nil, err
+	}
+	return newStatsSeries(query, points), nil
+}
+
+// TopAggregates - Aralıkta en çok event alan aggregate'ler (günlük çözünürlük)
+func (s *StatsService) TopAggregates(query model.StatsQuery) ([]*model.AggregateActivity, error) {
+	query, err := normalizeStatsQuery(query, model.StatsBucketDay)
+	if err != nil {
+		return nil, err
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultTopAggregates
+	}
+	if query.Limit > MaxTopAggregates {
+		return nil, fmt.Errorf("%w: limit must be at most %d", ErrInvalidStatsQuery, MaxTopAggregates)
+	}
+	return s.store.TopAggregates(query)
+}
+
+func withDefaultBucket(query model.StatsQuery, bucket string) model.StatsQuery {
+	if query.Bucket == "" {
+		query.Bucket = bucket
+	}
+	return query
+}
+
+// normalizeStatsQuery - Varsayılanları doldurur ve aralığı bucket sınırlarına genişletir
+// To verilmezse şimdi, From verilmezse To'dan 7 gün öncesi; böylece ilk ve son bucket da tam sayılır
+func normalizeStatsQuery(query model.StatsQuery, defaultBucket string) (model.StatsQuery, error) {
+	query.TenantID = model.NormalizeTenantID(query.TenantID)
+	if query.Bucket == "" {
+		query.Bucket = defaultBucket
+	}
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultStatsRange)
+	}
+	if !query.From.Before(query.To) {
+		return query, fmt.Errorf("%w: from must be before to", ErrInvalidStatsQuery)
+	}
+
+	from, ok := bucketStart(query.From, query.Bucket)
+	if !ok {
+		return query, fmt.Errorf("%w: bucket must be one of hour, day, week, month", ErrInvalidStatsQuery)
+	}
+	to, _ := bucketStart(query.To, query.Bucket)
+	if to.Before(query.To) {
+		to = nextBucket(to, query.Bucket)
+	}
+	query.From, query.To = from, to
+
+	buckets := 0
+	for t := from; t.Before(to); t = nextBucket(t, query.Bucket) {
+		if buckets++; buckets > maxStatsBuckets {
+			return query, fmt.Errorf("%w: range spans more than %d %s buckets", ErrInvalidStatsQuery, maxStatsBuckets, query.Bucket)
+		}
+	}
+	return query, nil
+}
+
+// bucketStart - t'yi içeren bucket'ın UTC başlangıcı; haftalar pazartesi başlar
+func bucketStart(t time.Time, bucket string) (time.Time, bool) {
+	t = t.UTC()
+	switch bucket {
+	case model.StatsBucketHour:
+		return t.Truncate(time.Hour), true
+	case model.StatsBucketDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), true
+	case model.StatsBucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), true
+	case model.StatsBucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), true
+	}
+	return time.Time{}, false
+}
+
+func nextBucket(t time.Time, bucket string) time.Time {
+	switch bucket {
+	case model.StatsBucketHour:
+		return t.Add(time.Hour)
+	case model.StatsBucketDay:
+		return t.AddDate(0, 0, 1)
+	case model.StatsBucketWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 1, 0)
+	}
+}
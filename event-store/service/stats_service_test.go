@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// recordingStatsStore - Servisin store'a ilettiği sorguyu saklar
+type recordingStatsStore struct {
+	query model.StatsQuery
+}
+
+func (r *recordingStatsStore) CountEventsByType(query model.StatsQuery) ([]*model.StatsPoint, error) {
+	r.query = query
+	return nil, nil
+}
+
+func (r *recordingStatsStore) CountLoginsByUserAgent(query model.StatsQuery) ([]*model.StatsPoint, error) {
+	r.query = query
+	return nil, nil
+}
+
+func (r *recordingStatsStore) TopAggregates(query model.StatsQuery) ([]*model.AggregateActivity, error) {
+	r.query = query
+	return nil, nil
+}
+
+func TestStatsQueriesAreAlignedToBuckets(t *testing.T) {
+	store := &recordingStatsStore{}
+	stats := NewStatsService(store)
+
+	// Çarşamba öğleden sonra - sonraki çarşamba sabahı
+	from := time.Date(2025, 3, 5, 15, 30, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)
+
+	if _, err := stats.Signups(model.StatsQuery{From: from, To: to}); err != nil {
+		t.Fatalf("Signups: %v", err)
+	}
+	if store.query.Bucket != model.StatsBucketDay || store.query.EventTypes[0] != "user.created" || store.query.TenantID != model.DefaultTenantID {
+		t.Errorf("unexpected signup query: %+v", store.query)
+	}
+	if !store.query.From.Equal(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)) || !store.query.To.Equal(time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected whole days, got %s - %s", store.query.From, store.query.To)
+	}
+
+	if _, err := stats.EventCounts(model.StatsQuery{From: from, To: to, Bucket: model.StatsBucketWeek}); err != nil {
+		t.Fatalf("EventCounts: %v", err)
+	}
+	if !store.query.From.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)) || !store.query.To.Equal(time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected weeks starting on monday, got %s - %s", store.query.From, store.query.To)
+	}
+
+	if _, err := stats.TopAggregates(model.StatsQuery{From: from, To: to}); err != nil || store.query.Limit != DefaultTopAggregates {
+		t.Errorf("expected default limit, got %d (%v)", store.query.Limit, err)
+	}
+}
+
+func TestInvalidStatsQueriesAreRejected(t *testing.T) {
+	stats := NewStatsService(&recordingStatsStore{})
+	now := time.Now()
+
+	for name, query := range map[string]model.StatsQuery{
+		"reversed range": {From: now, To: now.Add(-time.Hour)},
+		"unknown bucket": {Bucket: "minute"},
+		"too many hours": {From: now.AddDate(-1, 0, 0), To: now, Bucket: model.StatsBucketHour},
+	} {
+		if _, err := stats.EventCounts(query); !errors.Is(err, ErrInvalidStatsQuery) {
+			t.Errorf("%s: expected ErrInvalidStatsQuery, got %v", name, err)
+		}
+	}
+	if _, err := stats.TopAggregates(model.StatsQuery{Limit: MaxTopAggregates + 1}); !errors.Is(err, ErrInvalidStatsQuery) {
+		t.Errorf("expected limit to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// streamReducer - Tenant'ın stream'inin aggregate tipine kayıtlı reducer
+// aggregateType verilmişse (/replay/:aggregate_type/...) stream'in tipiyle aynı olmalıdır.
+// Stream bu tenant'ta yoksa ErrAggregateNotFound, tipin reducer'ı yoksa reducer.ErrUnknownAggregateType
+func streamReducer(repo repository.EventStore, reducers *reducer.Registry, tenantID, aggregateType, aggregateID string) (reducer.Reducer, error) {
+	var head *model.Event
+	err := repo.ReadStream(context.Background(), model.StreamQuery{
+		TenantID:    tenantID,
+		AggregateID: aggregateID,
+		Direction:   model.ReadBackward,
+		MaxCount:    1,
+	}, func(event *model.Event) error {
+		head = event
+		return nil
+	})
+	if err != nil {
+		return reducer.Reducer{}, fmt.Errorf("failed to read stream %s: %w", aggregateID, err)
+	}
+	if head == nil {
+		return reducer.Reducer{}, fmt.Errorf("%w: %s", ErrAggregateNotFound, aggregateID)
+	}
+
+	streamType := model.NormalizeAggregateType(head.AggregateType)
+	if aggregateType != "" && aggregateType != streamType {
+		return reducer.Reducer{}, fmt.Errorf("%w: %s is a %s stream, not %s", ErrAggregateTypeMismatch, aggregateID, streamType, aggregateType)
+	}
+	return reducers.Get(streamType)
+}
+
+// applyStream - Sorguya uyan event'leri storage'dan event event okuyup state'e uygular
+// Event sayısında üst sınır yoktur; reducer'ın işlemediği bir event replay'i durdurur.
+// onApplied her uygulanan event'ten sonra çağrılır (opsiyonel); uygulanan event sayısını döner
+func applyStream(repo repository.EventStore, r reducer.Reducer, state reducer.State, query model.StreamQuery, onApplied func() error) (int, error) {
+	applied := 0
+	err := repo.ReadStream(context.Background(), query, func(event *model.Event) error {
+		if err := r.Apply(state, event); err != nil {
+			return fmt.Errorf("failed to apply event %s: %w", event.ID, err)
+		}
+		applied++
+		if onApplied != nil {
+			return onApplied()
+		}
+		return nil
+	})
+	if err != nil {
+		return applied, fmt.Errorf("failed to replay stream %s: %w", query.AggregateID, err)
+	}
+	return applied, nil
+}
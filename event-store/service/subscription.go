@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+const (
+	// subscriptionPageSize - Catch-up sırasında storage'dan tek seferde okunan event sayısı
+	subscriptionPageSize = 500
+	// subscriptionBuffer - Canlı event kanalının kapasitesi; dolarsa subscriber catch-up'a döner
+	subscriptionBuffer = 1024
+)
+
+// SubscriptionOptions - SubscribeAll / SubscribeToStream ayarları
+type SubscriptionOptions struct {
+	// TenantID - Sadece bu tenant'ın event'leri iletilir (boş = DefaultTenantID)
+	TenantID string
+	// EventTypes - Boş değilse sadece bu tipteki event'ler iletilir
+	EventTypes []string
+	// Category - Boş değilse sadece bu kategorideki stream'lerin event'leri iletilir
+	Category string
+	// OnCaughtUp - Storage'daki event'ler bitip canlı akışa geçildiğinde bir kez çağrılır (opsiyonel)
+	OnCaughtUp func() error
+}
+
+// inTenant - Event subscription'ın tenant'ına mı ait?
+func (o SubscriptionOptions) inTenant(event *model.Event) bool {
+	return model.NormalizeTenantID(event.TenantID) == model.NormalizeTenantID(o.TenantID)
+}
+
+// matches - Event, subscription'ın tenant, kategori ve tip filtresine uyuyor mu?
+func (o SubscriptionOptions) matches(event *model.Event) bool {
+	if !o.inTenant(event) {
+		return false
+	}
+	if o.Category != "" && model.NormalizeCategory(event.Category, event.AggregateType) != o.Category {
+		return false
+	}
+	if len(o.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range o.EventTypes {
+		if event.EventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscribeAll - fromPosition'dan (dahil) itibaren tenant'ın tüm event'lerini position sırasıyla handle'a iletir
+// Önce storage'dan catch-up yapar, sonra canlı akışa geçer. ctx iptal edilene ya da handle
+// hata dönene kadar bloklar.
+func (s *EventService) SubscribeAll(ctx context.Context, fromPosition uint64, opts SubscriptionOptions, handle func(*model.Event) error) error {
+	next := fromPosition
+	if next == 0 {
+		next = 1
+	}
+
+	return s.runSubscription(ctx, opts, subscriptionCursor{
+		readPage: func() ([]*model.Event, error) {
+			filter := model.EventFilter{TenantID: opts.TenantID, Category: opts.Category, FromPosition: next, Limit: subscriptionPageSize}
+			if len(opts.EventTypes) == 1 {
+				filter.EventType = opts.EventTypes[0]
+			}
+			events, err := s.repo.GetEvents(filter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read events from position %d: %w", next, err)
+			}
+			// Tenant/kategori/tip filtresi storage'da uygulandığı için atlanan position'lar sayfanın sonuna göre ilerletilir
+			if len(events) > 0 {
+				next = events[len(events)-1].Position + 1
+			}
+			return events, nil
+		},
+		pageIsLast: func(events []*model.Event) bool {
+			return len(events) < subscriptionPageSize
+		},
+		live: func(event *model.Event) liveDecision {
+			// Storage tenant filtresiyle okunduğu için next son eşleşen event'e göre ilerler;
+			// aradaki (başka tenant'ların) position'lar beklenen boşluktur. Kaçırılan canlı
+			// event'ler kanalın kapanmasıyla fark edilir ve catch-up ile okunur
+			if event.Position < next {
+				return liveSkip
+			}
+			next = event.Position + 1
+			return liveDeliver
+		},
+	}, handle)
+}
+
+// SubscribeToStream - Tenant'ın tek aggregate'inin event'lerini fromVersion'dan (dahil) itibaren version sırasıyla iletir
+func (s *EventService) SubscribeToStream(ctx context.Context, aggregateID string, fromVersion uint32, opts SubscriptionOptions, handle func(*model.Event) error) error {
+	if aggregateID == "" {
+		return fmt.Errorf("aggregate_id is required")
+	}
+
+	next := fromVersion
+	if next == 0 {
+		next = 1
+	}
+
+	return s.runSubscription(ctx, opts, subscriptionCursor{
+		readPage: func() ([]*model.Event, error) {
+			events, err := s.repo.GetEventsAfterVersion(opts.TenantID, aggregateID, next-1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read events for aggregate %s: %w", aggregateID, err)
+			}
+			if len(events) > 0 {
+				next = events[len(events)-1].Version + 1
+			}
+			return events, nil
+		},
+		// Aggregate stream'i tek seferde okunur
+		pageIsLast: func([]*model.Event) bool {
+			return true
+		},
+		live: func(event *model.Event) liveDecision {
+			switch {
+			case event.AggregateID != aggregateID || !opts.inTenant(event) || event.Version < next:
+				return liveSkip
+			case event.Version > next:
+				return liveGap
+			}
+			next = event.Version + 1
+			return liveDeliver
+		},
+	}, handle)
+}
+
+type liveDecision int
+
+const (
+	liveDeliver liveDecision = iota
+	liveSkip
+	liveGap
+)
+
+// subscriptionCursor - Subscription türüne göre değişen okuma/sıralama kuralları
+type subscriptionCursor struct {
+	readPage   func() ([]*model.Event, error)
+	pageIsLast func(events []*model.Event) bool
+	live       func(event *model.Event) liveDecision
+}
+
+// runSubscription - Catch-up + canlı akış döngüsü
+// Canlı kanala catch-up'tan önce abone olunur; böylece okuma sırasında yazılan event'ler
+// kaçırılmaz, iki kez gelenler cursor tarafından elenir. Kanal taşarsa ya da sırada
+// boşluk görülürse tekrar storage'dan okunur.
+func (s *EventService) runSubscription(ctx context.Context, opts SubscriptionOptions, cursor subscriptionCursor, handle func(*model.Event) error) error {
+	notifiedCaughtUp := false
+
+	for {
+		sub := s.broadcaster.subscribe(subscriptionBuffer)
+
+		if err := s.catchUp(ctx, opts, cursor, handle); err != nil {
+			s.broadcaster.unsubscribe(sub)
+			return err
+		}
+
+		if !notifiedCaughtUp && opts.OnCaughtUp != nil {
+			if err := opts.OnCaughtUp(); err != nil {
+				s.broadcaster.unsubscribe(sub)
+				return err
+			}
+		}
+		notifiedCaughtUp = true
+
+		err := s.followLive(ctx, sub, opts, cursor, handle)
+		s.broadcaster.unsubscribe(sub)
+		if err != nil {
+			return err
+		}
+		log.Printf("subscription fell behind live events, catching up from storage")
+	}
+}
+
+func (s *EventService) catchUp(ctx context.Context, opts SubscriptionOptions, cursor subscriptionCursor, handle func(*model.Event) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		events, err := cursor.readPage()
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if !opts.matches(event) {
+				continue
+			}
+			if err := handle(event); err != nil {
+				return err
+			}
+		}
+
+		if cursor.pageIsLast(events) {
+			return nil
+		}
+	}
+}
+
+// followLive - Canlı event'leri iletir; nil dönerse subscriber catch-up'a dönmelidir
+func (s *EventService) followLive(ctx context.Context, sub *liveSubscription, opts SubscriptionOptions, cursor subscriptionCursor, handle func(*model.Event) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-sub.events:
+			if !ok {
+				return nil
+			}
+
+			switch cursor.live(event) {
+			case liveSkip:
+				continue
+			case liveGap:
+				return nil
+			}
+
+			if !opts.matches(event) {
+				continue
+			}
+			if err := handle(event); err != nil {
+				return err
+			}
+		}
+	}
+}
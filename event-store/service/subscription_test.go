@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+// startSubscription - Subscription'ı arka planda çalıştırır, iletilen event'leri kanala yazar
+func startSubscription(t *testing.T, subscribe func(ctx context.Context, opts SubscriptionOptions, handle func(*model.Event) error) error) (<-chan *model.Event, <-chan struct{}) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan *model.Event, 100)
+	caughtUp := make(chan struct{})
+	done := make(chan error, 1)
+
+	go func() {
+		done <- subscribe(ctx, SubscriptionOptions{
+			EventTypes: []string{"user.created", "user.deactivated"},
+			OnCaughtUp: func() error {
+				close(caughtUp)
+				return nil
+			},
+		}, func(event *model.Event) error {
+			received <- event
+			return nil
+		})
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	return received, caughtUp
+}
+
+func nextEvent(t *testing.T, received <-chan *model.Event) *model.Event {
+	t.Helper()
+
+	select {
+	case event := <-received:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
+
+func TestSubscribeAllCatchesUpThenFollowsLive(t *testing.T) {
+	svc := newTestEventService()
+
+	for _, event := range []*model.Event{
+		{EventType: "user.created", AggregateID: "user-1", Payload: `{}`},
+		{EventType: "user.login.recorded", AggregateID: "user-1", Payload: `{}`},
+		{EventType: "user.created", AggregateID: "user-2", Payload: `{}`},
+	} {
+		if err := svc.SaveEvent(event); err != nil {
+			t.Fatalf("SaveEvent: %v", err)
+		}
+	}
+
+	received, caughtUp := startSubscription(t, func(ctx context.Context, opts SubscriptionOptions, handle func(*model.Event) error) error {
+		return svc.SubscribeAll(ctx, 2, opts, handle)
+	})
+
+	if event := nextEvent(t, received); event.Position != 3 || event.AggregateID != "user-2" {
+		t.Fatalf("expected catch-up from position 3, got %+v", event)
+	}
+
+	<-caughtUp
+	if err := svc.SaveEvent(&model.Event{EventType: "user.login.recorded", AggregateID: "user-2", Payload: `{}`}); err != nil {
+		t.Fatalf("SaveEvent: %v", err)
+	}
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{}, []*model.Event{{EventType: "user.deactivated", Payload: `{}`}}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	// Filtrelenen login event'i atlanmalı
+	if event := nextEvent(t, received); event.Position != 5 || event.EventType != "user.deactivated" {
+		t.Fatalf("expected live event at position 5, got %+v", event)
+	}
+}
+
+func TestSubscribeToStreamFollowsSingleAggregate(t *testing.T) {
+	svc := newTestEventService()
+
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{}, []*model.Event{
+		{EventType: "user.created", Payload: `{}`},
+	}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	received, caughtUp := startSubscription(t, func(ctx context.Context, opts SubscriptionOptions, handle func(*model.Event) error) error {
+		return svc.SubscribeToStream(ctx, "user-1", 0, opts, handle)
+	})
+
+	if event := nextEvent(t, received); event.Version != 1 {
+		t.Fatalf("expected version 1 from catch-up, got %+v", event)
+	}
+
+	<-caughtUp
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-2", model.ExpectedVersion{}, []*model.Event{{EventType: "user.created", Payload: `{}`}}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+	if _, err := svc.AppendEvents(model.DefaultTenantID, "user-1", model.ExpectedVersion{}, []*model.Event{{EventType: "user.deactivated", Payload: `{}`}}); err != nil {
+		t.Fatalf("AppendEvents: %v", err)
+	}
+
+	if event := nextEvent(t, received); event.AggregateID != "user-1" || event.Version != 2 {
+		t.Fatalf("expected user-1 version 2, got %+v", event)
+	}
+}
+
+func TestSlowSubscriberFallsBackToCatchUp(t *testing.T) {
+	broadcaster := NewEventBroadcaster()
+	sub := broadcaster.subscribe(1)
+
+	broadcaster.publish([]*model.Event{{Position: 1}, {Position: 2}})
+
+	if event := <-sub.events; event.Position != 1 {
+		t.Fatalf("expected buffered event 1, got %d", event.Position)
+	}
+	if _, ok := <-sub.events; ok {
+		t.Fatal("expected lagging subscription to be closed")
+	}
+	if broadcaster.SubscriberCount() != 0 {
+		t.Errorf("expected lagging subscriber to be removed")
+	}
+}
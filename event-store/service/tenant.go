@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// streamHead - Aggregate'in son (latestVersion) event'i; bu tenant'ta değilse owned false döner
+// Başka tenant'ın aggregate'i storage'da tenant filtresiyle okunduğu için bulunamaz.
+// Arşiv sadece tenant'ın kendi aralıkları için ErrArchived döndüğünden bu da sahiplik sayılır (head nil)
+func streamHead(repo repository.EventStore, tenantID, aggregateID string, latestVersion uint32) (*model.Event, bool, error) {
+	var head *model.Event
+	err := repo.ReadStream(context.Background(), model.StreamQuery{
+		TenantID:    tenantID,
+		AggregateID: aggregateID,
+		FromVersion: latestVersion,
+		MaxCount:    1,
+	}, func(event *model.Event) error {
+		head = event
+		return nil
+	})
+	if errors.Is(err, repository.ErrArchived) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read stream %s: %w", aggregateID, err)
+	}
+	return head, head != nil, nil
+}
+
+// streamExists - Aggregate'in son (latestVersion) event'i bu tenant'ta mı?
+func streamExists(repo repository.EventStore, tenantID, aggregateID string, latestVersion uint32) (bool, error) {
+	_, owned, err := streamHead(repo, tenantID, aggregateID, latestVersion)
+	return owned, err
+}
+
+// checkTenant - Event'i olan (currentVersion > 0) aggregate'e sadece kendi tenant'ı yazabilir
+// Version'lar aggregate ID başına tekil olduğu için başka tenant'ın yazması stream'i bozardı.
+// Stream'in aggregate tipini döner (yeni ya da head'i arşivde olan stream için boş)
+func checkTenant(repo repository.EventStore, tenantID, aggregateID string, currentVersion uint32) (string, error) {
+	if currentVersion == 0 {
+		return "", nil
+	}
+
+	head, owned, err := streamHead(repo, tenantID, aggregateID, currentVersion)
+	if err != nil {
+		return "", err
+	}
+	if !owned {
+		return "", fmt.Errorf("%w: %s", ErrTenantMismatch, aggregateID)
+	}
+	if head == nil {
+		return "", nil
+	}
+	return model.NormalizeAggregateType(head.AggregateType), nil
+}
@@ -0,0 +1,96 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// DefaultBatchSize - Export'ta sayfa, import'ta yazma batch'i büyüklüğü
+const DefaultBatchSize = 1000
+
+// Selection - Export edilecek event'ler
+// AggregateIDs verilirse sadece o stream'ler (version sırasıyla), aksi halde zaman aralığındaki
+// tüm event'ler position sırasıyla yazılır (boş aralık = tüm store). Export tek tenant'ı okur (boş = default)
+type Selection struct {
+	TenantID     string
+	StartTime    time.Time
+	EndTime      time.Time
+	AggregateIDs []string
+}
+
+// ExportResult - Yazılan event ve aggregate sayısı
+type ExportResult struct {
+	Events     uint64 `json:"events"`
+	Aggregates int    `json:"aggregates"`
+}
+
+// Export - Seçilen event'leri w'ye NDJSON olarak yazar (her satır bir model.Event)
+// version, position, timestamp, schema_version ve metadata korunur. PII alanları store'un verdiği
+// haliyle yazılır: eventctl privacy store üzerinden okuduğu için düz metindir (anahtarı yok edilmiş
+// aggregate'lerde Redacted) ve hedef import ederken kendi anahtarlarıyla yeniden şifreler
+func Export(ctx context.Context, store repository.EventStore, selection Selection, w io.Writer) (ExportResult, error) {
+	var result ExportResult
+	encoder := json.NewEncoder(w)
+	aggregates := make(map[string]bool)
+
+	write := func(event *model.Event) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to write event %s: %w", event.ID, err)
+		}
+		result.Events++
+		aggregates[event.AggregateID] = true
+		return nil
+	}
+
+	if len(selection.AggregateIDs) > 0 {
+		for _, aggregateID := range selection.AggregateIDs {
+			query := model.StreamQuery{TenantID: selection.TenantID, AggregateID: aggregateID, EndTime: selection.EndTime}
+			err := store.ReadStream(ctx, query, func(event *model.Event) error {
+				if !selection.StartTime.IsZero() && event.Timestamp.Before(selection.StartTime) {
+					return nil
+				}
+				return write(event)
+			})
+			if err != nil {
+				return result, fmt.Errorf("failed to export aggregate %s: %w", aggregateID, err)
+			}
+		}
+		result.Aggregates = len(aggregates)
+		return result, nil
+	}
+
+	filter := model.EventFilter{
+		TenantID:     selection.TenantID,
+		StartTime:    selection.StartTime,
+		EndTime:      selection.EndTime,
+		FromPosition: 1,
+		Limit:        DefaultBatchSize,
+	}
+	for {
+		events, err := store.GetEvents(filter)
+		if err != nil {
+			return result, fmt.Errorf("failed to read events from position %d: %w", filter.FromPosition, err)
+		}
+		for _, event := range events {
+			if err := write(event); err != nil {
+				return result, err
+			}
+			filter.FromPosition = event.Position + 1
+		}
+		if len(events) < filter.Limit {
+			break
+		}
+	}
+
+	result.Aggregates = len(aggregates)
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// ImportResult - Okunan, yazılan ve zaten var olduğu için atlanan event sayıları
+type ImportResult struct {
+	Read          uint64 `json:"read"`
+	Imported      uint64 `json:"imported"`
+	Skipped       uint64 `json:"skipped"`
+	FirstPosition uint64 `json:"first_position,omitempty"`
+	LastPosition  uint64 `json:"last_position,omitempty"`
+}
+
+// VersionConflictError - Dosyadaki event'in version'ı hedef stream'de başka bir event'e ait
+type VersionConflictError struct {
+	EventID       string
+	AggregateID   string
+	Version       uint32
+	LatestVersion uint32
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("event %s: aggregate %s is already at version %d, cannot import version %d",
+		e.EventID, e.AggregateID, e.LatestVersion, e.Version)
+}
+
+// VersionGapError - Dosyadaki event hedef stream'in son version'ının hemen devamı değil
+// (örn. zaman aralığı export'unda stream'in ilk event'leri dosyada yok)
+type VersionGapError struct {
+	EventID       string
+	AggregateID   string
+	Version       uint32
+	LatestVersion uint32
+}
+
+func (e *VersionGapError) Error() string {
+	return fmt.Sprintf("event %s: aggregate %s is at version %d, importing version %d would leave a gap",
+		e.EventID, e.AggregateID, e.LatestVersion, e.Version)
+}
+
+// ImportOptions - Import ayarları
+type ImportOptions struct {
+	// BatchSize - Tek yazımdaki event sayısı (0 = DefaultBatchSize)
+	BatchSize int
+	// AllowGaps - Version'ı hedefteki son version'ın devamı olmayan event'leri de yaz
+	// (sadece kendi aralığını taşıyan zaman aralığı export'ları için)
+	AllowGaps bool
+}
+
+// Import - Export ile yazılmış NDJSON'u store'a yazar
+//   - ID'ler ve version'lar korunur; hedefte aynı ID'li event varsa atlanır (tekrar çalıştırılabilir)
+//   - Position'lar hedefin son position'ından devam edecek şekilde, dosyadaki sırayla yeniden atanır
+//   - Bir aggregate'in version'ı hedefteki son version'ın bir fazlası olmalıdır; küçükse
+//     VersionConflictError, büyükse (AllowGaps verilmedikçe) VersionGapError döner
+//
+// Position'lar event service'in dışında atandığı için event-store servisi import boyunca durdurulmalı
+// ve sonra yeniden başlatılmalıdır: çalışan servis son position'ı bellekte tutar ve import edilen
+// position'ları tekrar kullanır.
+func Import(ctx context.Context, store repository.EventStore, r io.Reader, options ImportOptions) (ImportResult, error) {
+	var result ImportResult
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	position, err := store.GetLastPosition()
+	if err != nil {
+		return result, err
+	}
+
+	latest := make(map[string]uint32)
+	latestVersion := func(aggregateID string) (uint32, error) {
+		if version, ok := latest[aggregateID]; ok {
+			return version, nil
+		}
+		version, err := store.GetLatestVersionForAggregate(aggregateID)
+		if err != nil {
+			return 0, err
+		}
+		latest[aggregateID] = version
+		return version, nil
+	}
+
+	flush := func(batch []*model.Event) error {
+		ids := make([]string, len(batch))
+		for i, event := range batch {
+			ids[i] = event.ID
+		}
+		existing, err := store.FindExistingEventIDs(ids)
+		if err != nil {
+			return err
+		}
+
+		var pending []*model.Event
+		for _, event := range batch {
+			if existing[event.ID] {
+				// Hedefte zaten var; aggregate'in önbellekteki version'ı da onu kapsamalı
+				if version, ok := latest[event.AggregateID]; ok && event.Version > version {
+					latest[event.AggregateID] = event.Version
+				}
+				result.Skipped++
+				continue
+			}
+
+			current, err := latestVersion(event.AggregateID)
+			if err != nil {
+				return err
+			}
+			if event.Version <= current {
+				return &VersionConflictError{
+					EventID:       event.ID,
+					AggregateID:   event.AggregateID,
+					Version:       event.Version,
+					LatestVersion: current,
+				}
+			}
+			if event.Version != current+1 && !options.AllowGaps {
+				return &VersionGapError{
+					EventID:       event.ID,
+					AggregateID:   event.AggregateID,
+					Version:       event.Version,
+					LatestVersion: current,
+				}
+			}
+			latest[event.AggregateID] = event.Version
+
+			position++
+			event.Position = position
+			if result.FirstPosition == 0 {
+				result.FirstPosition = position
+			}
+			pending = append(pending, event)
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+		if err := store.SaveEvents(pending); err != nil {
+			return fmt.Errorf("failed to save events: %w", err)
+		}
+		result.Imported += uint64(len(pending))
+		result.LastPosition = position
+		return nil
+	}
+
+	decoder := json.NewDecoder(r)
+	var batch []*model.Event
+	seen := make(map[string]bool)
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		var event model.Event
+		err := decoder.Decode(&event)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to decode line %d: %w", result.Read+1, err)
+		}
+		result.Read++
+
+		if event.ID == "" || event.AggregateID == "" || event.Version == 0 {
+			return result, fmt.Errorf("line %d: id, aggregate_id and version are required", result.Read)
+		}
+		// Aynı dosyada iki kez geçen event (örn. birleştirilmiş export'lar)
+		if seen[event.ID] {
+			result.Skipped++
+			continue
+		}
+		seen[event.ID] = true
+
+		batch = append(batch, &event)
+		if len(batch) >= batchSize {
+			if err := flush(batch); err != nil {
+				return result, err
+			}
+			batch = nil
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := flush(batch); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,196 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/privacy"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+func seedStore(t *testing.T) *repository.MemoryEventRepository {
+	t.Helper()
+	repo := repository.NewMemoryEventRepository()
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := []*model.Event{
+		{ID: "a1", EventType: "user.created", AggregateID: "user-a", Version: 1, Payload: `{"email":"a@example.com","n":1}`},
+		{ID: "b1", EventType: "user.created", AggregateID: "user-b", Version: 1, Payload: `{"email":"b@example.com"}`},
+		{ID: "a2", EventType: "user.email.changed", AggregateID: "user-a", Version: 2, Payload: `{"new_email":"a2@example.com"}`},
+	}
+	for i, event := range events {
+		event.Position = uint64(i + 1)
+		event.Timestamp = base.Add(time.Duration(i) * time.Hour)
+		event.SchemaVersion = 1
+		event.Metadata = model.EventMetadata{CorrelationID: "corr-1", SourceService: "auth-service"}
+		if err := repo.SaveEvent(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestExportImportRoundTripPreservesVersions(t *testing.T) {
+	ctx := context.Background()
+	source := seedStore(t)
+
+	var file bytes.Buffer
+	exported, err := Export(ctx, source, Selection{}, &file)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if exported.Events != 3 || exported.Aggregates != 2 {
+		t.Fatalf("unexpected export result: %+v", exported)
+	}
+
+	// Hedefte başka bir stream zaten var: position'lar onun devamından atanır
+	target := repository.NewMemoryEventRepository()
+	if err := target.SaveEvent(&model.Event{ID: "x1", EventType: "user.created", AggregateID: "user-x", Version: 1, Position: 1, Payload: `{}`}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := file.Bytes()
+	imported, err := Import(ctx, target, bytes.NewReader(data), ImportOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if imported.Imported != 3 || imported.FirstPosition != 2 || imported.LastPosition != 4 {
+		t.Errorf("unexpected import result: %+v", imported)
+	}
+
+	events, _ := target.GetEvents(model.EventFilter{AggregateID: "user-a"})
+	if len(events) != 2 || events[1].Version != 2 || events[1].Metadata.CorrelationID != "corr-1" || !events[1].Timestamp.Equal(time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected versions, timestamps and metadata to be preserved, got %+v", events)
+	}
+
+	// Tekrar import edilen event'ler atlanır
+	again, err := Import(ctx, target, bytes.NewReader(data), ImportOptions{})
+	if err != nil || again.Imported != 0 || again.Skipped != 3 {
+		t.Errorf("expected re-import to skip all events, got %+v (%v)", again, err)
+	}
+
+	report, err := Verify(ctx, target, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !report.OK() || report.Aggregates != 2 || report.StoreEvents != 3 {
+		t.Errorf("expected verify to pass, got %+v", report)
+	}
+}
+
+func TestExportSelectsAggregatesAndTimeRange(t *testing.T) {
+	ctx := context.Background()
+	source := seedStore(t)
+
+	var byAggregate bytes.Buffer
+	result, err := Export(ctx, source, Selection{AggregateIDs: []string{"user-b"}}, &byAggregate)
+	if err != nil || result.Events != 1 {
+		t.Fatalf("expected one event of user-b, got %+v (%v)", result, err)
+	}
+
+	var byTime bytes.Buffer
+	selection := Selection{StartTime: time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)}
+	result, err = Export(ctx, source, selection, &byTime)
+	if err != nil || result.Events != 2 {
+		t.Fatalf("expected two events after 13:00, got %+v (%v)", result, err)
+	}
+
+	data := bytes.Clone(byTime.Bytes())
+
+	// Zaman aralığı export'u sadece kendi version aralığını doğrular
+	report, err := Verify(ctx, source, &byTime)
+	if err != nil || !report.OK() {
+		t.Errorf("expected partial export to verify, got %+v (%v)", report, err)
+	}
+
+	// user-a'nın version 1'i aralıkta değil: boş hedefe ancak AllowGaps ile yazılır
+	_, err = Import(ctx, repository.NewMemoryEventRepository(), bytes.NewReader(data), ImportOptions{})
+	var gap *VersionGapError
+	if !errors.As(err, &gap) || gap.AggregateID != "user-a" || gap.Version != 2 {
+		t.Fatalf("expected version gap on user-a, got %v", err)
+	}
+	imported, err := Import(ctx, repository.NewMemoryEventRepository(), bytes.NewReader(data), ImportOptions{AllowGaps: true})
+	if err != nil || imported.Imported != 2 {
+		t.Errorf("expected AllowGaps to import both events, got %+v (%v)", imported, err)
+	}
+}
+
+func TestVerifyDetectsDifferencesAndImportRejectsConflicts(t *testing.T) {
+	ctx := context.Background()
+	source := seedStore(t)
+
+	var file bytes.Buffer
+	if _, err := Export(ctx, source, Selection{}, &file); err != nil {
+		t.Fatal(err)
+	}
+	data := file.Bytes()
+
+	// Aynı aggregate'e farklı ID'li version 1 yazılmış hedef
+	target := repository.NewMemoryEventRepository()
+	if err := target.SaveEvent(&model.Event{ID: "other", EventType: "user.created", AggregateID: "user-a", Version: 1, Position: 1, Payload: `{}`}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Import(ctx, target, bytes.NewReader(data), ImportOptions{})
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) || conflict.AggregateID != "user-a" {
+		t.Fatalf("expected version conflict on user-a, got %v", err)
+	}
+
+	report, err := Verify(ctx, target, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() || len(report.Mismatches) != 2 {
+		t.Errorf("expected both aggregates to differ, got %+v", report)
+	}
+}
+
+func TestExportImportMovesPIIBetweenKeyStores(t *testing.T) {
+	ctx := context.Background()
+	source := privacy.NewStore(repository.NewMemoryEventRepository(), repository.NewMemoryKeyRepository(), privacy.DefaultFields())
+	for i, aggregateID := range []string{"user-a", "user-b"} {
+		event := &model.Event{ID: aggregateID + "-1", EventType: "user.created", AggregateID: aggregateID, Version: 1, Position: uint64(i + 1),
+			Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Payload: `{"email":"` + aggregateID + `@example.com","password_hash":"h"}`}
+		if err := source.SaveEvent(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := source.Forget("user-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	var file bytes.Buffer
+	if _, err := Export(ctx, source, Selection{}, &file); err != nil {
+		t.Fatal(err)
+	}
+	data := file.Bytes()
+
+	// Hedefin anahtarları ayrı; düz metin gelen PII hedefte yeniden şifrelenir
+	targetRepo := repository.NewMemoryEventRepository()
+	target := privacy.NewStore(targetRepo, repository.NewMemoryKeyRepository(), privacy.DefaultFields())
+	if _, err := Import(ctx, target, bytes.NewReader(data), ImportOptions{}); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	stored, _ := targetRepo.GetEvents(model.EventFilter{AggregateID: "user-a"})
+	if len(stored) != 1 || bytes.Contains([]byte(stored[0].Payload), []byte("user-a@example.com")) {
+		t.Errorf("expected email to be encrypted at rest in the target, got %+v", stored)
+	}
+	events, _ := target.GetEvents(model.EventFilter{AggregateID: "user-a"})
+	if len(events) != 1 || !bytes.Contains([]byte(events[0].Payload), []byte("user-a@example.com")) {
+		t.Errorf("expected imported email to be readable, got %+v", events)
+	}
+	events, _ = target.GetEvents(model.EventFilter{AggregateID: "user-b"})
+	if len(events) != 1 || !bytes.Contains([]byte(events[0].Payload), []byte(privacy.Redacted)) {
+		t.Errorf("expected forgotten aggregate to stay redacted, got %+v", events)
+	}
+
+	report, err := Verify(ctx, target, bytes.NewReader(data))
+	if err != nil || !report.OK() {
+		t.Errorf("expected verify to pass, got %+v (%v)", report, err)
+	}
+}
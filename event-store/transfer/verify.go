@@ -0,0 +1,187 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// AggregateDiff - Dosya ile store arasında farklı çıkan aggregate
+type AggregateDiff struct {
+	AggregateID string `json:"aggregate_id"`
+	FileEvents  int    `json:"file_events"`
+	StoreEvents int    `json:"store_events"`
+	FileHash    string `json:"file_hash"`
+	StoreHash   string `json:"store_hash"`
+}
+
+// VerifyReport - Karşılaştırma sonucu; Mismatches boşsa dosya ile store aynı
+type VerifyReport struct {
+	Aggregates  int             `json:"aggregates"`
+	FileEvents  uint64          `json:"file_events"`
+	StoreEvents uint64          `json:"store_events"`
+	Mismatches  []AggregateDiff `json:"mismatches"`
+}
+
+// OK - Sayılar ve tüm aggregate hash'leri eşleşiyor mu?
+func (r VerifyReport) OK() bool {
+	return len(r.Mismatches) == 0 && r.FileEvents == r.StoreEvents
+}
+
+// aggregateDigest - Bir aggregate'in event'lerinin version sırasıyla hash'i
+type aggregateDigest struct {
+	hash        hash.Hash
+	tenantID    string
+	count       int
+	minVersion  uint32
+	maxVersion  uint32
+	lastVersion uint32
+}
+
+func newAggregateDigest() *aggregateDigest {
+	return &aggregateDigest{hash: sha256.New()}
+}
+
+func (d *aggregateDigest) add(event *model.Event) error {
+	if d.count > 0 && event.Version <= d.lastVersion {
+		return fmt.Errorf("aggregate %s: version %d follows %d, events must be in version order",
+			event.AggregateID, event.Version, d.lastVersion)
+	}
+	if d.count == 0 {
+		d.minVersion = event.Version
+		d.tenantID = model.NormalizeTenantID(event.TenantID)
+	}
+	d.maxVersion = event.Version
+	d.lastVersion = event.Version
+	d.count++
+	return writeCanonical(d.hash, event)
+}
+
+func (d *aggregateDigest) sum() string {
+	return hex.EncodeToString(d.hash.Sum(nil))
+}
+
+// Verify - Dosyadaki her aggregate'i store'daki aynı version aralığıyla karşılaştırır
+// Position hash'e dahil değildir (import yeniden atar); payload anahtar sırasından bağımsız karşılaştırılır
+func Verify(ctx context.Context, store repository.EventStore, r io.Reader) (VerifyReport, error) {
+	var report VerifyReport
+	digests := make(map[string]*aggregateDigest)
+
+	decoder := json.NewDecoder(r)
+	for {
+		var event model.Event
+		err := decoder.Decode(&event)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to decode line %d: %w", report.FileEvents+1, err)
+		}
+		report.FileEvents++
+
+		digest, ok := digests[event.AggregateID]
+		if !ok {
+			digest = newAggregateDigest()
+			digests[event.AggregateID] = digest
+		}
+		if err := digest.add(&event); err != nil {
+			return report, err
+		}
+	}
+
+	aggregateIDs := make([]string, 0, len(digests))
+	for aggregateID := range digests {
+		aggregateIDs = append(aggregateIDs, aggregateID)
+	}
+	sort.Strings(aggregateIDs)
+	report.Aggregates = len(aggregateIDs)
+
+	for _, aggregateID := range aggregateIDs {
+		fileDigest := digests[aggregateID]
+		storeDigest := newAggregateDigest()
+
+		query := model.StreamQuery{
+			TenantID:    fileDigest.tenantID,
+			AggregateID: aggregateID,
+			FromVersion: fileDigest.minVersion,
+			ToVersion:   fileDigest.maxVersion,
+		}
+		if err := store.ReadStream(ctx, query, storeDigest.add); err != nil {
+			return report, fmt.Errorf("failed to read aggregate %s: %w", aggregateID, err)
+		}
+		report.StoreEvents += uint64(storeDigest.count)
+
+		if fileDigest.count != storeDigest.count || fileDigest.sum() != storeDigest.sum() {
+			report.Mismatches = append(report.Mismatches, AggregateDiff{
+				AggregateID: aggregateID,
+				FileEvents:  fileDigest.count,
+				StoreEvents: storeDigest.count,
+				FileHash:    fileDigest.sum(),
+				StoreHash:   storeDigest.sum(),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// writeCanonical - Event'in backend'den bağımsız gösterimi
+// Timestamp UTC'ye çevrilir, payload anahtarları sıralı ve boşluksuz yeniden yazılır (Postgres JSONB
+// payload'u yeniden biçimlendirdiği için); sayılar json.Number ile olduğu gibi korunur
+func writeCanonical(w io.Writer, event *model.Event) error {
+	payload, err := canonicalJSON(event.Payload)
+	if err != nil {
+		return fmt.Errorf("event %s: %w", event.ID, err)
+	}
+
+	fields := []string{
+		event.ID,
+		event.EventType,
+		event.AggregateID,
+		strconv.FormatUint(uint64(event.Version), 10),
+		event.Timestamp.UTC().Format(time.RFC3339Nano),
+		strconv.FormatUint(uint64(event.SchemaVersion), 10),
+		event.Metadata.CorrelationID,
+		event.Metadata.CausationID,
+		event.Metadata.ActorID,
+		event.Metadata.SourceService,
+		model.NormalizeTenantID(event.TenantID),
+		model.NormalizeAggregateType(event.AggregateType),
+		model.NormalizeCategory(event.Category, event.AggregateType),
+		payload,
+	}
+	for _, field := range fields {
+		// Uzunluk önekli: alan sınırları kayarak aynı hash'i üretemez
+		if _, err := fmt.Fprintf(w, "%d:%s;", len(field), field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func canonicalJSON(raw string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("invalid payload: %w", err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return string(data), nil
+}
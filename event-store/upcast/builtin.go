@@ -0,0 +1,36 @@
+package upcast
+
+import "fmt"
+
+// NewDefaultChain - Bilinen event şekli değişikliklerini içeren zincir
+// Yeni bir şema versiyonu eklendiğinde (schema/schemas/<type>.v<N+1>.json) vN -> vN+1 adımı buraya kaydedilir
+func NewDefaultChain() *Chain {
+	chain := NewChain()
+
+	mustRegister(chain, "user.created", LegacyVersion, userCreatedLegacyToV1)
+
+	return chain
+}
+
+func mustRegister(chain *Chain, eventType string, fromVersion uint16, fn Func) {
+	if err := chain.Register(eventType, fromVersion, fn); err != nil {
+		panic(err)
+	}
+}
+
+// userCreatedLegacyToV1 - İlk producer'lar user ID'yi "aggregate_id" yerine "id" alanında gönderiyordu
+func userCreatedLegacyToV1(payload map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := payload["aggregate_id"]; ok {
+		delete(payload, "id")
+		return payload, nil
+	}
+
+	id, ok := payload["id"].(string)
+	if !ok || id == "" {
+		return nil, fmt.Errorf("neither aggregate_id nor id is present")
+	}
+
+	payload["aggregate_id"] = id
+	delete(payload, "id")
+	return payload, nil
+}
@@ -0,0 +1,131 @@
+package upcast
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/eyupaydin41/event-store/model"
+)
+
+const (
+	// LegacyVersion - schema_version kolonundan önce yazılmış event'ler
+	LegacyVersion uint16 = 0
+	// FirstVersion - Schema registry'deki ilk versiyon; legacy event'ler için adım yoksa doğrudan bu versiyon kabul edilir
+	FirstVersion uint16 = 1
+)
+
+// Func - Tek adım: T tipindeki event'in vN payload'unu vN+1 şekline çevirir
+// Aldığı map'i değiştirip dönebilir; event store'daki satır değişmez
+type Func func(payload map[string]interface{}) (map[string]interface{}, error)
+
+// Chain - Event tipi ve kaynak versiyona göre kayıtlı upcaster'lar
+// Okunan event, kendi versiyonundan başlayıp art arda gelen adımlardan geçirilerek
+// o tip için kayıtlı en güncel şekle getirilir (v1 -> v2 -> v3 ...)
+type Chain struct {
+	mu    sync.RWMutex
+	steps map[string]map[uint16]Func
+}
+
+func NewChain() *Chain {
+	return &Chain{steps: make(map[string]map[uint16]Func)}
+}
+
+// Register - eventType'ın fromVersion -> fromVersion+1 adımını ekler
+// Aynı adım iki kez kaydedilemez; zincirde boşluk kalırsa upcast o versiyonda durur
+func (c *Chain) Register(eventType string, fromVersion uint16, fn Func) error {
+	if eventType == "" || fn == nil {
+		return fmt.Errorf("event type and upcaster function are required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.steps[eventType] == nil {
+		c.steps[eventType] = make(map[uint16]Func)
+	}
+	if _, exists := c.steps[eventType][fromVersion]; exists {
+		return fmt.Errorf("upcaster for %s v%d is already registered", eventType, fromVersion)
+	}
+	c.steps[eventType][fromVersion] = fn
+
+	return nil
+}
+
+// LatestVersion - Tipin upcast sonrası ulaştığı versiyon (adım yoksa FirstVersion)
+func (c *Chain) LatestVersion(eventType string) uint16 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	version := FirstVersion
+	for {
+		if _, ok := c.steps[eventType][version]; !ok {
+			return version
+		}
+		version++
+	}
+}
+
+// Upcast - Event'i tipinin en güncel şekline getirir
+// Değişiklik gerekmiyorsa aynı pointer döner, aksi halde payload'u ve SchemaVersion'ı
+// güncellenmiş bir kopya döner (orijinal event değişmez)
+func (c *Chain) Upcast(event *model.Event) (*model.Event, error) {
+	c.mu.RLock()
+	steps := c.steps[event.EventType]
+	c.mu.RUnlock()
+
+	version := event.SchemaVersion
+	var payload map[string]interface{}
+
+	for {
+		step, ok := steps[version]
+		if !ok {
+			if version == LegacyVersion {
+				version = FirstVersion
+				continue
+			}
+			break
+		}
+
+		if payload == nil {
+			if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal payload of event %s: %w", event.ID, err)
+			}
+		}
+
+		next, err := step(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upcast %s v%d (event %s): %w", event.EventType, version, event.ID, err)
+		}
+		payload = next
+		version++
+	}
+
+	if version == event.SchemaVersion {
+		return event, nil
+	}
+
+	upcasted := *event
+	upcasted.SchemaVersion = version
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal upcasted payload of event %s: %w", event.ID, err)
+		}
+		upcasted.Payload = string(data)
+	}
+
+	return &upcasted, nil
+}
+
+// UpcastAll - Event listesini yerinde günceller
+func (c *Chain) UpcastAll(events []*model.Event) error {
+	for i, event := range events {
+		upcasted, err := c.Upcast(event)
+		if err != nil {
+			return err
+		}
+		events[i] = upcasted
+	}
+	return nil
+}
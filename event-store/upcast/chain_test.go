@@ -0,0 +1,117 @@
+package upcast
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/reducer"
+	"github.com/eyupaydin41/event-store/repository"
+	"github.com/eyupaydin41/event-store/schema"
+	"github.com/eyupaydin41/event-store/service"
+)
+
+func TestChainAppliesStepsInOrder(t *testing.T) {
+	chain := NewChain()
+	rename := func(from, to string) Func {
+		return func(payload map[string]interface{}) (map[string]interface{}, error) {
+			payload[to] = payload[from]
+			delete(payload, from)
+			return payload, nil
+		}
+	}
+	if err := chain.Register("user.email.changed", 1, rename("new_email", "email")); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.Register("user.email.changed", 2, rename("email", "address")); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.Register("user.email.changed", 1, rename("a", "b")); err == nil {
+		t.Error("expected duplicate step to be rejected")
+	}
+
+	if latest := chain.LatestVersion("user.email.changed"); latest != 3 {
+		t.Fatalf("expected latest version 3, got %d", latest)
+	}
+
+	original := &model.Event{ID: "e-1", EventType: "user.email.changed", SchemaVersion: 1, Payload: `{"new_email":"b@example.com"}`}
+	upcasted, err := chain.Upcast(original)
+	if err != nil {
+		t.Fatalf("Upcast: %v", err)
+	}
+	if upcasted.SchemaVersion != 3 || upcasted.Payload != `{"address":"b@example.com"}` {
+		t.Errorf("unexpected upcast result: v%d %s", upcasted.SchemaVersion, upcasted.Payload)
+	}
+	if original.SchemaVersion != 1 || original.Payload != `{"new_email":"b@example.com"}` {
+		t.Errorf("original event was modified: v%d %s", original.SchemaVersion, original.Payload)
+	}
+
+	current := &model.Event{EventType: "user.email.changed", SchemaVersion: 3, Payload: `{"address":"c@example.com"}`}
+	if same, _ := chain.Upcast(current); same != current {
+		t.Error("expected an already current event to be returned as is")
+	}
+}
+
+func TestLegacyEventsAreReadInLatestShape(t *testing.T) {
+	repo := repository.NewMemoryEventRepository()
+	legacy := &model.Event{
+		ID:          "legacy-1",
+		EventType:   "user.created",
+		AggregateID: "user-1",
+		Payload:     `{"id":"user-1","email":"a@example.com","password_hash":"x"}`,
+		Timestamp:   time.Now(),
+		Version:     1,
+		Position:    1,
+	}
+	if err := repo.SaveEvent(legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewStore(repo, NewDefaultChain())
+
+	var read []*model.Event
+	err := store.ReadStream(context.Background(), model.StreamQuery{AggregateID: "user-1"}, func(event *model.Event) error {
+		read = append(read, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadStream: %v", err)
+	}
+	if len(read) != 1 || read[0].SchemaVersion != FirstVersion {
+		t.Fatalf("expected one event upcast to v%d, got %+v", FirstVersion, read)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(read[0].Payload), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["aggregate_id"] != "user-1" || payload["id"] != nil {
+		t.Errorf("expected id to be renamed to aggregate_id, got %v", payload)
+	}
+
+	registry, err := schema.LoadRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Validate(read[0].EventType, int(read[0].SchemaVersion), []byte(read[0].Payload)); err != nil {
+		t.Errorf("upcast payload does not match the latest schema: %v", err)
+	}
+
+	stored, err := repo.GetEvents(model.EventFilter{AggregateID: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored[0].SchemaVersion != LegacyVersion || stored[0].Payload != legacy.Payload {
+		t.Errorf("stored row was modified: v%d %s", stored[0].SchemaVersion, stored[0].Payload)
+	}
+
+	state, err := service.NewReplayService(store, reducer.NewDefaultRegistry()).ReplayState(model.DefaultTenantID, "user", "user-1")
+	if err != nil {
+		t.Fatalf("ReplayState: %v", err)
+	}
+	if aggregate := state.(*model.UserAggregate); aggregate.ID != "user-1" || aggregate.Email != "a@example.com" {
+		t.Errorf("unexpected replayed state: %+v", aggregate)
+	}
+}
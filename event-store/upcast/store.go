@@ -0,0 +1,59 @@
+package upcast
+
+import (
+	"context"
+
+	"github.com/eyupaydin41/event-store/model"
+	"github.com/eyupaydin41/event-store/repository"
+)
+
+// Store - EventStore'dan okunan event'leri upcast eden decorator
+// Servisler bu store üzerinden okuduğu için replay, snapshot, gRPC ve HTTP sadece güncel şekli görür;
+// yazma metodları olduğu gibi alttaki store'a gider, kayıtlı satırlar değişmez
+type Store struct {
+	repository.EventStore
+	chain *Chain
+}
+
+func NewStore(store repository.EventStore, chain *Chain) *Store {
+	return &Store{EventStore: store, chain: chain}
+}
+
+// Upcast - Tek bir event'i güncel şekle getirir (repository.Upcaster)
+func (s *Store) Upcast(event *model.Event) (*model.Event, error) {
+	return s.chain.Upcast(event)
+}
+
+func (s *Store) GetEvents(filter model.EventFilter) ([]*model.Event, error) {
+	events, err := s.EventStore.GetEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.chain.UpcastAll(events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Store) GetEventsAfterVersion(tenantID, aggregateID string, afterVersion uint32) ([]*model.Event, error) {
+	events, err := s.EventStore.GetEventsAfterVersion(tenantID, aggregateID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.chain.UpcastAll(events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Store) ReadStream(ctx context.Context, query model.StreamQuery, fn func(*model.Event) error) error {
+	return s.EventStore.ReadStream(ctx, query, func(event *model.Event) error {
+		upcasted, err := s.chain.Upcast(event)
+		if err != nil {
+			return err
+		}
+		return fn(upcasted)
+	})
+}
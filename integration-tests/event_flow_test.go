@@ -6,9 +6,11 @@ import (
 	"testing"
 	"time"
 
+	authDomain "github.com/eyupaydin41/auth-service/domain"
 	authEvent "github.com/eyupaydin41/auth-service/event"
-	authModel "github.com/eyupaydin41/auth-service/model"
+	"github.com/eyupaydin41/event-store/catalog"
 	"github.com/eyupaydin41/event-store/consumer"
+	"github.com/eyupaydin41/event-store/model"
 	"github.com/eyupaydin41/event-store/repository"
 	"github.com/eyupaydin41/event-store/service"
 	"github.com/google/uuid"
@@ -45,8 +47,9 @@ func TestEventFlow(t *testing.T) {
 	// Event-store'un service ve Consumer'ı
 	t.Log("Creating event-store components...")
 	eventRepo := repository.NewEventRepository(conn)
-	eventService := service.NewEventService(eventRepo)
-	eventConsumer := consumer.NewEventStoreConsumer(broker, "test-group", "user-events", eventService)
+	eventService := service.NewEventService(eventRepo, catalog.NewCatalog())
+	// Snapshot ve şema doğrulaması bu akışın parçası değil
+	eventConsumer := consumer.NewEventStoreConsumer(broker, "test-group", "user-events", eventService, nil, nil, consumer.SchemaModeOff, nil)
 	t.Log("Event-store Consumer created")
 
 	// Consumer'ı goroutine'de başlat
@@ -68,17 +71,22 @@ func TestEventFlow(t *testing.T) {
 	t.Log("Waiting for consumer to be ready...")
 	time.Sleep(5 * time.Second)
 
-	// Test verisi - Gerçek User modeli
+	// Test verisi - auth-service'in domain event'i
 	t.Log("Publishing user.created event via auth-service producer...")
-	testUser := authModel.User{
-		ID:    uuid.New().String(),
+	created := authDomain.UserCreatedEvent{
+		BaseEvent: authDomain.BaseEvent{
+			EventID:     uuid.New().String(),
+			AggregateID: uuid.New().String(),
+			Timestamp:   time.Now(),
+			Version:     1,
+		},
 		Email: "integration-test@example.com",
 	}
 
-	// Auth-service'in Publish metodunu kullan
-	producer.Publish("user.created", map[string]interface{}{
-		"id":    testUser.ID,
-		"email": testUser.Email,
+	// Auth-service'in Publish metodunu kullan (tenant Kafka header'ında gider)
+	producer.Publish(created.GetEventType(), created, authDomain.EventMetadata{
+		SourceService: "integration-test",
+		TenantID:      model.DefaultTenantID,
 	})
 
 	t.Log("Event published")
@@ -91,28 +99,26 @@ func TestEventFlow(t *testing.T) {
 	t.Log("Querying ClickHouse via event-store service...")
 
 	// Event count kontrolü
-	count, err := eventService.CountEvents()
+	count, err := eventService.CountEvents(model.DefaultTenantID)
 	require.NoError(t, err)
 	assert.Greater(t, count, uint64(0), "Should have at least 1 event")
 
 	// Aggregate ID ile eventi sorgula
-	events, err := eventService.GetEventsByAggregateID(testUser.ID, 0)
+	events, err := eventService.GetEventsByAggregateID(model.DefaultTenantID, created.AggregateID, 0)
 	require.NoError(t, err)
 	require.Len(t, events, 1, "Should have exactly 1 event for this user")
 
 	// Event detaylarını kontrol et
 	event := events[0]
 	assert.Equal(t, "user.created", event.EventType)
-	assert.Equal(t, testUser.ID, event.AggregateID)
+	assert.Equal(t, created.AggregateID, event.AggregateID)
+	assert.Equal(t, created.EventID, event.ID)
 
-	// Payload içindeki email'i kontrol et
+	// Payload envelope'un data kısmıdır; email'i kontrol et
 	var payload map[string]interface{}
 	err = json.Unmarshal([]byte(event.Payload), &payload)
 	require.NoError(t, err)
-
-	if data, ok := payload["data"].(map[string]interface{}); ok {
-		assert.Equal(t, testUser.Email, data["email"])
-	}
+	assert.Equal(t, created.Email, payload["email"])
 
 	t.Log("🎉 Integration Test PASSED!")
 	t.Log("   ✓ Auth-service Producer worked")
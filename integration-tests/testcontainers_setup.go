@@ -7,6 +7,7 @@ import (
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/eyupaydin41/event-store/config"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/kafka"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -79,30 +80,17 @@ func SetupClickHouse(ctx context.Context) (testcontainers.Container, driver.Conn
 		break
 	}
 
-	// Events tablosunu oluştur
-	if err := createEventsTable(ctx, conn); err != nil {
+	// Events tablosunu event-store'un kendi şemasıyla oluştur
+	if err := config.CreateEventTable(conn); err != nil {
 		return nil, nil, fmt.Errorf("failed to create events table: %w", err)
 	}
+	if err := config.AddEventColumns(conn); err != nil {
+		return nil, nil, fmt.Errorf("failed to add event columns: %w", err)
+	}
 
 	return container, conn, nil
 }
 
-// createEventsTable - events tablosunu oluşturur
-func createEventsTable(ctx context.Context, conn driver.Conn) error {
-	query := `
-		CREATE TABLE IF NOT EXISTS events (
-			id String,
-			event_type String,
-			aggregate_id String,
-			payload String,
-			timestamp DateTime64(3),
-			version UInt32
-		) ENGINE = MergeTree()
-		ORDER BY (timestamp, id)
-	`
-	return conn.Exec(ctx, query)
-}
-
 // SetupKafka - Kafka containerını başlatır ve broker adresini döner
 func SetupKafka(ctx context.Context) (testcontainers.Container, string, error) {
 	kafkaContainer, err := kafka.RunContainer(ctx)
@@ -9,6 +9,7 @@ import (
 	"github.com/eyupaydin41/query-service/service"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -25,8 +26,8 @@ func LoginHandler(authService *service.AuthService, producer *event.KafkaProduce
 			return
 		}
 
-		// 1. Auth projection'dan user'ı bul
-		authProj, err := authService.FindByEmail(req.Email)
+		// 1. Auth projection'dan user'ı bul (sadece isteğin tenant'ında)
+		authProj, err := authService.FindByEmail(tenantFrom(c), req.Email)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 			return
@@ -46,32 +47,37 @@ func LoginHandler(authService *service.AuthService, producer *event.KafkaProduce
 		}
 
 		// 4. JWT token oluştur
-		token, err := generateJWT(authProj.ID, authProj.Email)
+		token, err := generateJWT(authProj.ID, authProj.Email, authProj.TenantID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
 			return
 		}
 
-		// 5. Login event'ini Kafka'ya publish et
-		go publishLoginEvent(producer, authProj.ID, c.ClientIP(), c.Request.UserAgent())
+		// 5. Login event'ini Kafka'ya publish et (actor giriş yapan kullanıcı)
+		metadata := metadataFrom(c)
+		metadata.ActorID = authProj.ID
+		go publishLoginEvent(producer, authProj.ID, c.ClientIP(), c.Request.UserAgent(), metadata)
 
 		c.JSON(http.StatusOK, gin.H{
-			"token":   token,
-			"user_id": authProj.ID,
-			"email":   authProj.Email,
+			"token":     token,
+			"user_id":   authProj.ID,
+			"email":     authProj.Email,
+			"tenant_id": authProj.TenantID,
 		})
 	}
 }
 
 // generateJWT - JWT token oluşturur
-func generateJWT(userID, email string) (string, error) {
+// tenant_id claim'i auth-service'te isteğin tenant'ı olarak kullanılır
+func generateJWT(userID, email, tenantID string) (string, error) {
 	jwtSecret := os.Getenv("JWT_SECRET")
 
 	claims := jwt.MapClaims{
-		"user_id": userID,
-		"email":   email,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-		"iat":     time.Now().Unix(),
+		"user_id":   userID,
+		"email":     email,
+		"tenant_id": tenantID,
+		"exp":       time.Now().Add(time.Hour * 24).Unix(),
+		"iat":       time.Now().Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -79,8 +85,9 @@ func generateJWT(userID, email string) (string, error) {
 }
 
 // publishLoginEvent - Login event'ini Kafka'ya publish eder
-func publishLoginEvent(producer *event.KafkaProducer, userID, ipAddress, userAgent string) {
+func publishLoginEvent(producer *event.KafkaProducer, userID, ipAddress, userAgent string, metadata event.EventMetadata) {
 	loginEvent := event.UserLoginRecordedEvent{
+		EventID:     uuid.New().String(),
 		EventType:   "user.login.recorded",
 		AggregateID: userID,
 		Timestamp:   time.Now(),
@@ -89,5 +96,5 @@ func publishLoginEvent(producer *event.KafkaProducer, userID, ipAddress, userAge
 		UserAgent:   userAgent,
 	}
 
-	producer.Publish("user.login.recorded", loginEvent)
+	producer.Publish("user.login.recorded", loginEvent, metadata)
 }
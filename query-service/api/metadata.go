@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/eyupaydin41/query-service/event"
+	"github.com/eyupaydin41/query-service/model"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Bu dosya auth-service/api/metadata.go ile aynı tutulur; servisler ayrı Go modülleri ve ayrı Docker build
+// context'leri olduğu için ortak bir pakete taşınmaz. Farklar sadece event/model import'ları
+// (ve query-service'e özgü tenantFrom/RequireToken); birinde yapılan değişiklik diğerine de yapılmalıdır
+
+const (
+	CorrelationIDHeader = "X-Correlation-ID"
+	RequestIDHeader     = "X-Request-ID"
+	TenantHeader        = "X-Tenant-ID"
+
+	metadataKey = "event_metadata"
+)
+
+// tenantIDPattern - Event-store'un kabul ettiği tenant ID formatı
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// RequestMetadata - Her istek için event metadata'sını hazırlar
+// Correlation/request ID'leri header'dan alınır (yoksa üretilir) ve response'a yazılır;
+// geçerli bir Bearer token varsa actor token'daki kullanıcıdır. Tenant token'daki tenant_id claim'i
+// ya da X-Tenant-ID header'ıdır (yoksa default tenant); ikisi farklıysa istek 403 ile reddedilir
+func RequestMetadata(sourceService string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := tokenClaims(c.GetHeader("Authorization"))
+		tenantID, ok := requestTenant(c.GetHeader(TenantHeader), claims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "tenant does not match the token"})
+			return
+		}
+		if !tenantIDPattern.MatchString(tenantID) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + TenantHeader + " header"})
+			return
+		}
+
+		correlationID := c.GetHeader(CorrelationIDHeader)
+		if correlationID == "" {
+			correlationID = uuid.New().String()
+		}
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		c.Header(CorrelationIDHeader, correlationID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Set(metadataKey, event.EventMetadata{
+			CorrelationID: correlationID,
+			CausationID:   requestID,
+			ActorID:       claimString(claims, "user_id"),
+			SourceService: sourceService,
+			TenantID:      tenantID,
+		})
+
+		c.Next()
+	}
+}
+
+// metadataFrom - Middleware'in hazırladığı metadata (middleware yoksa sadece boş alanlar)
+func metadataFrom(c *gin.Context) event.EventMetadata {
+	metadata, _ := c.Get(metadataKey)
+	m, _ := metadata.(event.EventMetadata)
+	return m
+}
+
+// tenantFrom - İsteğin tenant'ı (middleware yoksa default tenant)
+func tenantFrom(c *gin.Context) string {
+	return model.NormalizeTenantID(metadataFrom(c).TenantID)
+}
+
+// RequireToken - Geçerli bir Bearer token ister; tenant header'ı tek başına başka bir
+// tenant'ın verisini okumaya yetmez (RequestMetadata'dan sonra çalışır)
+func RequireToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if metadataFrom(c).ActorID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "valid bearer token required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestTenant - Token'daki tenant önceliklidir; header farklı bir tenant istiyorsa ok=false
+func requestTenant(header string, claims jwt.MapClaims) (string, bool) {
+	claimed := claimString(claims, "tenant_id")
+	switch {
+	case claimed != "" && header != "" && claimed != header:
+		return "", false
+	case claimed != "":
+		return claimed, true
+	default:
+		return model.NormalizeTenantID(header), true
+	}
+}
+
+// tokenClaims - JWT doğrulanamazsa nil döner (actor ve tenant claim'i boş kalır)
+func tokenClaims(authorization string) jwt.MapClaims {
+	tokenString, ok := strings.CutPrefix(authorization, "Bearer ")
+	secret := os.Getenv("JWT_SECRET")
+	if !ok || tokenString == "" || secret == "" {
+		return nil
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil
+	}
+	return claims
+}
+
+func claimString(claims jwt.MapClaims, key string) string {
+	value, _ := claims[key].(string)
+	return value
+}
@@ -9,7 +9,7 @@ import (
 
 func GetUsersHandler(repo *repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		users, err := repo.GetAll()
+		users, err := repo.GetAll(tenantFrom(c))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch users"})
 			return
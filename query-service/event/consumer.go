@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/eyupaydin41/query-service/model"
 	"github.com/eyupaydin41/query-service/service"
 )
 
@@ -41,14 +42,24 @@ func (kc *KafkaConsumer) Start() {
 	for {
 		msg, err := kc.consumer.ReadMessage(-1)
 		if err == nil {
-			kc.handleEvent(msg.Value)
+			kc.handleEvent(msg.Value, tenantFromHeaders(msg.Headers))
 		} else {
 			log.Printf("consumer error: %v", err)
 		}
 	}
 }
 
-func (kc *KafkaConsumer) handleEvent(eventData []byte) {
+// tenantFromHeaders - tenant-id header'ı; göndermeyen (eski) producer'lar default tenant'tadır
+func tenantFromHeaders(headers []kafka.Header) string {
+	for _, header := range headers {
+		if header.Key == TenantHeader {
+			return model.NormalizeTenantID(string(header.Value))
+		}
+	}
+	return model.DefaultTenantID
+}
+
+func (kc *KafkaConsumer) handleEvent(eventData []byte, tenantID string) {
 	log.Println("✅ Received event:", string(eventData))
 	var envelope map[string]interface{}
 	if err := json.Unmarshal(eventData, &envelope); err != nil {
@@ -65,11 +76,11 @@ func (kc *KafkaConsumer) handleEvent(eventData []byte) {
 	switch eventType {
 	case "user.created":
 		if kc.authService != nil {
-			if err := kc.authService.HandleUserCreatedEvent(eventData); err != nil {
+			if err := kc.authService.HandleUserCreatedEvent(eventData, tenantID); err != nil {
 				log.Printf("failed to handle user.created event: %v", err)
 			}
 		}
-		kc.userService.HandleUserRegisteredEvent(eventData)
+		kc.userService.HandleUserRegisteredEvent(eventData, tenantID)
 
 	case "user.password.changed":
 		if kc.authService != nil {
@@ -2,8 +2,20 @@ package event
 
 import "time"
 
+// EventMetadata - Event'in hangi istek, kullanıcı ve servisten doğduğu
+// Payload'a girmez; envelope'ta ve event-store'da ayrı kolonlarda taşınır
+type EventMetadata struct {
+	CorrelationID string `json:"correlation_id"`
+	CausationID   string `json:"causation_id"`
+	ActorID       string `json:"actor_id"`
+	SourceService string `json:"source_service"`
+	// TenantID - Envelope'a girmez; Kafka'da tenant-id header'ında taşınır
+	TenantID string `json:"-"`
+}
+
 // UserLoginRecordedEvent - Login kaydedildiğinde publish edilir
 type UserLoginRecordedEvent struct {
+	EventID     string    `json:"event_id"`
 	EventType   string    `json:"event_type"`
 	AggregateID string    `json:"aggregate_id"`
 	Timestamp   time.Time `json:"timestamp"`
@@ -11,3 +23,7 @@ type UserLoginRecordedEvent struct {
 	IPAddress   string    `json:"ip_address"`
 	UserAgent   string    `json:"user_agent"`
 }
+
+func (e UserLoginRecordedEvent) GetEventID() string {
+	return e.EventID
+}
@@ -5,8 +5,12 @@ import (
 	"log"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
 )
 
+// TenantHeader - Mesajın tenant'ını taşıyan Kafka header'ı (event-store ile aynı)
+const TenantHeader = "tenant-id"
+
 type KafkaProducer struct {
 	producer *kafka.Producer
 	topic    string
@@ -24,16 +28,45 @@ func NewKafkaProducer(broker, topic string) *KafkaProducer {
 	}
 }
 
-func (kp *KafkaProducer) Publish(eventType string, payload interface{}) {
+// SchemaVersion - Publish edilen payload'ların event-store schema registry'deki versiyonu
+// Payload şekli geriye uyumsuz değiştiğinde event-store'a yeni şema eklenip bu değer artırılır
+const SchemaVersion = 1
+
+// AggregateType - Publish edilen event'lerin event-store'daki aggregate tipi (kullanıcı stream'leri)
+const AggregateType = "user"
+
+// identifiedEvent - Kendi event ID'sini taşıyan payload'lar
+type identifiedEvent interface {
+	GetEventID() string
+}
+
+// Publish - Event'i {"event_id", "type", "schema_version", "aggregate_type", "metadata", "data"} envelope'u ile publish eder
+// Tenant envelope'a girmez, tenant-id header'ında gider
+// Payload kendi ID'sini taşıyorsa o kullanılır, yoksa publish anında bir kez üretilir;
+// producer retry'larında mesaj aynı ID ile gider, event-store tekrarları ayıklar
+func (kp *KafkaProducer) Publish(eventType string, payload interface{}, metadata EventMetadata) {
+	eventID := ""
+	if e, ok := payload.(identifiedEvent); ok {
+		eventID = e.GetEventID()
+	}
+	if eventID == "" {
+		eventID = uuid.New().String()
+	}
+
 	data := map[string]interface{}{
-		"type": eventType,
-		"data": payload,
+		"event_id":       eventID,
+		"type":           eventType,
+		"schema_version": SchemaVersion,
+		"aggregate_type": AggregateType,
+		"metadata":       metadata,
+		"data":           payload,
 	}
 
 	value, _ := json.Marshal(data)
 	err := kp.producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &kp.topic, Partition: kafka.PartitionAny},
 		Value:          value,
+		Headers:        []kafka.Header{{Key: TenantHeader, Value: []byte(metadata.TenantID)}},
 	}, nil)
 
 	if err != nil {
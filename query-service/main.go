@@ -44,13 +44,14 @@ func main() {
 	producer := event.NewKafkaProducer(kafkaBroker, kafkaTopic)
 
 	r := gin.Default()
+	r.Use(api.RequestMetadata("query-service"))
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "OK"})
 	})
 
-	// QUERY endpoints
-	r.GET("/users", api.GetUsersHandler(userRepo))
+	// QUERY endpoints (kullanıcı listesi token'daki tenant'la sınırlı)
+	r.GET("/users", api.RequireToken(), api.GetUsersHandler(userRepo))
 	r.POST("/login", api.LoginHandler(authService, producer))
 
 	port := os.Getenv("PORT")
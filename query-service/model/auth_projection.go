@@ -3,9 +3,10 @@ package model
 import "time"
 
 // AuthProjection - Authentication için özel projection
-// Bu projection sadece login işlemi için gerekli bilgileri tutar
+// Bu projection sadece login işlemi için gerekli bilgileri tutar; email tenant içinde aranır
 type AuthProjection struct {
 	ID           string    `json:"id" db:"id"`
+	TenantID     string    `json:"tenant_id" db:"tenant_id" gorm:"index;not null;default:'default'"`
 	Email        string    `json:"email" db:"email"`
 	PasswordHash string    `json:"password_hash" db:"password_hash"`
 	Status       string    `json:"status" db:"status"`
@@ -5,6 +5,7 @@ import "time"
 type LoginHistory struct {
 	ID        string `gorm:"primaryKey"`
 	UserID    string `gorm:"index"`
+	TenantID  string `gorm:"index;not null;default:'default'"`
 	Email     string
 	IPAddress string
 	UserAgent string
@@ -0,0 +1,12 @@
+package model
+
+// DefaultTenantID - tenant_id taşımayan (eski) event'lerin ve tenant göndermeyen isteklerin tenant'ı
+const DefaultTenantID = "default"
+
+// NormalizeTenantID - Boş tenant default tenant'tır
+func NormalizeTenantID(tenantID string) string {
+	if tenantID == "" {
+		return DefaultTenantID
+	}
+	return tenantID
+}
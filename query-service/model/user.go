@@ -4,6 +4,7 @@ import "time"
 
 type User struct {
 	ID        string    `gorm:"primaryKey"`
+	TenantID  string    `gorm:"index;not null;default:'default'"`
 	Email     string    `gorm:"not null"`
 	Status    string    `gorm:"type:varchar(20);default:'active';not null"` // active, deleted, suspended
 	CreatedAt time.Time `gorm:"not null"`
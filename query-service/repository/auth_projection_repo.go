@@ -79,10 +79,11 @@ func (r *AuthProjectionRepository) UpdateStatus(id, status string) error {
 	return nil
 }
 
-// FindByEmail - Email'e göre auth projection'ı bulur
-func (r *AuthProjectionRepository) FindByEmail(email string) (*model.AuthProjection, error) {
+// FindByEmail - Tenant içinde email'e göre auth projection'ı bulur
+// Aynı email farklı tenant'larda ayrı kullanıcılardır
+func (r *AuthProjectionRepository) FindByEmail(tenantID, email string) (*model.AuthProjection, error) {
 	var auth model.AuthProjection
-	result := r.db.Where("email = ?", email).First(&auth)
+	result := r.db.Where("tenant_id = ? AND email = ?", tenantID, email).First(&auth)
 
 	if result.Error == gorm.ErrRecordNotFound {
 		return nil, fmt.Errorf("user not found with email: %s", email)
@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/eyupaydin41/query-service/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LoginHistoryRepository struct {
@@ -17,6 +18,7 @@ func NewLoginHistoryRepository(db *gorm.DB) *LoginHistoryRepository {
 	return &LoginHistoryRepository{db: db}
 }
 
+// Create - Login kaydını ekler; aynı ID zaten varsa (tekrar gelen event) sessizce atlar
 func (r *LoginHistoryRepository) Create(loginHistory *model.LoginHistory) error {
-	return r.db.Create(loginHistory).Error
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(loginHistory).Error
 }
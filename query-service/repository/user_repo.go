@@ -21,9 +21,10 @@ func (r *UserRepository) Create(user *model.User) error {
 	return r.db.Create(user).Error
 }
 
-func (r *UserRepository) GetAll() ([]model.User, error) {
+// GetAll - Tenant'ın tüm kullanıcıları
+func (r *UserRepository) GetAll(tenantID string) ([]model.User, error) {
 	var users []model.User
-	err := r.db.Find(&users).Error
+	err := r.db.Where("tenant_id = ?", tenantID).Find(&users).Error
 	return users, err
 }
 
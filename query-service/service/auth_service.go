@@ -22,7 +22,8 @@ func NewAuthService(authRepo *repository.AuthProjectionRepository) *AuthService
 }
 
 // HandleUserCreatedEvent - user.created event'ini işler
-func (s *AuthService) HandleUserCreatedEvent(eventData []byte) error {
+// Diğer event'ler aggregate ID ile güncellenir; tenant sadece oluşturulurken yazılır
+func (s *AuthService) HandleUserCreatedEvent(eventData []byte, tenantID string) error {
 	var envelope struct {
 		Type string          `json:"type"`
 		Data json.RawMessage `json:"data"`
@@ -47,6 +48,7 @@ func (s *AuthService) HandleUserCreatedEvent(eventData []byte) error {
 	// Auth projection oluştur
 	auth := &model.AuthProjection{
 		ID:           eventPayload.AggregateID,
+		TenantID:     model.NormalizeTenantID(tenantID),
 		Email:        eventPayload.Email,
 		PasswordHash: eventPayload.PasswordHash,
 		Status:       "active",
@@ -57,7 +59,7 @@ func (s *AuthService) HandleUserCreatedEvent(eventData []byte) error {
 		return fmt.Errorf("failed to upsert auth projection: %w", err)
 	}
 
-	log.Printf("Auth projection created: id=%s, email=%s", auth.ID, auth.Email)
+	log.Printf("Auth projection created: id=%s, tenant=%s, email=%s", auth.ID, auth.TenantID, auth.Email)
 	return nil
 }
 
@@ -144,7 +146,7 @@ func (s *AuthService) HandleUserDeactivatedEvent(eventData []byte) error {
 	return nil
 }
 
-// FindByEmail - Email'e göre auth projection bulur
-func (s *AuthService) FindByEmail(email string) (*model.AuthProjection, error) {
-	return s.authRepo.FindByEmail(email)
+// FindByEmail - Tenant içinde email'e göre auth projection bulur
+func (s *AuthService) FindByEmail(tenantID, email string) (*model.AuthProjection, error) {
+	return s.authRepo.FindByEmail(model.NormalizeTenantID(tenantID), email)
 }
@@ -22,7 +22,7 @@ func NewUserService(repo *repository.UserRepository, loginHistoryRepo *repositor
 	}
 }
 
-func (s *UserService) HandleUserRegisteredEvent(eventData []byte) {
+func (s *UserService) HandleUserRegisteredEvent(eventData []byte, tenantID string) {
 	var envelope map[string]interface{}
 	if err := json.Unmarshal(eventData, &envelope); err != nil {
 		log.Println("failed to parse event envelope:", err)
@@ -51,6 +51,7 @@ func (s *UserService) HandleUserRegisteredEvent(eventData []byte) {
 
 	user := &model.User{
 		ID:        aggregateID,
+		TenantID:  model.NormalizeTenantID(tenantID),
 		Email:     email,
 		Status:    "active",
 		CreatedAt: timestamp,
@@ -84,6 +85,12 @@ func (s *UserService) HandleUserLoggedInEvent(eventData []byte) {
 		return
 	}
 
+	// Event ID'yi login history ID'si olarak kullan; aynı event tekrar gelirse ikinci kayıt oluşmaz
+	loginID, _ := payload["event_id"].(string)
+	if loginID == "" {
+		loginID = uuid.New().String()
+	}
+
 	ipAddress, _ := dataField["ip_address"].(string)
 	userAgent, _ := dataField["user_agent"].(string)
 
@@ -95,8 +102,9 @@ func (s *UserService) HandleUserLoggedInEvent(eventData []byte) {
 	}
 
 	loginHistory := &model.LoginHistory{
-		ID:        uuid.New().String(),
+		ID:        loginID,
 		UserID:    aggregateID,
+		TenantID:  user.TenantID,
 		Email:     user.Email,
 		IPAddress: ipAddress,
 		UserAgent: userAgent,